@@ -0,0 +1,526 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: auth/v1/moderation.proto
+
+package authv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BanPlayerRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId *int64                 `protobuf:"varint,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	Reason *string                `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+	// issued_by identifies the moderator or system issuing the ban.
+	IssuedBy *string `protobuf:"bytes,3,opt,name=issued_by,json=issuedBy" json:"issued_by,omitempty"`
+	// expires_at is a unix timestamp; zero bans the player permanently.
+	ExpiresAt     *int64 `protobuf:"varint,4,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BanPlayerRequest) Reset() {
+	*x = BanPlayerRequest{}
+	mi := &file_auth_v1_moderation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BanPlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BanPlayerRequest) ProtoMessage() {}
+
+func (x *BanPlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_v1_moderation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BanPlayerRequest.ProtoReflect.Descriptor instead.
+func (*BanPlayerRequest) Descriptor() ([]byte, []int) {
+	return file_auth_v1_moderation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BanPlayerRequest) GetUserId() int64 {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return 0
+}
+
+func (x *BanPlayerRequest) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+func (x *BanPlayerRequest) GetIssuedBy() string {
+	if x != nil && x.IssuedBy != nil {
+		return *x.IssuedBy
+	}
+	return ""
+}
+
+func (x *BanPlayerRequest) GetExpiresAt() int64 {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return 0
+}
+
+type BanPlayerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BanId         *int64                 `protobuf:"varint,1,opt,name=ban_id,json=banId" json:"ban_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BanPlayerResponse) Reset() {
+	*x = BanPlayerResponse{}
+	mi := &file_auth_v1_moderation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BanPlayerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BanPlayerResponse) ProtoMessage() {}
+
+func (x *BanPlayerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_v1_moderation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BanPlayerResponse.ProtoReflect.Descriptor instead.
+func (*BanPlayerResponse) Descriptor() ([]byte, []int) {
+	return file_auth_v1_moderation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BanPlayerResponse) GetBanId() int64 {
+	if x != nil && x.BanId != nil {
+		return *x.BanId
+	}
+	return 0
+}
+
+type UnbanPlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        *int64                 `protobuf:"varint,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnbanPlayerRequest) Reset() {
+	*x = UnbanPlayerRequest{}
+	mi := &file_auth_v1_moderation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnbanPlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnbanPlayerRequest) ProtoMessage() {}
+
+func (x *UnbanPlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_v1_moderation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnbanPlayerRequest.ProtoReflect.Descriptor instead.
+func (*UnbanPlayerRequest) Descriptor() ([]byte, []int) {
+	return file_auth_v1_moderation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UnbanPlayerRequest) GetUserId() int64 {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return 0
+}
+
+type UnbanPlayerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnbanPlayerResponse) Reset() {
+	*x = UnbanPlayerResponse{}
+	mi := &file_auth_v1_moderation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnbanPlayerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnbanPlayerResponse) ProtoMessage() {}
+
+func (x *UnbanPlayerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_v1_moderation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnbanPlayerResponse.ProtoReflect.Descriptor instead.
+func (*UnbanPlayerResponse) Descriptor() ([]byte, []int) {
+	return file_auth_v1_moderation_proto_rawDescGZIP(), []int{3}
+}
+
+type ListAuditLogRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId *int64                 `protobuf:"varint,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	// from and to are unix timestamps; a zero to means now.
+	From *int64 `protobuf:"varint,2,opt,name=from" json:"from,omitempty"`
+	To   *int64 `protobuf:"varint,3,opt,name=to" json:"to,omitempty"`
+	// limit caps the number of entries returned, 100 by default and at most
+	// 1000.
+	Limit         *int32 `protobuf:"varint,4,opt,name=limit" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogRequest) Reset() {
+	*x = ListAuditLogRequest{}
+	mi := &file_auth_v1_moderation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogRequest) ProtoMessage() {}
+
+func (x *ListAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_v1_moderation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_auth_v1_moderation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListAuditLogRequest) GetUserId() int64 {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return 0
+}
+
+func (x *ListAuditLogRequest) GetFrom() int64 {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return 0
+}
+
+func (x *ListAuditLogRequest) GetTo() int64 {
+	if x != nil && x.To != nil {
+		return *x.To
+	}
+	return 0
+}
+
+func (x *ListAuditLogRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+// AuditEntry is a security-relevant action of a player. ip and user_agent
+// describe the client that made the request.
+type AuditEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	UserId    *int64                 `protobuf:"varint,2,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	Event     *string                `protobuf:"bytes,3,opt,name=event" json:"event,omitempty"`
+	Ip        *string                `protobuf:"bytes,4,opt,name=ip" json:"ip,omitempty"`
+	UserAgent *string                `protobuf:"bytes,5,opt,name=user_agent,json=userAgent" json:"user_agent,omitempty"`
+	// details holds event specific fields as a JSON object.
+	Details       *string `protobuf:"bytes,6,opt,name=details" json:"details,omitempty"`
+	CreatedAt     *int64  `protobuf:"varint,7,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_auth_v1_moderation_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_v1_moderation_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_auth_v1_moderation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AuditEntry) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetUserId() int64 {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetEvent() string {
+	if x != nil && x.Event != nil {
+		return *x.Event
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetIp() string {
+	if x != nil && x.Ip != nil {
+		return *x.Ip
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetUserAgent() string {
+	if x != nil && x.UserAgent != nil {
+		return *x.UserAgent
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetDetails() string {
+	if x != nil && x.Details != nil {
+		return *x.Details
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetCreatedAt() int64 {
+	if x != nil && x.CreatedAt != nil {
+		return *x.CreatedAt
+	}
+	return 0
+}
+
+type ListAuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogResponse) Reset() {
+	*x = ListAuditLogResponse{}
+	mi := &file_auth_v1_moderation_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogResponse) ProtoMessage() {}
+
+func (x *ListAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_v1_moderation_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_auth_v1_moderation_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListAuditLogResponse) GetEntries() []*AuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+var File_auth_v1_moderation_proto protoreflect.FileDescriptor
+
+const file_auth_v1_moderation_proto_rawDesc = "" +
+	"\n" +
+	"\x18auth/v1/moderation.proto\x12\aauth.v1\"\x7f\n" +
+	"\x10BanPlayerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1b\n" +
+	"\tissued_by\x18\x03 \x01(\tR\bissuedBy\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\"*\n" +
+	"\x11BanPlayerResponse\x12\x15\n" +
+	"\x06ban_id\x18\x01 \x01(\x03R\x05banId\"-\n" +
+	"\x12UnbanPlayerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"\x15\n" +
+	"\x13UnbanPlayerResponse\"h\n" +
+	"\x13ListAuditLogRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\xb3\x01\n" +
+	"\n" +
+	"AuditEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x12\x0e\n" +
+	"\x02ip\x18\x04 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x05 \x01(\tR\tuserAgent\x12\x18\n" +
+	"\adetails\x18\x06 \x01(\tR\adetails\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\"E\n" +
+	"\x14ListAuditLogResponse\x12-\n" +
+	"\aentries\x18\x01 \x03(\v2\x13.auth.v1.AuditEntryR\aentries2\xee\x01\n" +
+	"\x11ModerationService\x12B\n" +
+	"\tBanPlayer\x12\x19.auth.v1.BanPlayerRequest\x1a\x1a.auth.v1.BanPlayerResponse\x12H\n" +
+	"\vUnbanPlayer\x12\x1b.auth.v1.UnbanPlayerRequest\x1a\x1c.auth.v1.UnbanPlayerResponse\x12K\n" +
+	"\fListAuditLog\x12\x1c.auth.v1.ListAuditLogRequest\x1a\x1d.auth.v1.ListAuditLogResponseB$Z\"go-game-backend/gen/auth/v1;authv1b\beditionsp\xe8\a"
+
+var (
+	file_auth_v1_moderation_proto_rawDescOnce sync.Once
+	file_auth_v1_moderation_proto_rawDescData []byte
+)
+
+func file_auth_v1_moderation_proto_rawDescGZIP() []byte {
+	file_auth_v1_moderation_proto_rawDescOnce.Do(func() {
+		file_auth_v1_moderation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_auth_v1_moderation_proto_rawDesc), len(file_auth_v1_moderation_proto_rawDesc)))
+	})
+	return file_auth_v1_moderation_proto_rawDescData
+}
+
+var file_auth_v1_moderation_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_auth_v1_moderation_proto_goTypes = []any{
+	(*BanPlayerRequest)(nil),     // 0: auth.v1.BanPlayerRequest
+	(*BanPlayerResponse)(nil),    // 1: auth.v1.BanPlayerResponse
+	(*UnbanPlayerRequest)(nil),   // 2: auth.v1.UnbanPlayerRequest
+	(*UnbanPlayerResponse)(nil),  // 3: auth.v1.UnbanPlayerResponse
+	(*ListAuditLogRequest)(nil),  // 4: auth.v1.ListAuditLogRequest
+	(*AuditEntry)(nil),           // 5: auth.v1.AuditEntry
+	(*ListAuditLogResponse)(nil), // 6: auth.v1.ListAuditLogResponse
+}
+var file_auth_v1_moderation_proto_depIdxs = []int32{
+	5, // 0: auth.v1.ListAuditLogResponse.entries:type_name -> auth.v1.AuditEntry
+	0, // 1: auth.v1.ModerationService.BanPlayer:input_type -> auth.v1.BanPlayerRequest
+	2, // 2: auth.v1.ModerationService.UnbanPlayer:input_type -> auth.v1.UnbanPlayerRequest
+	4, // 3: auth.v1.ModerationService.ListAuditLog:input_type -> auth.v1.ListAuditLogRequest
+	1, // 4: auth.v1.ModerationService.BanPlayer:output_type -> auth.v1.BanPlayerResponse
+	3, // 5: auth.v1.ModerationService.UnbanPlayer:output_type -> auth.v1.UnbanPlayerResponse
+	6, // 6: auth.v1.ModerationService.ListAuditLog:output_type -> auth.v1.ListAuditLogResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_auth_v1_moderation_proto_init() }
+func file_auth_v1_moderation_proto_init() {
+	if File_auth_v1_moderation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_v1_moderation_proto_rawDesc), len(file_auth_v1_moderation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_auth_v1_moderation_proto_goTypes,
+		DependencyIndexes: file_auth_v1_moderation_proto_depIdxs,
+		MessageInfos:      file_auth_v1_moderation_proto_msgTypes,
+	}.Build()
+	File_auth_v1_moderation_proto = out.File
+	file_auth_v1_moderation_proto_goTypes = nil
+	file_auth_v1_moderation_proto_depIdxs = nil
+}
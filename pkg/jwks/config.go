@@ -0,0 +1,38 @@
+package jwks
+
+import "time"
+
+// Config describes the keys used to sign and verify tokens.
+type Config struct {
+	// Algorithm and Secret configure a single symmetric key (e.g. HS256).
+	// They are used only when Keys is empty.
+	Algorithm string `yaml:"algorithm"`
+	Secret    string `yaml:"secret"`
+	// Keys lists asymmetric keys in PEM format. New tokens are signed with the
+	// most recently activated key, and every key that is not retired is
+	// accepted for verification and published in the key set.
+	Keys []KeyConfig `yaml:"keys"`
+}
+
+// KeyConfig describes a single asymmetric key and its rotation schedule.
+type KeyConfig struct {
+	// ID is published as the "kid" of the key.
+	ID string `yaml:"id"`
+	// Algorithm overrides the algorithm inferred from the key type
+	// (RS256 for RSA, ES256/ES384/ES512 for EC, EdDSA for Ed25519).
+	Algorithm      string `yaml:"algorithm"`
+	PrivateKeyFile string `yaml:"private-key-file"`
+	// ActiveFrom is the time the key starts signing tokens. Keys are
+	// published before that so verifiers can pick them up in advance.
+	ActiveFrom time.Time `yaml:"active-from"`
+	// RetireAt is the time the key is withdrawn from the key set. It should
+	// be at least one access token TTL after the next key becomes active.
+	RetireAt time.Time `yaml:"retire-at"`
+}
+
+// RemoteConfig describes a JWKS endpoint to fetch verification keys from.
+type RemoteConfig struct {
+	URL string `yaml:"url"`
+	// RefreshInterval is the minimal interval between key set refreshes.
+	RefreshInterval time.Duration `yaml:"refresh-interval"`
+}
@@ -0,0 +1,4 @@
+// Package jwks manages the keys used to sign and verify JSON Web Tokens:
+// loading PEM keys, picking the signing key according to a rotation
+// schedule and fetching published key sets from remote JWKS endpoints.
+package jwks
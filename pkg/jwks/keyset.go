@@ -0,0 +1,189 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// ErrNoSigningKey is returned when no configured key is active at the
+// requested time.
+var ErrNoSigningKey = errors.New("no active signing key")
+
+const symmetricKeyID = "default"
+
+type scheduledKey struct {
+	key        jwk.Key
+	activeFrom time.Time
+	retireAt   time.Time
+}
+
+func (k scheduledKey) retired(now time.Time) bool {
+	return !k.retireAt.IsZero() && !now.Before(k.retireAt)
+}
+
+// KeySet holds the configured signing keys together with their rotation
+// schedule.
+type KeySet struct {
+	keys      []scheduledKey
+	symmetric bool
+}
+
+// Load reads the keys described by cfg.
+func Load(cfg *Config) (*KeySet, error) {
+	if len(cfg.Keys) == 0 {
+		return loadSymmetric(cfg)
+	}
+
+	ks := &KeySet{keys: make([]scheduledKey, 0, len(cfg.Keys))}
+	for _, kc := range cfg.Keys {
+		key, err := loadPrivateKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("load key '%s': %w", kc.ID, err)
+		}
+		ks.keys = append(ks.keys, scheduledKey{
+			key:        key,
+			activeFrom: kc.ActiveFrom,
+			retireAt:   kc.RetireAt,
+		})
+	}
+	return ks, nil
+}
+
+func loadSymmetric(cfg *Config) (*KeySet, error) {
+	if cfg.Secret == "" {
+		return nil, errors.New("neither keys nor secret configured")
+	}
+	key, err := jwk.FromRaw([]byte(cfg.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("create symmetric key: %w", err)
+	}
+	if err := setKeyMeta(key, symmetricKeyID, cfg.Algorithm); err != nil {
+		return nil, err
+	}
+	return &KeySet{keys: []scheduledKey{{key: key}}, symmetric: true}, nil
+}
+
+func loadPrivateKey(kc KeyConfig) (jwk.Key, error) {
+	if kc.ID == "" {
+		return nil, errors.New("key id is empty")
+	}
+
+	data, err := os.ReadFile(filepath.Clean(kc.PrivateKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("read private key file: %w", err)
+	}
+	key, err := jwk.ParseKey(data, jwk.WithPEM(true))
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	alg := kc.Algorithm
+	if alg == "" {
+		alg, err = inferAlgorithm(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := setKeyMeta(key, kc.ID, alg); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func inferAlgorithm(key jwk.Key) (string, error) {
+	var raw any
+	if err := key.Raw(&raw); err != nil {
+		return "", fmt.Errorf("get raw key: %w", err)
+	}
+
+	switch k := raw.(type) {
+	case *rsa.PrivateKey:
+		return jwa.RS256.String(), nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jwa.ES256.String(), nil
+		case elliptic.P384():
+			return jwa.ES384.String(), nil
+		case elliptic.P521():
+			return jwa.ES512.String(), nil
+		}
+		return "", fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+	case ed25519.PrivateKey:
+		return jwa.EdDSA.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", raw)
+	}
+}
+
+func setKeyMeta(key jwk.Key, id, alg string) error {
+	if err := key.Set(jwk.KeyIDKey, id); err != nil {
+		return fmt.Errorf("set key id: %w", err)
+	}
+	if err := key.Set(jwk.AlgorithmKey, jwa.SignatureAlgorithm(alg)); err != nil {
+		return fmt.Errorf("set key algorithm: %w", err)
+	}
+	return nil
+}
+
+// SigningKey returns the most recently activated key that is not retired at
+// the given time.
+func (s *KeySet) SigningKey(now time.Time) (jwk.Key, error) {
+	var current *scheduledKey
+	for i, k := range s.keys {
+		if now.Before(k.activeFrom) || k.retired(now) {
+			continue
+		}
+		if current == nil || k.activeFrom.After(current.activeFrom) {
+			current = &s.keys[i]
+		}
+	}
+	if current == nil {
+		return nil, ErrNoSigningKey
+	}
+	return current.key, nil
+}
+
+// KeySet returns the keys currently accepted for verification. Asymmetric
+// keys are returned without their private part.
+func (s *KeySet) KeySet(_ context.Context) (jwk.Set, error) {
+	set := jwk.NewSet()
+	now := time.Now()
+	for _, k := range s.keys {
+		if k.retired(now) {
+			continue
+		}
+		if err := set.AddKey(k.key); err != nil {
+			return nil, fmt.Errorf("add key: %w", err)
+		}
+	}
+	if s.symmetric {
+		return set, nil
+	}
+
+	public, err := jwk.PublicSetOf(set)
+	if err != nil {
+		return nil, fmt.Errorf("public key set: %w", err)
+	}
+	return public, nil
+}
+
+// PublicKeySet returns the keys suitable for publishing as a JWKS document.
+// Symmetric keys are never published.
+func (s *KeySet) PublicKeySet(ctx context.Context) (jwk.Set, error) {
+	if s.symmetric {
+		return jwk.NewSet(), nil
+	}
+	return s.KeySet(ctx)
+}
@@ -0,0 +1,80 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+func writeECKey(t *testing.T, dir, name string) string {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return path
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	ks, err := Load(&Config{Keys: []KeyConfig{
+		{ID: "old", PrivateKeyFile: writeECKey(t, dir, "old.pem"), ActiveFrom: now.Add(-48 * time.Hour)},
+		{ID: "new", PrivateKeyFile: writeECKey(t, dir, "new.pem"), ActiveFrom: now.Add(-time.Hour)},
+		{ID: "next", PrivateKeyFile: writeECKey(t, dir, "next.pem"), ActiveFrom: now.Add(time.Hour)},
+	}})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	key, err := ks.SigningKey(now)
+	if err != nil {
+		t.Fatalf("signing key: %v", err)
+	}
+	if key.KeyID() != "new" {
+		t.Fatalf("signing kid = %q, want %q", key.KeyID(), "new")
+	}
+
+	if _, err := ks.SigningKey(now.Add(-72 * time.Hour)); err == nil {
+		t.Fatal("expected no signing key before the first activation")
+	}
+
+	token, err := jwt.NewBuilder().Subject("42").Build()
+	if err != nil {
+		t.Fatalf("build token: %v", err)
+	}
+	signed, err := jwt.Sign(token, jwt.WithKey(key.Algorithm(), key))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	public, err := ks.PublicKeySet(context.Background())
+	if err != nil {
+		t.Fatalf("public key set: %v", err)
+	}
+	if public.Len() != 3 {
+		t.Fatalf("published %d keys, want 3", public.Len())
+	}
+	if _, err := jwt.Parse(signed, jwt.WithKeySet(public)); err != nil {
+		t.Fatalf("verify with published keys: %v", err)
+	}
+}
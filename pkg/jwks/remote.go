@@ -0,0 +1,33 @@
+package jwks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Remote fetches and caches the key set published at a JWKS endpoint.
+type Remote struct {
+	cache *jwk.Cache
+	url   string
+}
+
+// NewRemote creates a Remote key set. The cache is refreshed in the
+// background until ctx is done.
+func NewRemote(ctx context.Context, cfg *RemoteConfig) (*Remote, error) {
+	cache := jwk.NewCache(ctx)
+	if err := cache.Register(cfg.URL, jwk.WithMinRefreshInterval(cfg.RefreshInterval)); err != nil {
+		return nil, fmt.Errorf("register jwks url: %w", err)
+	}
+	return &Remote{cache: cache, url: cfg.URL}, nil
+}
+
+// KeySet returns the cached key set, fetching it on first use.
+func (r *Remote) KeySet(ctx context.Context) (jwk.Set, error) {
+	set, err := r.cache.Get(ctx, r.url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	return set, nil
+}
@@ -1,24 +1,39 @@
 // Package jwtfactory provides helpers for generating JSON Web Tokens (JWT)
-// using the jwtauth library.
+// signed with keys picked by a KeyProvider.
 package jwtfactory
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/go-chi/jwtauth/v5"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
 )
 
-// Factory wraps jwtauth.JWTAuth and provides helper methods for creating
-// JSON Web Tokens.
+// ErrInvalidToken is returned by Parse when a token is malformed, expired or
+// carries an invalid signature.
+var ErrInvalidToken = errors.New("invalid token")
+
+// KeyProvider returns the key that signs tokens issued at the given time and
+// the keys accepted when parsing tokens.
+type KeyProvider interface {
+	SigningKey(now time.Time) (jwk.Key, error)
+	KeySet(ctx context.Context) (jwk.Set, error)
+}
+
+// Factory signs JSON Web Tokens with the key supplied by a KeyProvider. The
+// key ID is written to the token header so verifiers can pick the matching
+// key during rotation.
 type Factory struct {
-	auth *jwtauth.JWTAuth
+	keys KeyProvider
 }
 
-// New creates a Factory using the provided JWTAuth instance.
-func New(auth *jwtauth.JWTAuth) *Factory {
+// New creates a Factory using the provided key provider.
+func New(keys KeyProvider) *Factory {
 	return &Factory{
-		auth: auth,
+		keys: keys,
 	}
 }
 
@@ -30,7 +45,13 @@ func (tf *Factory) Generate(
 	issueTime time.Time,
 	extraClaims map[string]any,
 ) (tkn string, expiresAt time.Time, err error) {
+	key, err := tf.keys.SigningKey(issueTime)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to pick signing key: %w", err)
+	}
+
 	expiresAt = issueTime.Add(ttl)
+	token := jwt.New()
 	claims := map[string]any{
 		"exp": expiresAt.Unix(),
 		"iat": issueTime.Unix(),
@@ -38,9 +59,30 @@ func (tf *Factory) Generate(
 	for k, v := range extraClaims {
 		claims[k] = v
 	}
-	_, tokenString, err := tf.auth.Encode(claims)
+	for k, v := range claims {
+		if err := token.Set(k, v); err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to set claim '%s': %w", k, err)
+		}
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(key.Algorithm(), key))
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to generate token: %w", err)
 	}
-	return tokenString, expiresAt, nil
+	return string(signed), expiresAt, nil
+}
+
+// Parse verifies the signature, expiration and issue time of a token and
+// returns its claims.
+func (tf *Factory) Parse(ctx context.Context, tkn string) (jwt.Token, error) {
+	keySet, err := tf.keys.KeySet(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key set: %w", err)
+	}
+
+	token, err := jwt.ParseString(tkn, jwt.WithKeySet(keySet), jwt.WithValidate(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return token, nil
 }
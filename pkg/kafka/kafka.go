@@ -11,7 +11,19 @@ import (
 type ForwarderConfig struct {
 	Brokers      []string      `yaml:"brokers"`
 	PollInterval time.Duration `yaml:"poll-interval"`
-	BatchSize    int32         `yaml:"batch-size"`
+	// NotifyPollInterval replaces PollInterval while the forwarder is woken
+	// up by notifications of added events.
+	NotifyPollInterval time.Duration `yaml:"notify-poll-interval"`
+	BatchSize          int32         `yaml:"batch-size"`
+	LeaseDuration      time.Duration `yaml:"lease-duration"`
+	// MaxAttempts is the number of failed attempts after which an event is
+	// moved to the dead-letter state.
+	MaxAttempts    int32         `yaml:"max-attempts"`
+	RetryBaseDelay time.Duration `yaml:"retry-base-delay"`
+	RetryMaxDelay  time.Duration `yaml:"retry-max-delay"`
+	// Pipeline makes the forwarder claim the next batch while the previous
+	// one is being published.
+	Pipeline bool `yaml:"pipeline"`
 }
 
 // ReaderConfig contains Kafka reader settings.
@@ -21,10 +33,19 @@ type ReaderConfig struct {
 	GroupID string   `yaml:"group-id"`
 }
 
-// NewWriter creates a kafka writer for the given brokers.
+// writerBatchTimeout bounds how long the writer waits for more messages
+// before sending a partial batch. Writes are synchronous, so every write would
+// otherwise wait for the default of one second.
+const writerBatchTimeout = 10 * time.Millisecond
+
+// NewWriter creates a kafka writer for the given brokers. Messages are
+// assigned to partitions by the hash of their key, so that messages sharing a
+// key are consumed in order.
 func NewWriter(brokers []string) *k.Writer {
 	return &k.Writer{
 		Addr:                   k.TCP(brokers...),
+		Balancer:               &k.Hash{},
+		BatchTimeout:           writerBatchTimeout,
 		AllowAutoTopicCreation: false,
 		RequiredAcks:           k.RequireAll,
 	}
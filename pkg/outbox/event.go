@@ -1,8 +1,27 @@
 package outbox
 
+import "time"
+
 // Event represents a message stored in the outbox table.
 type Event struct {
-	ID      int64
-	Topic   string
+	ID    int64
+	Topic string
+	// Key is the Kafka message key. Events with the same key are published
+	// to the same partition, in the order they were added.
+	Key     string
 	Payload []byte
+	Headers Headers
+	// Attempts is the number of failed attempts to publish the event.
+	Attempts int32
+}
+
+// DeadEvent is an event moved to the dead-letter state after failing to be
+// published too many times. It is not published until it is requeued.
+type DeadEvent struct {
+	ID        int64
+	Topic     string
+	Attempts  int32
+	LastError string
+	CreatedAt time.Time
+	DeadAt    time.Time
 }
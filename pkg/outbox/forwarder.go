@@ -2,54 +2,303 @@ package outbox
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"go-game-backend/pkg/logging"
+	"maps"
+	"os"
+	"slices"
 	"time"
 
+	kafkapkg "go-game-backend/pkg/kafka"
+
 	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
 )
 
-// Forwarder periodically sends events from the outbox to Kafka.
+// releaseTimeout bounds releasing claims once the forwarder stops.
+const releaseTimeout = 5 * time.Second
+
+// Writer publishes messages to Kafka. It is implemented by *kafka.Writer.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Store provides the outbox events to forward. It is implemented by
+// *Repository.
+type Store interface {
+	Claim(ctx context.Context, owner string, limit int32, lease time.Duration) ([]Event, error)
+	MarkProcessed(ctx context.Context, ids ...int64) error
+	MarkFailed(ctx context.Context, id int64, cause error, retryIn time.Duration, dead bool) error
+	ReleaseEvents(ctx context.Context, owner string, ids []int64) error
+	ReleaseClaims(ctx context.Context, owner string) error
+}
+
+// Waker wakes the forwarder up when events may have been added to the
+// outbox. It is implemented by *Listener.
+type Waker interface {
+	Wakeups() <-chan struct{}
+	Listening() bool
+}
+
+// Forwarder periodically sends events from the outbox to Kafka. Several
+// forwarders may run against the same outbox: each claims its batch with a
+// lease, and events leased by a forwarder that crashed are claimed again
+// once the lease runs out.
+//
+// The forwarder claims events as soon as its waker reports them added. While
+// the waker is listening it only polls every cfg.NotifyPollInterval, to pick
+// up events due for a retry and leases that ran out, and every
+// cfg.PollInterval otherwise.
+//
+// A batch is published with a single write and marked processed with a
+// single update. Batches are claimed back to back while they come full, and
+// with cfg.Pipeline set the next batch is claimed while the previous one is
+// being published.
+//
+// An event that fails to be published is retried with an exponential
+// backoff, holding back the later events of its topic with the same key, and
+// is moved to the dead-letter state after cfg.MaxAttempts attempts.
 type Forwarder struct {
-	store        *Repository
-	writer       *kafka.Writer
-	pollInterval time.Duration
-	batchSize    int32
+	store  Store
+	writer Writer
+	waker  Waker
+	owner  string
+	cfg    *kafkapkg.ForwarderConfig
+	logger *logging.ZapLogger
 }
 
-// NewForwarder creates a new Forwarder instance.
-func NewForwarder(store *Repository, writer *kafka.Writer, pollInterval time.Duration, batchSize int32) *Forwarder {
-	return &Forwarder{store: store, writer: writer, pollInterval: pollInterval, batchSize: batchSize}
+// NewForwarder creates a new Forwarder instance. The lease must be longer
+// than it takes to publish a batch, twice that with cfg.Pipeline set,
+// otherwise another forwarder may claim and publish the same events again.
+// The waker may be nil, in which case the forwarder only polls.
+func NewForwarder(store Store, writer Writer, waker Waker, cfg *kafkapkg.ForwarderConfig, logger *logging.ZapLogger) *Forwarder {
+	return &Forwarder{
+		store:  store,
+		writer: writer,
+		waker:  waker,
+		owner:  newOwnerID(),
+		cfg:    cfg,
+		logger: logger,
+	}
 }
 
 // Run starts the forwarder loop and blocks until the context is done.
 func (f *Forwarder) Run(ctx context.Context) {
-	ticker := time.NewTicker(f.pollInterval)
-	defer ticker.Stop()
+	defer f.releaseClaims(ctx)
+
+	if !f.cfg.Pipeline {
+		f.poll(ctx, f.publishBatch)
+		return
+	}
+
+	batches := make(chan []Event)
+	go func() {
+		defer close(batches)
+		f.poll(ctx, func(ctx context.Context, events []Event) {
+			select {
+			case batches <- events:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	for events := range batches {
+		f.publishBatch(ctx, events)
+	}
+}
+
+// poll claims batches of events and passes them to handle until ctx is done.
+// Batches are claimed back to back while they come full, otherwise once
+// woken up or once per poll interval.
+func (f *Forwarder) poll(ctx context.Context, handle func(context.Context, []Event)) {
+	var wakeups <-chan struct{}
+	if f.waker != nil {
+		wakeups = f.waker.Wakeups()
+	}
+	timer := time.NewTimer(f.pollInterval())
+	defer timer.Stop()
 
 	for {
+		events, err := f.store.Claim(ctx, f.owner, f.cfg.BatchSize, f.cfg.LeaseDuration)
+		if err != nil && ctx.Err() == nil {
+			f.logger.ErrorCtx(ctx, "claim outbox events", zap.Error(err))
+		}
+		if len(events) > 0 {
+			handle(ctx, events)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if len(events) == int(f.cfg.BatchSize) {
+			continue
+		}
+
+		timer.Reset(f.pollInterval())
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			_ = f.processBatch(ctx)
+		case <-timer.C:
+		case <-wakeups:
 		}
 	}
 }
 
-func (f *Forwarder) processBatch(ctx context.Context) error {
-	events, err := f.store.Fetch(ctx, f.batchSize)
-	if err != nil {
-		return err
+// pollInterval returns how long to wait for a wakeup before polling.
+func (f *Forwarder) pollInterval() time.Duration {
+	if f.waker != nil && f.waker.Listening() {
+		return f.cfg.NotifyPollInterval
+	}
+	return f.cfg.PollInterval
+}
+
+// publishBatch publishes the events and logs the error, if any.
+func (f *Forwarder) publishBatch(ctx context.Context, events []Event) {
+	if err := f.publish(ctx, events); err != nil && ctx.Err() == nil {
+		f.logger.ErrorCtx(ctx, "forward outbox events", zap.Error(err))
 	}
-	for _, e := range events {
-		msg := kafka.Message{Topic: e.Topic, Value: e.Payload}
-		if err := f.writer.WriteMessages(ctx, msg); err != nil {
-			// if publishing fails, stop processing to retry later
-			return fmt.Errorf("write messages: %w", err)
+}
+
+// publish writes the events to Kafka at once and records the outcome. The
+// events that failed are scheduled for a retry, and the later events of
+// their key are released to wait for it. Later events of a key that were
+// published despite an earlier failure are marked processed, as they cannot
+// be taken back.
+func (f *Forwarder) publish(ctx context.Context, events []Event) error {
+	msgs := make([]kafka.Message, len(events))
+	for i, e := range events {
+		msgs[i] = newMessage(e)
+	}
+
+	err := f.writer.WriteMessages(ctx, msgs...)
+	if ctx.Err() != nil {
+		// the claims are released once the forwarder stops
+		return ctx.Err()
+	}
+	writeErrs := writeErrors(err, len(events))
+
+	var published, skipped []int64
+	// keys with a failed event, whose later events must wait for it
+	failed := make(map[orderingKey]bool)
+	for i, e := range events {
+		if writeErrs == nil || writeErrs[i] == nil {
+			published = append(published, e.ID)
+			continue
+		}
+		ok := orderingKey{topic: e.Topic, key: e.Key}
+		if failed[ok] {
+			skipped = append(skipped, e.ID)
+			continue
+		}
+		failed[ok] = true
+		if err := f.markFailed(ctx, e, writeErrs[i]); err != nil {
+			return err
 		}
-		if err := f.store.MarkProcessed(ctx, e.ID); err != nil {
+	}
+
+	if len(published) > 0 {
+		if err := f.store.MarkProcessed(ctx, published...); err != nil {
 			return fmt.Errorf("mark processed: %w", err)
 		}
 	}
+	if len(skipped) > 0 {
+		if err := f.store.ReleaseEvents(ctx, f.owner, skipped); err != nil {
+			return fmt.Errorf("release skipped: %w", err)
+		}
+	}
 	return nil
 }
+
+// writeErrors returns the error of each of n messages written at once, or
+// nil if all of them were written.
+func writeErrors(err error, n int) kafka.WriteErrors {
+	if err == nil {
+		return nil
+	}
+	var errs kafka.WriteErrors
+	if errors.As(err, &errs) && len(errs) == n {
+		return errs
+	}
+	// the write failed as a whole
+	errs = make(kafka.WriteErrors, n)
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// orderingKey identifies the events that must be published in order.
+type orderingKey struct {
+	topic string
+	key   string
+}
+
+// newMessage returns the Kafka message publishing e.
+func newMessage(e Event) kafka.Message {
+	msg := kafka.Message{Topic: e.Topic, Value: e.Payload}
+	if e.Key != "" {
+		msg.Key = []byte(e.Key)
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.Headers)) {
+		msg.Headers = append(msg.Headers, kafka.Header{Key: name, Value: []byte(e.Headers[name])})
+	}
+	return msg
+}
+
+// markFailed records the failed attempt to publish e and schedules a retry,
+// or moves e to the dead-letter state if it has run out of attempts.
+func (f *Forwarder) markFailed(ctx context.Context, e Event, cause error) error {
+	attempt := e.Attempts + 1
+	dead := attempt >= f.cfg.MaxAttempts
+	retryIn := f.backoff(attempt)
+
+	fields := []zap.Field{
+		zap.Int64("event_id", e.ID),
+		zap.String("topic", e.Topic),
+		zap.String("key", e.Key),
+		zap.Int32("attempt", attempt),
+		zap.Error(cause),
+	}
+	if dead {
+		f.logger.ErrorCtx(ctx, "outbox event moved to dead letter", fields...)
+	} else {
+		f.logger.WarnCtx(ctx, "publish outbox event", append(fields, zap.Duration("retry_in", retryIn))...)
+	}
+
+	if err := f.store.MarkFailed(ctx, e.ID, cause, retryIn, dead); err != nil {
+		return fmt.Errorf("mark failed %d: %w", e.ID, err)
+	}
+	return nil
+}
+
+// backoff returns the delay before the next attempt after the given failed
+// attempt, doubling from RetryBaseDelay up to RetryMaxDelay.
+func (f *Forwarder) backoff(attempt int32) time.Duration {
+	delay := f.cfg.RetryBaseDelay
+	for i := int32(1); i < attempt && delay < f.cfg.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, f.cfg.RetryMaxDelay)
+}
+
+// releaseClaims gives up the leases of the forwarder, even if ctx is
+// already canceled.
+func (f *Forwarder) releaseClaims(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+	if err := f.store.ReleaseClaims(ctx, f.owner); err != nil {
+		f.logger.ErrorCtx(ctx, "release outbox claims", zap.Error(err))
+	}
+}
+
+// newOwnerID returns an identifier unique to this forwarder instance.
+func newOwnerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "forwarder"
+	}
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,665 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/pkg/logging"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kafkapkg "go-game-backend/pkg/kafka"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// roundTrip stands in for the latency of a call to Kafka or PostgreSQL.
+const roundTrip = 500 * time.Microsecond
+
+// waitTimeout bounds how long tests wait for the forwarder.
+const waitTimeout = 5 * time.Second
+
+var errBroker = errors.New("broker unavailable")
+
+// standInWriter acknowledges every write after a round trip, or fails it
+// with err. With hang set, writes are reported on it and hang until their
+// context is done.
+type standInWriter struct {
+	err  error
+	hang chan struct{}
+}
+
+func (w standInWriter) WriteMessages(ctx context.Context, _ ...kafka.Message) error {
+	if w.hang != nil {
+		w.hang <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	time.Sleep(roundTrip)
+	return w.err
+}
+
+// memEvent is an event of memStore with the state of its outbox row.
+type memEvent struct {
+	Event
+	processedAt   time.Time
+	claimedBy     string
+	claimedUntil  time.Time
+	nextAttemptAt time.Time
+	dead          bool
+	lastError     string
+	pruned        bool
+}
+
+// memStore is an in-memory outbox claiming events by the rules of the
+// ClaimEvents query. Its clock can be moved forward with advance.
+type memStore struct {
+	mu       sync.Mutex
+	events   []*memEvent
+	first    int // index of the first event that may be unprocessed
+	offset   time.Duration
+	released []int64
+	prunes   int
+	pruneErr error
+
+	processed int
+	done      chan struct{} // closed once every event is processed
+	closed    bool
+	updated   chan struct{} // receives a value when events are processed
+}
+
+// newMemStore returns a store holding n events, each with a key of its own
+// so that none is held back by a batch being published.
+func newMemStore(n int) *memStore {
+	s := &memStore{done: make(chan struct{}), updated: make(chan struct{}, 1)}
+	for i := range n {
+		s.add("user-created", fmt.Sprint(i))
+	}
+	return s
+}
+
+// add adds an event to the outbox and returns its ID.
+func (s *memStore) add(topic, key string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := int64(len(s.events) + 1)
+	s.events = append(s.events, &memEvent{Event: Event{
+		ID:      id,
+		Topic:   topic,
+		Key:     key,
+		Payload: []byte(`{"user_id":1}`),
+		Headers: Headers{HeaderEventType: "user_created"},
+	}})
+	return id
+}
+
+func (s *memStore) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+// advance moves the clock of the store forward.
+func (s *memStore) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+// event returns a copy of the event with the given ID.
+func (s *memStore) event(id int64) memEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.events[id-1]
+}
+
+// ids returns the IDs of the events matching the filter.
+func (s *memStore) ids(match func(e *memEvent) bool) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []int64
+	for _, e := range s.events {
+		if match(e) {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
+}
+
+func (s *memStore) processedIDs() []int64 {
+	return s.ids(func(e *memEvent) bool { return !e.processedAt.IsZero() })
+}
+
+func (s *memStore) failedIDs() []int64 {
+	return s.ids(func(e *memEvent) bool { return e.Attempts > 0 })
+}
+
+// claimedIDs returns the IDs of the unprocessed events leased to a
+// forwarder.
+func (s *memStore) claimedIDs() []int64 {
+	return s.ids(func(e *memEvent) bool { return e.claimedBy != "" && e.processedAt.IsZero() })
+}
+
+func (s *memStore) releasedIDs() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.released)
+}
+
+// waitProcessed waits until the event with the given ID is processed.
+func (s *memStore) waitProcessed(t *testing.T, id int64) {
+	t.Helper()
+	timeout := time.After(waitTimeout)
+	for s.event(id).processedAt.IsZero() {
+		select {
+		case <-s.updated:
+		case <-timeout:
+			t.Fatalf("event %d not processed", id)
+		}
+	}
+}
+
+func (s *memStore) Claim(_ context.Context, owner string, limit int32, lease time.Duration) ([]Event, error) {
+	time.Sleep(roundTrip)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.first < len(s.events) && !s.events[s.first].processedAt.IsZero() {
+		s.first++
+	}
+
+	now := s.now()
+	var events []Event
+	// keys with an older event leased or waiting to be retried
+	held := make(map[orderingKey]bool)
+	for _, e := range s.events[s.first:] {
+		if len(events) == int(limit) {
+			break
+		}
+		if !e.processedAt.IsZero() || e.dead {
+			continue
+		}
+		ok := orderingKey{topic: e.Topic, key: e.Key}
+		if held[ok] {
+			continue
+		}
+		if !e.claimedUntil.Before(now) || e.nextAttemptAt.After(now) {
+			held[ok] = true
+			continue
+		}
+		e.claimedBy = owner
+		e.claimedUntil = now.Add(lease)
+		events = append(events, e.Event)
+	}
+	return events, nil
+}
+
+func (s *memStore) MarkProcessed(_ context.Context, ids ...int64) error {
+	time.Sleep(roundTrip)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		e := s.events[id-1]
+		e.processedAt = s.now()
+		e.Payload = nil
+	}
+	s.processed += len(ids)
+	if s.processed == len(s.events) && !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+	select {
+	case s.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *memStore) MarkFailed(_ context.Context, id int64, cause error, retryIn time.Duration, dead bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.events[id-1]
+	e.Attempts++
+	e.lastError = cause.Error()
+	e.nextAttemptAt = s.now().Add(retryIn)
+	e.dead = dead
+	e.claimedBy = ""
+	e.claimedUntil = time.Time{}
+	return nil
+}
+
+func (s *memStore) ReleaseEvents(_ context.Context, owner string, ids []int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		e := s.events[id-1]
+		if e.claimedBy == owner && e.processedAt.IsZero() {
+			e.claimedBy = ""
+			e.claimedUntil = time.Time{}
+			s.released = append(s.released, id)
+		}
+	}
+	return nil
+}
+
+func (s *memStore) ReleaseClaims(_ context.Context, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.events {
+		if e.claimedBy == owner && e.processedAt.IsZero() {
+			e.claimedBy = ""
+			e.claimedUntil = time.Time{}
+		}
+	}
+	return nil
+}
+
+// fakeWaker stands in for a Listener. Its wakeups are unbuffered, so that
+// wake returns once the forwarder received the wakeup.
+type fakeWaker struct {
+	wakeups   chan struct{}
+	listening atomic.Bool
+}
+
+func newFakeWaker(listening bool) *fakeWaker {
+	w := &fakeWaker{wakeups: make(chan struct{})}
+	w.listening.Store(listening)
+	return w
+}
+
+func (w *fakeWaker) Wakeups() <-chan struct{} { return w.wakeups }
+
+func (w *fakeWaker) Listening() bool { return w.listening.Load() }
+
+// wake wakes the forwarder up, failing the test if it does not wait for it.
+func (w *fakeWaker) wake(t *testing.T) {
+	t.Helper()
+	select {
+	case w.wakeups <- struct{}{}:
+	case <-time.After(waitTimeout):
+		t.Fatal("forwarder not waiting for a wakeup")
+	}
+}
+
+// newTestForwarder returns a forwarder of the store publishing with w.
+func newTestForwarder(s *memStore, w Writer, waker Waker, cfg *kafkapkg.ForwarderConfig) *Forwarder {
+	if cfg == nil {
+		cfg = &kafkapkg.ForwarderConfig{
+			PollInterval:   time.Hour,
+			BatchSize:      10,
+			LeaseDuration:  time.Minute,
+			MaxAttempts:    5,
+			RetryBaseDelay: time.Second,
+			RetryMaxDelay:  time.Minute,
+		}
+	}
+	return NewForwarder(s, w, waker, cfg, logging.NewNopLogger())
+}
+
+// run runs the forwarder until the returned function is called.
+func run(f *Forwarder) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		f.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+func TestPublish(t *testing.T) {
+	type event struct{ topic, key string }
+	cases := []struct {
+		name      string
+		events    []event
+		err       error
+		processed []int64
+		failed    []int64
+		released  []int64
+	}{
+		{
+			name:      "all written",
+			events:    []event{{"a", "1"}, {"a", "1"}, {"a", "2"}},
+			processed: []int64{1, 2, 3},
+		},
+		{
+			name:     "write failed as a whole",
+			events:   []event{{"a", "1"}, {"a", "1"}, {"a", "2"}},
+			err:      errBroker,
+			failed:   []int64{1, 3},
+			released: []int64{2},
+		},
+		{
+			name:      "partial write",
+			events:    []event{{"a", "1"}, {"a", "1"}, {"a", "2"}, {"a", "1"}},
+			err:       kafka.WriteErrors{errBroker, errBroker, nil, nil},
+			processed: []int64{3, 4},
+			failed:    []int64{1},
+			released:  []int64{2},
+		},
+		{
+			name:      "key held back per topic",
+			events:    []event{{"a", "1"}, {"b", "1"}, {"a", "1"}, {"b", "1"}},
+			err:       kafka.WriteErrors{errBroker, errBroker, errBroker, nil},
+			failed:    []int64{1, 2},
+			processed: []int64{4},
+			released:  []int64{3},
+		},
+		{
+			name:      "events without a key held back together",
+			events:    []event{{"a", ""}, {"a", ""}, {"a", "1"}},
+			err:       kafka.WriteErrors{errBroker, errBroker, nil},
+			processed: []int64{3},
+			failed:    []int64{1},
+			released:  []int64{2},
+		},
+		{
+			name:   "write errors not matching the messages",
+			events: []event{{"a", "1"}, {"a", "2"}},
+			err:    kafka.WriteErrors{errBroker},
+			failed: []int64{1, 2},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newMemStore(0)
+			for _, e := range tc.events {
+				store.add(e.topic, e.key)
+			}
+			f := newTestForwarder(store, standInWriter{err: tc.err}, nil, nil)
+			events, _ := store.Claim(ctx, f.owner, 10, time.Minute)
+			if len(events) != len(tc.events) {
+				t.Fatalf("claimed %d events, want %d", len(events), len(tc.events))
+			}
+
+			if err := f.publish(ctx, events); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+
+			if got := store.processedIDs(); !slices.Equal(got, tc.processed) {
+				t.Errorf("processed = %v, want %v", got, tc.processed)
+			}
+			if got := store.failedIDs(); !slices.Equal(got, tc.failed) {
+				t.Errorf("failed = %v, want %v", got, tc.failed)
+			}
+			if got := store.releasedIDs(); !slices.Equal(got, tc.released) {
+				t.Errorf("released = %v, want %v", got, tc.released)
+			}
+			if got := store.claimedIDs(); len(got) > 0 {
+				t.Errorf("still claimed = %v, want none", got)
+			}
+		})
+	}
+}
+
+func TestRunReleasesClaims(t *testing.T) {
+	store := newMemStore(0)
+	store.add("a", "1")
+	store.add("a", "2")
+	hang := make(chan struct{})
+	f := newTestForwarder(store, standInWriter{hang: hang}, nil, nil)
+
+	stop := run(f)
+	select {
+	case <-hang:
+	case <-time.After(waitTimeout):
+		t.Fatal("events not published")
+	}
+	if got := store.claimedIDs(); !slices.Equal(got, []int64{1, 2}) {
+		t.Fatalf("claimed = %v, want [1 2]", got)
+	}
+	stop()
+
+	if got := store.claimedIDs(); len(got) > 0 {
+		t.Errorf("claimed after stop = %v, want none", got)
+	}
+	if got := store.processedIDs(); len(got) > 0 {
+		t.Errorf("processed = %v, want none", got)
+	}
+	if got := store.failedIDs(); len(got) > 0 {
+		t.Errorf("failed = %v, want none", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	f := newTestForwarder(newMemStore(0), standInWriter{}, nil, &kafkapkg.ForwarderConfig{
+		RetryBaseDelay: time.Second,
+		RetryMaxDelay:  10 * time.Second,
+	})
+	cases := []struct {
+		attempt int32
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 5, want: 10 * time.Second},
+		{attempt: 100, want: 10 * time.Second},
+	}
+	for _, tc := range cases {
+		if got := f.backoff(tc.attempt); got != tc.want {
+			t.Errorf("backoff(%d) = %v, want %v", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestMarkFailedDeadLetters(t *testing.T) {
+	cases := []struct {
+		attempts int32
+		dead     bool
+	}{
+		{attempts: 0},
+		{attempts: 1},
+		{attempts: 2, dead: true},
+		{attempts: 5, dead: true},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("attempts=%d", tc.attempts), func(t *testing.T) {
+			ctx := context.Background()
+			store := newMemStore(0)
+			id := store.add("a", "1")
+			f := newTestForwarder(store, standInWriter{}, nil, &kafkapkg.ForwarderConfig{
+				MaxAttempts:    3,
+				RetryBaseDelay: time.Second,
+				RetryMaxDelay:  time.Minute,
+			})
+			e := store.event(id).Event
+			e.Attempts = tc.attempts
+
+			before := store.now()
+			if err := f.markFailed(ctx, e, errBroker); err != nil {
+				t.Fatalf("mark failed: %v", err)
+			}
+
+			got := store.event(id)
+			if got.dead != tc.dead {
+				t.Errorf("dead = %t, want %t", got.dead, tc.dead)
+			}
+			if got.lastError != errBroker.Error() {
+				t.Errorf("last error = %q, want %q", got.lastError, errBroker.Error())
+			}
+			if retryAt := before.Add(f.backoff(tc.attempts + 1)); got.nextAttemptAt.Before(retryAt) {
+				t.Errorf("next attempt at %v, want after %v", got.nextAttemptAt, retryAt)
+			}
+		})
+	}
+}
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore(0)
+	store.add("a", "1") // 1
+	store.add("a", "1") // 2
+	store.add("a", "2") // 3
+	store.add("b", "1") // 4
+
+	steps := []struct {
+		name  string
+		do    func()
+		owner string
+		limit int32
+		want  []int64
+	}{
+		{
+			name:  "first event of the key",
+			owner: "x",
+			limit: 1,
+			want:  []int64{1},
+		},
+		{
+			name:  "later event of a leased key held back",
+			owner: "y",
+			limit: 10,
+			want:  []int64{3, 4},
+		},
+		{
+			name: "later event of a key waiting to be retried held back",
+			do: func() {
+				_ = store.MarkFailed(ctx, 1, errBroker, time.Hour, false)
+			},
+			owner: "z",
+			limit: 10,
+		},
+		{
+			name:  "expired leases claimed again",
+			do:    func() { store.advance(2 * time.Minute) },
+			owner: "z",
+			limit: 10,
+			want:  []int64{3, 4},
+		},
+		{
+			name: "dead event not claimed nor holding back its key",
+			do: func() {
+				_ = store.MarkFailed(ctx, 1, errBroker, 0, true)
+			},
+			owner: "x",
+			limit: 10,
+			want:  []int64{2},
+		},
+		{
+			name: "released events claimed again",
+			do: func() {
+				_ = store.ReleaseClaims(ctx, "z")
+			},
+			owner: "y",
+			limit: 10,
+			want:  []int64{3, 4},
+		},
+	}
+	for _, step := range steps {
+		if step.do != nil {
+			step.do()
+		}
+		events, err := store.Claim(ctx, step.owner, step.limit, time.Minute)
+		if err != nil {
+			t.Fatalf("%s: claim: %v", step.name, err)
+		}
+		var got []int64
+		for _, e := range events {
+			got = append(got, e.ID)
+		}
+		if !slices.Equal(got, step.want) {
+			t.Errorf("%s: claimed %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	cfg := &kafkapkg.ForwarderConfig{PollInterval: time.Second, NotifyPollInterval: time.Minute}
+	cases := []struct {
+		name  string
+		waker Waker
+		want  time.Duration
+	}{
+		{name: "no waker", want: time.Second},
+		{name: "listening", waker: newFakeWaker(true), want: time.Minute},
+		{name: "not listening", waker: newFakeWaker(false), want: time.Second},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newTestForwarder(newMemStore(0), standInWriter{}, tc.waker, cfg)
+			if got := f.pollInterval(); got != tc.want {
+				t.Errorf("poll interval = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestForwarderWakesUp(t *testing.T) {
+	store := newMemStore(0)
+	waker := newFakeWaker(true)
+	f := newTestForwarder(store, standInWriter{}, waker, &kafkapkg.ForwarderConfig{
+		PollInterval:       time.Hour,
+		NotifyPollInterval: time.Hour,
+		BatchSize:          10,
+		LeaseDuration:      time.Minute,
+	})
+	stop := run(f)
+	defer stop()
+
+	id := store.add("a", "1")
+	waker.wake(t)
+	store.waitProcessed(t, id)
+}
+
+func TestForwarderPollsWhenNotListening(t *testing.T) {
+	store := newMemStore(0)
+	waker := newFakeWaker(true)
+	f := newTestForwarder(store, standInWriter{}, waker, &kafkapkg.ForwarderConfig{
+		PollInterval:       10 * time.Millisecond,
+		NotifyPollInterval: time.Hour,
+		BatchSize:          10,
+		LeaseDuration:      time.Minute,
+	})
+	stop := run(f)
+	defer stop()
+
+	// the listener stops listening and wakes the forwarder up
+	waker.listening.Store(false)
+	waker.wake(t)
+
+	id := store.add("a", "1")
+	store.waitProcessed(t, id)
+}
+
+func BenchmarkForwarder(b *testing.B) {
+	cases := []struct {
+		batchSize int32
+		pipeline  bool
+	}{
+		{batchSize: 1},
+		{batchSize: 10},
+		{batchSize: 100},
+		{batchSize: 100, pipeline: true},
+	}
+	for _, tc := range cases {
+		b.Run(fmt.Sprintf("batch=%d/pipeline=%t", tc.batchSize, tc.pipeline), func(b *testing.B) {
+			store := newMemStore(b.N)
+			f := NewForwarder(store, standInWriter{}, nil, &kafkapkg.ForwarderConfig{
+				PollInterval:  time.Millisecond,
+				BatchSize:     tc.batchSize,
+				LeaseDuration: time.Minute,
+				Pipeline:      tc.pipeline,
+			}, logging.NewNopLogger())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stopped := make(chan struct{})
+			b.ResetTimer()
+			go func() {
+				defer close(stopped)
+				f.Run(ctx)
+			}()
+			<-store.done
+			b.StopTimer()
+			cancel()
+			<-stopped
+
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "events/s")
+		})
+	}
+}
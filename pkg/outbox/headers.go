@@ -0,0 +1,16 @@
+package outbox
+
+// Names of the headers events are commonly published with.
+const (
+	// HeaderEventType names the kind of payload of the event.
+	HeaderEventType = "event-type"
+	// HeaderSchemaVersion is the version of the payload schema.
+	HeaderSchemaVersion = "schema-version"
+	// HeaderCorrelationID identifies the request the event was created by.
+	HeaderCorrelationID = "correlation-id"
+	// HeaderProducer names the service that published the event.
+	HeaderProducer = "producer"
+)
+
+// Headers are the Kafka headers an event is published with.
+type Headers map[string]string
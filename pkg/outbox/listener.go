@@ -0,0 +1,109 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/pkg/logging"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// NotifyChannel is the channel the outbox_notify trigger notifies when
+// events are added to the outbox.
+const NotifyChannel = "outbox"
+
+// closeTimeout bounds closing the listen connection.
+const closeTimeout = 5 * time.Second
+
+// Listener wakes the forwarder up when events are added to the outbox,
+// listening for the notifications of the outbox_notify trigger on a
+// connection of its own taken from the pool.
+type Listener struct {
+	pool       *pgxpool.Pool
+	retryDelay time.Duration
+	logger     *logging.ZapLogger
+
+	wakeups   chan struct{}
+	listening atomic.Bool
+}
+
+// NewListener creates a new Listener. It listens again retryDelay after the
+// listen connection drops.
+func NewListener(pool *pgxpool.Pool, retryDelay time.Duration, logger *logging.ZapLogger) *Listener {
+	return &Listener{
+		pool:       pool,
+		retryDelay: retryDelay,
+		logger:     logger,
+		wakeups:    make(chan struct{}, 1),
+	}
+}
+
+// Wakeups returns the channel receiving a value when events were added to
+// the outbox, and whenever the listener starts or stops listening.
+// Notifications arriving before the previous one is received are merged.
+func (l *Listener) Wakeups() <-chan struct{} {
+	return l.wakeups
+}
+
+// Listening reports whether the listener receives notifications.
+func (l *Listener) Listening() bool {
+	return l.listening.Load()
+}
+
+// Run listens for notifications and blocks until the context is done.
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		l.listening.Store(false)
+		l.wake()
+		if ctx.Err() != nil {
+			return
+		}
+		l.logger.WarnCtx(ctx, "outbox listener stopped, falling back to polling", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retryDelay):
+		}
+	}
+}
+
+// listen waits for notifications until the connection fails or ctx is done.
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	// the connection is left listening, so it is closed rather than
+	// returned to the pool
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), closeTimeout)
+		defer cancel()
+		_ = conn.Hijack().Close(closeCtx)
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+NotifyChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	l.listening.Store(true)
+	// events may have been added while not listening
+	l.wake()
+
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		l.wake()
+	}
+}
+
+func (l *Listener) wake() {
+	select {
+	case l.wakeups <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"context"
+	"go-game-backend/pkg/logging"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PrunerConfig holds configuration for Pruner.
+type PrunerConfig struct {
+	Interval  time.Duration `yaml:"interval"`
+	Retention time.Duration `yaml:"retention"`
+	BatchSize int32         `yaml:"batch-size"`
+}
+
+// PruneStore deletes the events processed long ago. It is implemented by
+// *Repository.
+type PruneStore interface {
+	PruneProcessed(ctx context.Context, before time.Time, limit int32) (int64, error)
+}
+
+// Pruner periodically deletes the events processed longer than the
+// retention ago, so that the outbox does not keep them forever.
+type Pruner struct {
+	store  PruneStore
+	cfg    *PrunerConfig
+	logger *logging.ZapLogger
+}
+
+// NewPruner creates a new Pruner instance.
+func NewPruner(store PruneStore, cfg *PrunerConfig, logger *logging.ZapLogger) *Pruner {
+	return &Pruner{store: store, cfg: cfg, logger: logger}
+}
+
+// Run starts the prune loop and blocks until the context is done.
+func (p *Pruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.prune(ctx)
+		}
+	}
+}
+
+// prune deletes expired events batch by batch until none are left.
+func (p *Pruner) prune(ctx context.Context) {
+	before := time.Now().Add(-p.cfg.Retention)
+	for ctx.Err() == nil {
+		pruned, err := p.store.PruneProcessed(ctx, before, p.cfg.BatchSize)
+		if pruned > 0 {
+			p.logger.InfoCtx(ctx, "pruned outbox events", zap.Int64("count", pruned))
+		}
+		if err != nil {
+			p.logger.ErrorCtx(ctx, "prune outbox events", zap.Error(err))
+			return
+		}
+		if pruned < int64(p.cfg.BatchSize) {
+			return
+		}
+	}
+}
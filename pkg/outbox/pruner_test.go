@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"go-game-backend/pkg/logging"
+	"slices"
+	"testing"
+	"time"
+)
+
+// prunedIDs returns the IDs of the events deleted by PruneProcessed.
+func (s *memStore) prunedIDs() []int64 {
+	return s.ids(func(e *memEvent) bool { return e.pruned })
+}
+
+// setProcessed marks the event with the given ID processed at the given time.
+func (s *memStore) setProcessed(id int64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[id-1].processedAt = at
+}
+
+func (s *memStore) PruneProcessed(_ context.Context, before time.Time, limit int32) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prunes++
+	if s.pruneErr != nil {
+		return 0, s.pruneErr
+	}
+
+	var expired []*memEvent
+	for _, e := range s.events {
+		if !e.pruned && !e.processedAt.IsZero() && e.processedAt.Before(before) {
+			expired = append(expired, e)
+		}
+	}
+	slices.SortStableFunc(expired, func(a, b *memEvent) int { return a.processedAt.Compare(b.processedAt) })
+	expired = expired[:min(len(expired), int(limit))]
+	for _, e := range expired {
+		e.pruned = true
+	}
+	return int64(len(expired)), nil
+}
+
+func TestPrune(t *testing.T) {
+	cases := []struct {
+		name      string
+		expired   int
+		recent    int
+		pending   int
+		pruneErr  error
+		wantCalls int
+	}{
+		{name: "nothing to prune", recent: 2, pending: 1, wantCalls: 1},
+		{name: "less than a batch", expired: 2, recent: 1, wantCalls: 1},
+		{name: "full batches", expired: 6, recent: 1, pending: 1, wantCalls: 3},
+		{name: "several batches", expired: 7, recent: 1, wantCalls: 3},
+		{name: "store failing", expired: 4, pruneErr: errors.New("connection refused"), wantCalls: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newMemStore(0)
+			store.pruneErr = tc.pruneErr
+			now := time.Now()
+			var want []int64
+			for range tc.expired {
+				id := store.add("a", "1")
+				store.setProcessed(id, now.Add(-48*time.Hour))
+				if tc.pruneErr == nil {
+					want = append(want, id)
+				}
+			}
+			for range tc.recent {
+				id := store.add("a", "1")
+				store.setProcessed(id, now.Add(-time.Hour))
+			}
+			for range tc.pending {
+				store.add("a", "1")
+			}
+
+			p := NewPruner(store, &PrunerConfig{Retention: 24 * time.Hour, BatchSize: 3}, logging.NewNopLogger())
+			p.prune(context.Background())
+
+			if got := store.prunedIDs(); !slices.Equal(got, want) {
+				t.Errorf("pruned = %v, want %v", got, want)
+			}
+			if store.prunes != tc.wantCalls {
+				t.Errorf("prune calls = %d, want %d", store.prunes, tc.wantCalls)
+			}
+		})
+	}
+}
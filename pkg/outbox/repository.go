@@ -1,62 +1,238 @@
 package outbox
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go-game-backend/pkg/outbox/sqlc"
 	postgresstore "go-game-backend/pkg/postgres"
+	"slices"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// claimsLockKey is the advisory lock serializing claims of forwarder
+// instances, so that each sees the leases taken by the others.
+const claimsLockKey int64 = 0x6f7574626f78 // "outbox"
+
+// ErrEventNotFound is returned when requeuing an event that does not exist
+// or is not in the dead-letter state.
+var ErrEventNotFound = errors.New("outbox event not found")
+
 // Repository provides access to outbox events stored in PostgreSQL.
 type Repository struct {
 	postgresstore.BaseRepo[*sqlc.Queries]
+
+	pool *pgxpool.Pool
 }
 
 // NewRepository creates a new Repository with the given pgx pool.
 func NewRepository(pool *pgxpool.Pool) *Repository {
-	return &Repository{BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool))}
+	return &Repository{
+		BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool)),
+		pool:     pool,
+	}
 }
 
-// Add inserts a new event into the outbox table.
-func (r *Repository) Add(ctx context.Context, topic string, payload []byte) error {
-	if err := r.Q(ctx).AddEvent(ctx, sqlc.AddEventParams{Topic: topic, Payload: payload}); err != nil {
+// Add inserts a new event into the outbox table. The event is published with
+// key as the message key, so that events sharing a key keep their order, and
+// with the given headers. Key may be empty for events that need no ordering
+// beyond that of their topic.
+func (r *Repository) Add(ctx context.Context, topic, key string, payload []byte, headers Headers) error {
+	if headers == nil {
+		headers = Headers{}
+	}
+	hs, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("marshal headers: %w", err)
+	}
+	err = r.Q(ctx).AddEvent(ctx, sqlc.AddEventParams{
+		Topic:        topic,
+		PartitionKey: key,
+		Payload:      payload,
+		Headers:      hs,
+	})
+	if err != nil {
 		return fmt.Errorf("insert outbox event: %w", err)
 	}
 	return nil
 }
 
-// AddJSON inserts a new event into the outbox table, converting payload to JSON.
-func (r *Repository) AddJSON(ctx context.Context, topic string, payload any) error {
+// AddJSON inserts a new event into the outbox table like Add, converting
+// payload to JSON.
+func (r *Repository) AddJSON(ctx context.Context, topic, key string, payload any, headers Headers) error {
 	js, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
-	if err := r.Q(ctx).AddEvent(ctx, sqlc.AddEventParams{Topic: topic, Payload: js}); err != nil {
-		return fmt.Errorf("insert outbox event: %w", err)
+	return r.Add(ctx, topic, key, js, headers)
+}
+
+// Claim leases up to limit unprocessed events to owner for the lease
+// duration and returns them in the order they were added. Events leased to
+// another owner or waiting to be retried are skipped, as are events with an
+// older event of the same topic and key leased or waiting to be retried, so
+// that events sharing a key are published in order even when several
+// instances forward the outbox. Events whose lease ran out are claimed again, dead events are not.
+func (r *Repository) Claim(ctx context.Context, owner string, limit int32, lease time.Duration) ([]Event, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := r.Q(ctx).WithTx(tx)
+	if err := q.LockClaims(ctx, claimsLockKey); err != nil {
+		return nil, fmt.Errorf("lock outbox claims: %w", err)
+	}
+
+	rows, err := q.ClaimEvents(ctx, sqlc.ClaimEventsParams{
+		Owner:        owner,
+		LeaseSeconds: lease.Seconds(),
+		MaxEvents:    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox events: %w", err)
+	}
+
+	events := make([]Event, len(rows))
+	for i, row := range rows {
+		var headers Headers
+		if err := json.Unmarshal(row.Headers, &headers); err != nil {
+			return nil, fmt.Errorf("unmarshal headers of event %d: %w", row.ID, err)
+		}
+		events[i] = Event{
+			ID:       row.ID,
+			Topic:    row.Topic,
+			Key:      row.PartitionKey,
+			Payload:  row.Payload,
+			Headers:  headers,
+			Attempts: row.Attempts,
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	slices.SortFunc(events, func(a, b Event) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+// ReleaseClaims gives up the leases owner holds on events it has not
+// processed, so that they can be claimed again right away.
+func (r *Repository) ReleaseClaims(ctx context.Context, owner string) error {
+	if err := r.Q(ctx).ReleaseClaims(ctx, pgtype.Text{String: owner, Valid: true}); err != nil {
+		return fmt.Errorf("release outbox claims: %w", err)
 	}
 	return nil
 }
 
-// Fetch returns a batch of unprocessed events.
-func (r *Repository) Fetch(ctx context.Context, limit int32) ([]Event, error) {
-	rows, err := r.Q(ctx).FetchEvents(ctx, limit)
+// ReleaseEvents gives up the leases owner holds on the given events, so that
+// they can be claimed again right away.
+func (r *Repository) ReleaseEvents(ctx context.Context, owner string, ids []int64) error {
+	if err := r.Q(ctx).ReleaseEvents(ctx, sqlc.ReleaseEventsParams{Ids: ids, Owner: owner}); err != nil {
+		return fmt.Errorf("release outbox events: %w", err)
+	}
+	return nil
+}
+
+// MarkProcessed marks the events as processed. Their payload is dropped, as
+// it may carry secrets, e.g. single-use tokens, that must not outlive the
+// delivery of the event.
+func (r *Repository) MarkProcessed(ctx context.Context, ids ...int64) error {
+	if err := r.Q(ctx).MarkProcessed(ctx, ids); err != nil {
+		return fmt.Errorf("mark outbox events processed: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt to publish the event and releases its
+// claim. The event is retried after retryIn, unless dead is set, in which
+// case it is moved to the dead-letter state.
+func (r *Repository) MarkFailed(ctx context.Context, id int64, cause error, retryIn time.Duration, dead bool) error {
+	err := r.Q(ctx).MarkFailed(ctx, sqlc.MarkFailedParams{
+		LastError:    cause.Error(),
+		RetrySeconds: retryIn.Seconds(),
+		Dead:         dead,
+		ID:           id,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("query outbox events: %w", err)
+		return fmt.Errorf("mark outbox event failed: %w", err)
 	}
-	events := make([]Event, len(rows))
+	return nil
+}
+
+// ListDead returns up to limit events in the dead-letter state, oldest
+// first.
+func (r *Repository) ListDead(ctx context.Context, limit int32) ([]DeadEvent, error) {
+	rows, err := r.Q(ctx).ListDeadEvents(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list dead outbox events: %w", err)
+	}
+	events := make([]DeadEvent, len(rows))
 	for i, row := range rows {
-		events[i] = Event{ID: row.ID, Topic: row.Topic, Payload: row.Payload}
+		events[i] = DeadEvent{
+			ID:        row.ID,
+			Topic:     row.Topic,
+			Attempts:  row.Attempts,
+			LastError: row.LastError.String,
+			CreatedAt: row.CreatedAt.Time,
+			DeadAt:    row.DeadAt.Time,
+		}
 	}
 	return events, nil
 }
 
-// MarkProcessed marks the event as processed.
-func (r *Repository) MarkProcessed(ctx context.Context, id int64) error {
-	if err := r.Q(ctx).MarkProcessed(ctx, id); err != nil {
-		return fmt.Errorf("mark outbox event processed: %w", err)
+// Requeue moves a dead event back to the outbox with its attempts reset.
+// ErrEventNotFound is returned if there is no such dead event.
+func (r *Repository) Requeue(ctx context.Context, id int64) error {
+	n, err := r.Q(ctx).RequeueDeadEvent(ctx, id)
+	if err != nil {
+		return fmt.Errorf("requeue outbox event: %w", err)
+	}
+	if n == 0 {
+		return ErrEventNotFound
+	}
+	return nil
+}
+
+// RequeueAll moves all dead events of the topic back to the outbox, or all
+// dead events if topic is empty, and returns how many were requeued.
+func (r *Repository) RequeueAll(ctx context.Context, topic string) (int64, error) {
+	n, err := r.Q(ctx).RequeueDeadEvents(ctx, pgtype.Text{String: topic, Valid: topic != ""})
+	if err != nil {
+		return 0, fmt.Errorf("requeue outbox events: %w", err)
+	}
+	return n, nil
+}
+
+// PruneProcessed deletes up to limit events processed before the given time
+// and returns how many were deleted.
+func (r *Repository) PruneProcessed(ctx context.Context, before time.Time, limit int32) (int64, error) {
+	n, err := r.Q(ctx).PruneProcessed(ctx, sqlc.PruneProcessedParams{
+		Before:    pgtype.Timestamptz{Time: before, Valid: true},
+		MaxEvents: limit,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("prune outbox events: %w", err)
+	}
+	return n, nil
+}
+
+// DeleteByKey deletes every event with the key, whether published or not,
+// e.g. the events about a player whose data is purged. The events without a
+// key are never deleted this way.
+func (r *Repository) DeleteByKey(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+	if err := r.Q(ctx).DeleteKeyEvents(ctx, key); err != nil {
+		return fmt.Errorf("delete outbox events: %w", err)
 	}
 	return nil
 }
@@ -9,9 +9,17 @@ import (
 )
 
 type Outbox struct {
-	ID          int64
-	Topic       string
-	Payload     []byte
-	CreatedAt   pgtype.Timestamptz
-	ProcessedAt pgtype.Timestamptz
+	ID            int64
+	Topic         string
+	PartitionKey  string
+	Payload       []byte
+	Headers       []byte
+	CreatedAt     pgtype.Timestamptz
+	ProcessedAt   pgtype.Timestamptz
+	ClaimedBy     pgtype.Text
+	ClaimedUntil  pgtype.Timestamptz
+	Attempts      int32
+	LastError     pgtype.Text
+	NextAttemptAt pgtype.Timestamptz
+	DeadAt        pgtype.Timestamptz
 }
@@ -7,42 +7,91 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addEvent = `-- name: AddEvent :exec
-INSERT INTO outbox (topic, payload, created_at) VALUES ($1, $2, NOW())
+INSERT INTO outbox (topic, partition_key, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())
 `
 
 type AddEventParams struct {
-	Topic   string
-	Payload []byte
+	Topic        string
+	PartitionKey string
+	Payload      []byte
+	Headers      []byte
 }
 
 func (q *Queries) AddEvent(ctx context.Context, arg AddEventParams) error {
-	_, err := q.db.Exec(ctx, addEvent, arg.Topic, arg.Payload)
+	_, err := q.db.Exec(ctx, addEvent,
+		arg.Topic,
+		arg.PartitionKey,
+		arg.Payload,
+		arg.Headers,
+	)
 	return err
 }
 
-const fetchEvents = `-- name: FetchEvents :many
-SELECT id, topic, payload FROM outbox WHERE processed_at IS NULL ORDER BY id LIMIT $1
+const claimEvents = `-- name: ClaimEvents :many
+UPDATE outbox
+SET claimed_by    = $1::text,
+    claimed_until = NOW() + make_interval(secs => $2::float8)
+WHERE id IN (
+    SELECT o.id
+    FROM outbox o
+    WHERE o.processed_at IS NULL
+      AND o.dead_at IS NULL
+      AND (o.claimed_until IS NULL OR o.claimed_until < NOW())
+      AND (o.next_attempt_at IS NULL OR o.next_attempt_at <= NOW())
+      AND NOT EXISTS (
+          SELECT 1
+          FROM outbox p
+          WHERE p.topic = o.topic
+            AND p.partition_key = o.partition_key
+            AND p.id < o.id
+            AND p.processed_at IS NULL
+            AND p.dead_at IS NULL
+            AND (p.claimed_until >= NOW() OR p.next_attempt_at > NOW())
+      )
+    ORDER BY o.id
+    LIMIT $3
+    FOR UPDATE OF o SKIP LOCKED
+)
+RETURNING id, topic, partition_key, payload, headers, attempts
 `
 
-type FetchEventsRow struct {
-	ID      int64
-	Topic   string
-	Payload []byte
+type ClaimEventsParams struct {
+	Owner        string
+	LeaseSeconds float64
+	MaxEvents    int32
 }
 
-func (q *Queries) FetchEvents(ctx context.Context, limit int32) ([]FetchEventsRow, error) {
-	rows, err := q.db.Query(ctx, fetchEvents, limit)
+type ClaimEventsRow struct {
+	ID           int64
+	Topic        string
+	PartitionKey string
+	Payload      []byte
+	Headers      []byte
+	Attempts     int32
+}
+
+func (q *Queries) ClaimEvents(ctx context.Context, arg ClaimEventsParams) ([]ClaimEventsRow, error) {
+	rows, err := q.db.Query(ctx, claimEvents, arg.Owner, arg.LeaseSeconds, arg.MaxEvents)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []FetchEventsRow
+	var items []ClaimEventsRow
 	for rows.Next() {
-		var i FetchEventsRow
-		if err := rows.Scan(&i.ID, &i.Topic, &i.Payload); err != nil {
+		var i ClaimEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Topic,
+			&i.PartitionKey,
+			&i.Payload,
+			&i.Headers,
+			&i.Attempts,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -53,11 +102,179 @@ func (q *Queries) FetchEvents(ctx context.Context, limit int32) ([]FetchEventsRo
 	return items, nil
 }
 
+const deleteKeyEvents = `-- name: DeleteKeyEvents :exec
+DELETE FROM outbox WHERE partition_key = $1
+`
+
+func (q *Queries) DeleteKeyEvents(ctx context.Context, partitionKey string) error {
+	_, err := q.db.Exec(ctx, deleteKeyEvents, partitionKey)
+	return err
+}
+
+const listDeadEvents = `-- name: ListDeadEvents :many
+SELECT id, topic, attempts, last_error, created_at, dead_at
+FROM outbox
+WHERE dead_at IS NOT NULL
+ORDER BY id
+LIMIT $1
+`
+
+type ListDeadEventsRow struct {
+	ID        int64
+	Topic     string
+	Attempts  int32
+	LastError pgtype.Text
+	CreatedAt pgtype.Timestamptz
+	DeadAt    pgtype.Timestamptz
+}
+
+func (q *Queries) ListDeadEvents(ctx context.Context, limit int32) ([]ListDeadEventsRow, error) {
+	rows, err := q.db.Query(ctx, listDeadEvents, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeadEventsRow
+	for rows.Next() {
+		var i ListDeadEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Topic,
+			&i.Attempts,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.DeadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockClaims = `-- name: LockClaims :exec
+SELECT pg_advisory_xact_lock($1::bigint)
+`
+
+func (q *Queries) LockClaims(ctx context.Context, lockKey int64) error {
+	_, err := q.db.Exec(ctx, lockClaims, lockKey)
+	return err
+}
+
+const markFailed = `-- name: MarkFailed :exec
+UPDATE outbox
+SET attempts        = attempts + 1,
+    last_error      = $1::text,
+    next_attempt_at = NOW() + make_interval(secs => $2::float8),
+    dead_at         = CASE WHEN $3::bool THEN NOW() END,
+    claimed_by      = NULL,
+    claimed_until   = NULL
+WHERE id = $4
+`
+
+type MarkFailedParams struct {
+	LastError    string
+	RetrySeconds float64
+	Dead         bool
+	ID           int64
+}
+
+func (q *Queries) MarkFailed(ctx context.Context, arg MarkFailedParams) error {
+	_, err := q.db.Exec(ctx, markFailed,
+		arg.LastError,
+		arg.RetrySeconds,
+		arg.Dead,
+		arg.ID,
+	)
+	return err
+}
+
 const markProcessed = `-- name: MarkProcessed :exec
-UPDATE outbox SET processed_at = NOW() WHERE id = $1
+UPDATE outbox SET processed_at = NOW(), payload = ''::bytea WHERE id = ANY($1::bigint[])
+`
+
+func (q *Queries) MarkProcessed(ctx context.Context, ids []int64) error {
+	_, err := q.db.Exec(ctx, markProcessed, ids)
+	return err
+}
+
+const pruneProcessed = `-- name: PruneProcessed :execrows
+DELETE FROM outbox
+WHERE id IN (
+    SELECT id
+    FROM outbox
+    WHERE processed_at < $1::timestamptz
+    ORDER BY processed_at
+    LIMIT $2
+)
+`
+
+type PruneProcessedParams struct {
+	Before    pgtype.Timestamptz
+	MaxEvents int32
+}
+
+func (q *Queries) PruneProcessed(ctx context.Context, arg PruneProcessedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneProcessed, arg.Before, arg.MaxEvents)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseClaims = `-- name: ReleaseClaims :exec
+UPDATE outbox SET claimed_by = NULL, claimed_until = NULL
+WHERE claimed_by = $1 AND processed_at IS NULL
+`
+
+func (q *Queries) ReleaseClaims(ctx context.Context, claimedBy pgtype.Text) error {
+	_, err := q.db.Exec(ctx, releaseClaims, claimedBy)
+	return err
+}
+
+const releaseEvents = `-- name: ReleaseEvents :exec
+UPDATE outbox SET claimed_by = NULL, claimed_until = NULL
+WHERE id = ANY($1::bigint[])
+  AND claimed_by = $2::text
+  AND processed_at IS NULL
 `
 
-func (q *Queries) MarkProcessed(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, markProcessed, id)
+type ReleaseEventsParams struct {
+	Ids   []int64
+	Owner string
+}
+
+func (q *Queries) ReleaseEvents(ctx context.Context, arg ReleaseEventsParams) error {
+	_, err := q.db.Exec(ctx, releaseEvents, arg.Ids, arg.Owner)
 	return err
 }
+
+const requeueDeadEvent = `-- name: RequeueDeadEvent :execrows
+UPDATE outbox SET attempts = 0, next_attempt_at = NULL, dead_at = NULL
+WHERE id = $1 AND dead_at IS NOT NULL
+`
+
+func (q *Queries) RequeueDeadEvent(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, requeueDeadEvent, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const requeueDeadEvents = `-- name: RequeueDeadEvents :execrows
+UPDATE outbox SET attempts = 0, next_attempt_at = NULL, dead_at = NULL
+WHERE dead_at IS NOT NULL
+  AND ($1::text IS NULL OR topic = $1::text)
+`
+
+func (q *Queries) RequeueDeadEvents(ctx context.Context, topic pgtype.Text) (int64, error) {
+	result, err := q.db.Exec(ctx, requeueDeadEvents, topic)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
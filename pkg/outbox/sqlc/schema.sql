@@ -1,7 +1,32 @@
 CREATE TABLE outbox (
     id BIGSERIAL PRIMARY KEY,
     topic TEXT NOT NULL,
+    partition_key TEXT NOT NULL DEFAULT '',
     payload BYTEA NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}',
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    processed_at TIMESTAMPTZ
+    processed_at TIMESTAMPTZ,
+    claimed_by TEXT,
+    claimed_until TIMESTAMPTZ,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ,
+    dead_at TIMESTAMPTZ
 );
+
+CREATE INDEX outbox_unprocessed_idx ON outbox (topic, partition_key, id) WHERE processed_at IS NULL;
+CREATE INDEX outbox_dead_idx ON outbox (id) WHERE dead_at IS NOT NULL;
+CREATE INDEX outbox_processed_idx ON outbox (processed_at) WHERE processed_at IS NOT NULL;
+CREATE INDEX outbox_partition_key_idx ON outbox (partition_key);
+
+CREATE FUNCTION outbox_notify() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_notify
+    AFTER INSERT ON outbox
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION outbox_notify();
@@ -1,8 +1,84 @@
 -- name: AddEvent :exec
-INSERT INTO outbox (topic, payload, created_at) VALUES ($1, $2, NOW());
+INSERT INTO outbox (topic, partition_key, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW());
 
--- name: FetchEvents :many
-SELECT id, topic, payload FROM outbox WHERE processed_at IS NULL ORDER BY id LIMIT $1;
+-- name: LockClaims :exec
+SELECT pg_advisory_xact_lock(sqlc.arg(lock_key)::bigint);
+
+-- name: ClaimEvents :many
+UPDATE outbox
+SET claimed_by    = sqlc.arg(owner)::text,
+    claimed_until = NOW() + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+WHERE id IN (
+    SELECT o.id
+    FROM outbox o
+    WHERE o.processed_at IS NULL
+      AND o.dead_at IS NULL
+      AND (o.claimed_until IS NULL OR o.claimed_until < NOW())
+      AND (o.next_attempt_at IS NULL OR o.next_attempt_at <= NOW())
+      AND NOT EXISTS (
+          SELECT 1
+          FROM outbox p
+          WHERE p.topic = o.topic
+            AND p.partition_key = o.partition_key
+            AND p.id < o.id
+            AND p.processed_at IS NULL
+            AND p.dead_at IS NULL
+            AND (p.claimed_until >= NOW() OR p.next_attempt_at > NOW())
+      )
+    ORDER BY o.id
+    LIMIT sqlc.arg(max_events)
+    FOR UPDATE OF o SKIP LOCKED
+)
+RETURNING id, topic, partition_key, payload, headers, attempts;
+
+-- name: ReleaseClaims :exec
+UPDATE outbox SET claimed_by = NULL, claimed_until = NULL
+WHERE claimed_by = $1 AND processed_at IS NULL;
+
+-- name: ReleaseEvents :exec
+UPDATE outbox SET claimed_by = NULL, claimed_until = NULL
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+  AND claimed_by = sqlc.arg(owner)::text
+  AND processed_at IS NULL;
 
 -- name: MarkProcessed :exec
-UPDATE outbox SET processed_at = NOW() WHERE id = $1;
+UPDATE outbox SET processed_at = NOW(), payload = ''::bytea WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: MarkFailed :exec
+UPDATE outbox
+SET attempts        = attempts + 1,
+    last_error      = sqlc.arg(last_error)::text,
+    next_attempt_at = NOW() + make_interval(secs => sqlc.arg(retry_seconds)::float8),
+    dead_at         = CASE WHEN sqlc.arg(dead)::bool THEN NOW() END,
+    claimed_by      = NULL,
+    claimed_until   = NULL
+WHERE id = sqlc.arg(id);
+
+-- name: ListDeadEvents :many
+SELECT id, topic, attempts, last_error, created_at, dead_at
+FROM outbox
+WHERE dead_at IS NOT NULL
+ORDER BY id
+LIMIT $1;
+
+-- name: RequeueDeadEvent :execrows
+UPDATE outbox SET attempts = 0, next_attempt_at = NULL, dead_at = NULL
+WHERE id = $1 AND dead_at IS NOT NULL;
+
+-- name: RequeueDeadEvents :execrows
+UPDATE outbox SET attempts = 0, next_attempt_at = NULL, dead_at = NULL
+WHERE dead_at IS NOT NULL
+  AND (sqlc.narg(topic)::text IS NULL OR topic = sqlc.narg(topic)::text);
+
+-- name: PruneProcessed :execrows
+DELETE FROM outbox
+WHERE id IN (
+    SELECT id
+    FROM outbox
+    WHERE processed_at < sqlc.arg(before)::timestamptz
+    ORDER BY processed_at
+    LIMIT sqlc.arg(max_events)
+);
+
+-- name: DeleteKeyEvents :exec
+DELETE FROM outbox WHERE partition_key = $1;
@@ -0,0 +1,26 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockNotObtained is returned by DoWithLock when the lock is already held
+// by someone else.
+var ErrLockNotObtained = errors.New("lock not obtained")
+
+// IsUnavailable reports whether err indicates that the Redis server could not
+// be reached. A deadline running out is not, although it is a net.Error.
+func IsUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, redis.ErrClosed) || errors.Is(err, redis.ErrPoolTimeout) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
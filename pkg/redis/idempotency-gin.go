@@ -0,0 +1,172 @@
+package redisstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers of the idempotency middleware.
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// Error codes reported in the body of responses rejected by the idempotency
+// middleware.
+const (
+	ErrorCodeInvalidIdempotencyKey = "invalid_idempotency_key"
+	ErrorCodeIdempotencyKeyReused  = "idempotency_key_reused"
+	ErrorCodeRequestInProgress     = "request_in_progress"
+)
+
+// idempotencyError mirrors the error body returned by the services.
+type idempotencyError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// GinMiddleware makes requests carrying an Idempotency-Key header safe to
+// retry. The response to the first request with a key is stored and replayed
+// for retries with the same key and body, marked with the
+// Idempotent-Replayed header. Reusing the key for a different request is
+// rejected with 422, and retrying while the first request is still in flight
+// with 409. Server errors and transient client errors, such as 423 and 429,
+// are not stored, so the request may be retried.
+//
+// Keys are scoped by scope, typically the route, and the credentials of the
+// request are part of what must match, so one client cannot obtain the
+// response of another. Requests are let through without the guarantee when
+// Redis fails.
+func (s *IdempotencyStore) GinMiddleware(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, idempotencyError{
+				Code:    ErrorCodeInvalidIdempotencyKey,
+				Message: "idempotency key is too long",
+			})
+			return
+		}
+
+		fingerprint, ok := requestFingerprint(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		key = scope + ":" + key
+		// The outcome is stored even when the client goes away mid-request,
+		// which is exactly when it is going to retry.
+		ctx := context.WithoutCancel(c.Request.Context())
+		acquired, stored, err := s.Acquire(ctx, key, fingerprint)
+		if err != nil {
+			_ = c.Error(err)
+			c.Next()
+			return
+		}
+
+		if !acquired {
+			replayIdempotentResponse(c, fingerprint, stored)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+		c.Next()
+
+		if !storableStatus(rec.Status()) {
+			if err := s.Release(ctx, key); err != nil {
+				_ = c.Error(err)
+			}
+			return
+		}
+
+		err = s.Complete(ctx, key, IdempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      rec.Status(),
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+		})
+		if err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
+
+// storableStatus reports whether a response with the status is final and
+// may be replayed. Server errors and the transient client errors, such as a
+// locked resource or an exceeded rate limit, are retried instead.
+func storableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusLocked, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+func replayIdempotentResponse(c *gin.Context, fingerprint string, stored IdempotentResponse) {
+	switch {
+	case stored.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, idempotencyError{
+			Code:    ErrorCodeIdempotencyKeyReused,
+			Message: "idempotency key was used for a different request",
+		})
+	case !stored.Completed:
+		c.AbortWithStatusJSON(http.StatusConflict, idempotencyError{
+			Code:    ErrorCodeRequestInProgress,
+			Message: "a request with this idempotency key is in progress",
+		})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+		c.Abort()
+	}
+}
+
+// requestFingerprint digests the method, path, credentials and body of the
+// request. The body is restored for the handler.
+func requestFingerprint(c *gin.Context) (string, bool) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", false
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	h.Write([]byte(c.GetHeader("Authorization") + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// responseRecorder keeps a copy of the response body written by the handler.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
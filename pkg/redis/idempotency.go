@@ -0,0 +1,112 @@
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyConfig controls how long the responses of requests carrying an
+// idempotency key are kept.
+type IdempotencyConfig struct {
+	// TTL is how long a stored response is replayed for retries.
+	TTL time.Duration `yaml:"ttl"`
+	// LockTTL bounds how long a request may hold its key while in flight, so
+	// that a crashed replica does not block retries until TTL.
+	LockTTL time.Duration `yaml:"lock-ttl"`
+}
+
+// IdempotentResponse is the response stored for an idempotency key.
+// Fingerprint identifies the request the key was first used with.
+type IdempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyStore keeps the responses of requests carrying an idempotency
+// key in Redis, so that retries are answered by every replica of a service
+// without repeating the request.
+type IdempotencyStore struct {
+	rdb redis.Cmdable
+	cfg *IdempotencyConfig
+}
+
+// NewIdempotencyStore creates an IdempotencyStore keeping responses in rdb.
+func NewIdempotencyStore(rdb redis.Cmdable, cfg *IdempotencyConfig) *IdempotencyStore {
+	return &IdempotencyStore{
+		rdb: rdb,
+		cfg: cfg,
+	}
+}
+
+// Acquire claims key for a request with the given fingerprint. When the key
+// was already claimed, acquired is false and stored holds what is recorded
+// for it: the response of a completed request, or only the fingerprint of a
+// request still in flight.
+func (s *IdempotencyStore) Acquire(
+	ctx context.Context,
+	key, fingerprint string,
+) (acquired bool, stored IdempotentResponse, err error) {
+	pending, err := json.Marshal(IdempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return false, IdempotentResponse{}, fmt.Errorf("marshal pending response: %w", err)
+	}
+
+	// A claim expiring between SETNX and GET is retried once; it only happens
+	// when a request outlives LockTTL.
+	for range 2 {
+		ok, err := s.rdb.SetNX(ctx, idempotencyKey(key), pending, s.cfg.LockTTL).Result()
+		if err != nil {
+			return false, IdempotentResponse{}, fmt.Errorf("setnx idempotency key '%s': %w", key, err)
+		}
+		if ok {
+			return true, IdempotentResponse{}, nil
+		}
+
+		raw, err := s.rdb.Get(ctx, idempotencyKey(key)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return false, IdempotentResponse{}, fmt.Errorf("get idempotency key '%s': %w", key, err)
+		}
+		if err := json.Unmarshal(raw, &stored); err != nil {
+			return false, IdempotentResponse{}, fmt.Errorf("unmarshal idempotent response: %w", err)
+		}
+		return false, stored, nil
+	}
+	return false, IdempotentResponse{}, fmt.Errorf("acquire idempotency key '%s': claim keeps expiring", key)
+}
+
+// Complete stores the response of the request holding key.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, resp IdempotentResponse) error {
+	resp.Completed = true
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("marshal idempotent response: %w", err)
+	}
+
+	if err := s.rdb.Set(ctx, idempotencyKey(key), raw, s.cfg.TTL).Err(); err != nil {
+		return fmt.Errorf("set idempotency key '%s': %w", key, err)
+	}
+	return nil
+}
+
+// Release drops the claim of key so that the request may be retried.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := s.rdb.Del(ctx, idempotencyKey(key)).Err(); err != nil {
+		return fmt.Errorf("del idempotency key '%s': %w", key, err)
+	}
+	return nil
+}
+
+func idempotencyKey(key string) string {
+	return fmt.Sprintf("idempotency:%s", key)
+}
@@ -0,0 +1,137 @@
+package redisstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCodeRateLimited is reported in the body of responses rejected by the
+// rate limit middleware.
+const ErrorCodeRateLimited = "rate_limited"
+
+// RateLimitKeyFunc extracts the value a request is counted under. Requests
+// for which ok is false are not counted by the rule.
+type RateLimitKeyFunc func(c *gin.Context) (key string, ok bool)
+
+// RateLimitRule limits requests sharing the same key within a scope.
+type RateLimitRule struct {
+	Scope string
+	Limit Limit
+	Key   RateLimitKeyFunc
+}
+
+// rateLimitError mirrors the error body returned by the services.
+type rateLimitError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// GinMiddleware rejects requests exceeding any of the rules with 429 and a
+// Retry-After header. Requests are let through when Redis fails, so that an
+// outage of the limiter does not take the service down with it.
+func (l *RateLimiter) GinMiddleware(rules ...RateLimitRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, rule := range rules {
+			if !rule.Limit.Enabled() {
+				continue
+			}
+			key, ok := rule.Key(c)
+			if !ok {
+				continue
+			}
+
+			allowed, retryAfter, err := l.Allow(c.Request.Context(), rule.Scope+":"+key, rule.Limit)
+			if err != nil {
+				_ = c.Error(err)
+				continue
+			}
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				c.Header("Retry-After", strconv.Itoa(max(seconds, 1)))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, rateLimitError{
+					Code:    ErrorCodeRateLimited,
+					Message: "too many requests",
+				})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// ClientIPKey counts requests per client IP.
+func ClientIPKey(c *gin.Context) (string, bool) {
+	return "ip:" + c.ClientIP(), true
+}
+
+// FieldCanonicalizer maps every spelling a handler accepts for a request
+// field value to a single one, so that the spellings share a counter. ok is
+// false for values the handler rejects.
+type FieldCanonicalizer func(value string) (canonical string, ok bool)
+
+// invalidFieldValue is the key of the shared counter of requests whose field
+// cannot be read or canonicalized.
+const invalidFieldValue = "\x00invalid"
+
+// JSONFieldKey counts requests per canonical value of a top-level string
+// field of the JSON request body, e.g. the login being attacked. The field is
+// looked up as encoding/json binds it, ignoring case. Requests with a body
+// or field value that cannot be decoded or canonicalized, or with the field
+// given more than once, share one counter, so that they cannot dodge the
+// limit. The body is restored for the handler.
+func JSONFieldKey(field string, canonical FieldCanonicalizer) RateLimitKeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		if c.Request.Body == nil {
+			return "", false
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", false
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Decode like gin binds the body, which ignores trailing data.
+		var fields map[string]json.RawMessage
+		if err := json.NewDecoder(bytes.NewReader(body)).Decode(&fields); err != nil {
+			return fieldKey(field, invalidFieldValue), true
+		}
+
+		var raw json.RawMessage
+		found := 0
+		for name, value := range fields {
+			if strings.EqualFold(name, field) {
+				raw = value
+				found++
+			}
+		}
+		switch {
+		case found == 0:
+			return "", false
+		case found > 1:
+			return fieldKey(field, invalidFieldValue), true
+		}
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return fieldKey(field, invalidFieldValue), true
+		}
+		value, ok := canonical(value)
+		if !ok {
+			return fieldKey(field, invalidFieldValue), true
+		}
+		return fieldKey(field, value), true
+	}
+}
+
+// fieldKey returns the key of a canonical request field value, so that HTTP
+// and gRPC requests for the same value share their counters.
+func fieldKey(field, value string) string {
+	return field + ":" + value
+}
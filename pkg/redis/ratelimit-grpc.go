@@ -0,0 +1,162 @@
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCRateLimitKeyFunc extracts the value a call is counted under from its
+// context and request message. Calls for which ok is false are not counted
+// by the rule.
+type GRPCRateLimitKeyFunc func(ctx context.Context, req any) (key string, ok bool)
+
+// GRPCRateLimitRule limits calls sharing the same key within a scope.
+type GRPCRateLimitRule struct {
+	Scope string
+	Limit Limit
+	Key   GRPCRateLimitKeyFunc
+}
+
+// UnaryServerInterceptor rejects calls exceeding any of the rules of their
+// full method name with ResourceExhausted and a retry-after header. Calls are
+// let through when Redis fails, like in GinMiddleware.
+func (l *RateLimiter) UnaryServerInterceptor(rules map[string][]GRPCRateLimitRule) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		for _, rule := range rules[info.FullMethod] {
+			if !rule.Limit.Enabled() {
+				continue
+			}
+			key, ok := rule.Key(ctx, req)
+			if !ok {
+				continue
+			}
+
+			allowed, retryAfter, err := l.Allow(ctx, rule.Scope+":"+key, rule.Limit)
+			if err != nil {
+				continue
+			}
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(max(seconds, 1))))
+				return nil, status.Error(codes.ResourceExhausted, "too many requests")
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// forwardedForKey is the metadata key proxies report the client IP in.
+const forwardedForKey = "x-forwarded-for"
+
+// PeerIPKey returns a key func counting calls per client IP, under the same
+// key as ClientIPKey. Calls coming from one of trustedProxies, given as IPs
+// or CIDRs like to gin.Engine.SetTrustedProxies, are counted under the client
+// IP the proxies report in x-forwarded-for metadata, as gin does for HTTP
+// requests.
+func PeerIPKey(trustedProxies []string) (GRPCRateLimitKeyFunc, error) {
+	trusted, err := parseTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, _ any) (string, bool) {
+		p, ok := peer.FromContext(ctx)
+		if !ok || p.Addr == nil {
+			return "", false
+		}
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+
+		if ip := net.ParseIP(host); ip != nil && isTrustedProxy(trusted, ip) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if clientIP, ok := forwardedClientIP(md.Get(forwardedForKey), trusted); ok {
+				host = clientIP
+			}
+		}
+		return "ip:" + host, true
+	}, nil
+}
+
+// parseTrustedProxies parses proxy IPs and CIDRs.
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy '%s'", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			proxy = fmt.Sprintf("%s/%d", ip, bits)
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy '%s': %w", proxy, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+func isTrustedProxy(trusted []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range trusted {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedClientIP walks the x-forwarded-for chain from the nearest hop and
+// returns the first IP not of a trusted proxy, or the farthest one if every
+// hop is trusted. ok is false when the chain is missing or malformed.
+func forwardedClientIP(values []string, trusted []*net.IPNet) (string, bool) {
+	if len(values) == 0 {
+		return "", false
+	}
+	hops := strings.Split(strings.Join(values, ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		ip := net.ParseIP(hop)
+		if ip == nil {
+			return "", false
+		}
+		if i == 0 || !isTrustedProxy(trusted, ip) {
+			return hop, true
+		}
+	}
+	return "", false
+}
+
+// MessageFieldKey counts calls per value returned by get for the request
+// message, under the same key as JSONFieldKey for the field. get must return
+// the value canonicalized like the FieldCanonicalizer of the field does.
+// Calls with another message type or an empty value are not counted.
+func MessageFieldKey[T any](field string, get func(T) string) GRPCRateLimitKeyFunc {
+	return func(_ context.Context, req any) (string, bool) {
+		msg, ok := req.(T)
+		if !ok {
+			return "", false
+		}
+		value := get(msg)
+		if value == "" {
+			return "", false
+		}
+		return fieldKey(field, value), true
+	}
+}
@@ -0,0 +1,97 @@
+package redisstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limit allows Requests requests within a sliding Window. A zero Limit
+// allows everything.
+type Limit struct {
+	Requests int           `yaml:"requests"`
+	Window   time.Duration `yaml:"window"`
+}
+
+// Enabled reports whether the limit restricts anything.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+// slidingWindowScript keeps the timestamps of the requests accepted within
+// the window in a sorted set. It returns {1, 0} when the request is accepted,
+// or {0, retry_after_ms} otherwise.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+if redis.call('ZCARD', key) < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return {1, 0}
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// RateLimiter counts requests per key in Redis using a sliding window log,
+// so limits are shared by every replica of a service.
+type RateLimiter struct {
+	rdb redis.Scripter
+}
+
+// NewRateLimiter creates a RateLimiter storing its counters in rdb.
+func NewRateLimiter(rdb redis.Scripter) *RateLimiter {
+	return &RateLimiter{rdb: rdb}
+}
+
+// Allow records a request for key and reports whether it fits into limit.
+// When it does not, retryAfter tells how long until the oldest request in
+// the window expires.
+func (l *RateLimiter) Allow(ctx context.Context, key string, limit Limit) (ok bool, retryAfter time.Duration, err error) {
+	if !limit.Enabled() {
+		return true, 0, nil
+	}
+
+	member, err := newWindowMember()
+	if err != nil {
+		return false, 0, err
+	}
+
+	res, err := slidingWindowScript.Run(
+		ctx,
+		l.rdb,
+		[]string{rateLimitKey(key)},
+		time.Now().UnixMilli(),
+		limit.Window.Milliseconds(),
+		limit.Requests,
+		member,
+	).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("run rate limit script for '%s': %w", key, err)
+	}
+
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// newWindowMember returns a unique sorted set member so that requests
+// arriving within the same millisecond are all counted.
+func newWindowMember() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate window member: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("rate_limit:%s", key)
+}
@@ -4,6 +4,7 @@ package redisstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-game-backend/pkg/futils"
 	"go-game-backend/pkg/logging"
@@ -52,6 +53,11 @@ func New[TRepos any](
 	}
 }
 
+// Client exposes the underlying Redis client.
+func (s *Storage[TRepos]) Client() *redis.Client {
+	return s.rdb
+}
+
 // Stop closes the underlying Redis client connection.
 func (s *Storage[TRepos]) Stop() error {
 	err := s.rdb.Close()
@@ -84,6 +90,9 @@ func (s *Storage[TRepos]) Raw() *TRepos {
 // supplied function while holding the lock.
 func (s *Storage[TRepos]) DoWithLock(ctx context.Context, key string, ttl time.Duration, f futils.CtxF) error {
 	lock, err := s.locker.Obtain(ctx, key, ttl, nil)
+	if errors.Is(err, redislock.ErrNotObtained) {
+		return fmt.Errorf("failed to obtain lock '%s': %w", key, ErrLockNotObtained)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to obtain lock: %w", err)
 	}
@@ -17,9 +17,9 @@ type Config struct {
 // Builder helps in constructing a Service with optional components such as
 // initialization functions and HTTP/GRPC servers.
 type Builder struct {
-	httpServerSetup *httpServerSetup
-	grpcServerSetup *grpcServerSetup
-	goFuncs         []func(context.Context) error
+	httpServerSetups []*httpServerSetup
+	grpcServerSetup  *grpcServerSetup
+	goFuncs          []func(context.Context) error
 }
 
 // NewBuilder creates a new empty Builder instance.
@@ -28,27 +28,32 @@ func NewBuilder() *Builder {
 }
 
 // WithHTTPServer configures the service to start an HTTP server using the
-// provided configuration and handler factory.
+// provided configuration and handler factory. Calling it again adds another
+// server on its own address, e.g. to keep an admin API off the public
+// listener.
 func (b *Builder) WithHTTPServer(
 	cfg *HTTPServerConfig,
 	handlerFactory func() http.Handler,
 ) *Builder {
-	b.httpServerSetup = &httpServerSetup{
+	b.httpServerSetups = append(b.httpServerSetups, &httpServerSetup{
 		Cfg:            cfg,
 		HandlerFactory: handlerFactory,
-	}
+	})
 	return b
 }
 
 // WithGRPCServer configures the service to start a gRPC server using the
-// provided configuration and setup function.
+// provided configuration, setup function and server options such as
+// interceptors.
 func (b *Builder) WithGRPCServer(
 	cfg *GRPCServerConfig,
 	setupServerFunc func(*grpc.Server),
+	opts ...grpc.ServerOption,
 ) *Builder {
 	b.grpcServerSetup = &grpcServerSetup{
 		Cfg:             cfg,
 		SetupServerFunc: setupServerFunc,
+		Opts:            opts,
 	}
 	return b
 }
@@ -62,7 +67,7 @@ func (b *Builder) WithGo(f func(context.Context) error) *Builder {
 // Build constructs a Service based on the options configured on the Builder.
 func (b *Builder) Build() *Service {
 	return newService(
-		b.httpServerSetup,
+		b.httpServerSetups,
 		b.grpcServerSetup,
 		b.goFuncs,
 	)
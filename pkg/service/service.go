@@ -42,25 +42,26 @@ type GRPCServerConfig struct {
 type grpcServerSetup struct {
 	Cfg             *GRPCServerConfig
 	SetupServerFunc func(*grpc.Server)
+	Opts            []grpc.ServerOption
 }
 
 // Service orchestrates the lifecycle of application components such as HTTP
 // and gRPC servers.
 type Service struct {
-	httpServerSetup *httpServerSetup
-	grpcServerSetup *grpcServerSetup
-	goFuncs         []func(context.Context) error
+	httpServerSetups []*httpServerSetup
+	grpcServerSetup  *grpcServerSetup
+	goFuncs          []func(context.Context) error
 }
 
 func newService(
-	httpServerSetup *httpServerSetup,
+	httpServerSetups []*httpServerSetup,
 	grpcServerSetup *grpcServerSetup,
 	goFuncs []func(context.Context) error,
 ) *Service {
 	return &Service{
-		httpServerSetup: httpServerSetup,
-		grpcServerSetup: grpcServerSetup,
-		goFuncs:         goFuncs,
+		httpServerSetups: httpServerSetups,
+		grpcServerSetup:  grpcServerSetup,
+		goFuncs:          goFuncs,
 	}
 }
 
@@ -89,40 +90,12 @@ func (s *Service) Run(rootCtx context.Context, shutdownTimeout time.Duration) er
 		}
 	})
 
-	if s.httpServerSetup != nil {
-		httpServer := &http.Server{
-			Addr:              s.httpServerSetup.Cfg.Address,
-			ReadHeaderTimeout: s.httpServerSetup.Cfg.ReadHeaderTimeout,
-			Handler:           s.httpServerSetup.HandlerFactory(),
-		}
-
-		g.Go(
-			func() error {
-				if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-					return fmt.Errorf("http server error: %w", err)
-				}
-				return nil
-			},
-		)
-
-		g.Go(func() error {
-			<-errGroupCtx.Done()
-
-			// We intentionally decouple shutdown from the (already-canceled) root context.
-			// A fresh context with a timeout lets the server drain in-flight requests.
-			ctx, cancel := context.WithTimeout(context.Background(), s.httpServerSetup.Cfg.ShutdownTimeout)
-			defer cancel()
-
-			//nolint:contextcheck // shutdown must *not* inherit canceled parent
-			if err := httpServer.Shutdown(ctx); err != nil {
-				return fmt.Errorf("failed to shutdown server: %w", err)
-			}
-			return nil
-		})
+	for _, setup := range s.httpServerSetups {
+		runHTTPServer(errGroupCtx, g, setup)
 	}
 
 	if s.grpcServerSetup != nil {
-		grpcServer := grpc.NewServer()
+		grpcServer := grpc.NewServer(s.grpcServerSetup.Opts...)
 		s.grpcServerSetup.SetupServerFunc(grpcServer)
 
 		g.Go(func() error {
@@ -156,3 +129,37 @@ func (s *Service) Run(rootCtx context.Context, shutdownTimeout time.Duration) er
 	}
 	return nil
 }
+
+// runHTTPServer starts the server described by setup in g and shuts it down
+// once ctx is done.
+func runHTTPServer(ctx context.Context, g *errgroup.Group, setup *httpServerSetup) {
+	httpServer := &http.Server{
+		Addr:              setup.Cfg.Address,
+		ReadHeaderTimeout: setup.Cfg.ReadHeaderTimeout,
+		Handler:           setup.HandlerFactory(),
+	}
+
+	g.Go(
+		func() error {
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("http server %s error: %w", setup.Cfg.Address, err)
+			}
+			return nil
+		},
+	)
+
+	g.Go(func() error {
+		<-ctx.Done()
+
+		// We intentionally decouple shutdown from the (already-canceled) root context.
+		// A fresh context with a timeout lets the server drain in-flight requests.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), setup.Cfg.ShutdownTimeout)
+		defer cancel()
+
+		//nolint:contextcheck // shutdown must *not* inherit canceled parent
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shutdown server %s: %w", setup.Cfg.Address, err)
+		}
+		return nil
+	})
+}
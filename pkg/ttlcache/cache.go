@@ -0,0 +1,77 @@
+// Package ttlcache provides a small in-memory cache whose entries expire at a
+// given time.
+package ttlcache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache is a size-bounded map of values with individual expiration times. It
+// is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]entry[V]
+	maxSize int
+	now     func() time.Time
+}
+
+// New creates a Cache holding at most maxSize entries.
+func New[K comparable, V any](maxSize int) *Cache[K, V] {
+	return &Cache[K, V]{
+		entries: make(map[K]entry[V]),
+		maxSize: maxSize,
+		now:     time.Now,
+	}
+}
+
+// Get returns the value stored under key if it has not expired yet.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if !c.now().Before(e.expiresAt) {
+		delete(c.entries, key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key until expiresAt. When the cache is full, expired
+// entries are evicted first; if none are, the value is not stored.
+func (c *Cache[K, V]) Set(key K, value V, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if !now.Before(expiresAt) {
+		return
+	}
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxSize {
+		c.evictExpired(now)
+		if len(c.entries) >= c.maxSize {
+			return
+		}
+	}
+	c.entries[key] = entry[V]{value: value, expiresAt: expiresAt}
+}
+
+func (c *Cache[K, V]) evictExpired(now time.Time) {
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package ttlcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New[string, int](2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1, now.Add(time.Second))
+	c.Set("b", 2, now.Add(time.Minute))
+	c.Set("c", 3, now.Add(time.Minute))
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %v; want 1, true", v, ok)
+	}
+	if _, ok := c.Get("c"); ok {
+		t.Fatal("entry stored over the size limit")
+	}
+
+	now = now.Add(2 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expired entry returned")
+	}
+
+	c.Set("c", 3, now.Add(time.Minute))
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Fatalf("Get(c) = %d, %v; want 3, true", v, ok)
+	}
+}
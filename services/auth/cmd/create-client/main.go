@@ -0,0 +1,114 @@
+// Command auth-create-client registers a service client allowed to obtain
+// service tokens from the auth service with the client credentials grant.
+// The generated secret is printed once and cannot be recovered later.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"go-game-backend/pkg/service"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/internal/services/password"
+	"log"
+	"os"
+	"strings"
+
+	postgresstore "go-game-backend/pkg/postgres"
+	postgresrepo "go-game-backend/services/auth/internal/repository/postgres"
+)
+
+// secretSize is the number of random bytes in a generated client secret.
+const secretSize = 32
+
+// Config holds the PostgreSQL and password hashing configuration of the auth
+// service.
+type Config struct {
+	Postgres *postgresstore.Config `yaml:"postgres"`
+	Password *password.Config      `yaml:"password"`
+}
+
+func main() {
+	clientID := flag.String("id", "", "client ID, e.g. the name of the calling service")
+	scopes := flag.String("scopes", "", "comma separated scopes granted to the client")
+	description := flag.String("description", "", "what the client is used for")
+
+	cfg, err := service.LoadConfig[Config](
+		"./configs/default.yaml",
+		nil,
+		func(err error) { log.Fatal(err) },
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *clientID == "" {
+		log.Fatal("-id is required")
+	}
+
+	secret, err := run(context.Background(), cfg, dto.ServiceClient{
+		ClientID:    *clientID,
+		Scopes:      splitScopes(*scopes),
+		Description: *description,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := fmt.Fprintf(os.Stdout, "client_id: %s\nclient_secret: %s\n", *clientID, secret); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run stores the client with a newly generated secret and returns the
+// secret.
+func run(ctx context.Context, cfg *Config, client dto.ServiceClient) (string, error) {
+	hasher, err := password.NewHasher(cfg.Password)
+	if err != nil {
+		return "", fmt.Errorf("create password hasher: %w", err)
+	}
+
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read random bytes: %w", err)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+
+	client.SecretHash, err = hasher.Hash(secret)
+	if err != nil {
+		return "", fmt.Errorf("hash client secret: %w", err)
+	}
+
+	storage, err := postgresstore.New(ctx, cfg.Postgres, postgresrepo.NewRepos)
+	if err != nil {
+		return "", fmt.Errorf("create storage: %w", err)
+	}
+	defer func() {
+		if err := storage.Stop(); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	err = storage.Raw().ServiceClient().AddServiceClient(ctx, client)
+	if errors.Is(err, services.ErrAlreadyExists) {
+		return "", fmt.Errorf("service client '%s' already exists", client.ClientID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("add service client: %w", err)
+	}
+	return secret, nil
+}
+
+func splitScopes(s string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(s, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"go-game-backend/pkg/jwks"
 	"go-game-backend/pkg/jwtfactory"
 	"go-game-backend/pkg/kafka"
 	"go-game-backend/pkg/logging"
@@ -15,15 +16,25 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-chi/jwtauth/v5"
+	"google.golang.org/grpc"
 
+	authv1 "go-game-backend/gen/auth/v1"
 	postgresstore "go-game-backend/pkg/postgres"
 	redisstore "go-game-backend/pkg/redis"
+	"go-game-backend/services/auth/internal/clientinfo"
+	grpchand "go-game-backend/services/auth/internal/handlers/grpc"
 	httphand "go-game-backend/services/auth/internal/handlers/http"
+	"go-game-backend/services/auth/internal/jobs"
+	"go-game-backend/services/auth/internal/ratelimit"
 	postgresrepo "go-game-backend/services/auth/internal/repository/postgres"
 	redisrepo "go-game-backend/services/auth/internal/repository/redis"
 	authsvc "go-game-backend/services/auth/internal/services/auth"
+	"go-game-backend/services/auth/internal/services/oidc"
+	"go-game-backend/services/auth/internal/services/password"
+	"go-game-backend/services/auth/internal/services/secretbox"
 	tknfactory "go-game-backend/services/auth/internal/services/token"
+	"go-game-backend/services/auth/pkg/authverify"
+	"go-game-backend/services/auth/pkg/models"
 	playerslocker "go-game-backend/services/players/pkg/locker"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -31,21 +42,24 @@ import (
 
 // Config holds the configuration for the auth service.
 type Config struct {
-	Service         *service.Config           `yaml:"service"`
-	HTTP            *service.HTTPServerConfig `yaml:"http"`
-	AuthService     *authsvc.Config           `yaml:"auth-service"`
-	Redis           *redisstore.Config        `yaml:"redis"`
-	TokenFactory    *tknfactory.Config        `yaml:"token-factory"`
-	Postgres        *postgresstore.Config     `yaml:"postgres"`
-	Kafka           *kafka.ForwarderConfig    `yaml:"kafka"`
-	JWTConfig       *JWTConfig                `yaml:"jwt"`
-	ShutdownTimeout time.Duration             `yaml:"shutdown-timeout"`
-}
-
-// JWTConfig holds the configuration for the JWT generation.
-type JWTConfig struct {
-	Algorithm string `yaml:"algorithm"`
-	Secret    string `yaml:"secret"`
+	Service         *service.Config               `yaml:"service"`
+	HTTP            *service.HTTPServerConfig     `yaml:"http"`
+	AdminHTTP       *service.HTTPServerConfig     `yaml:"admin-http"`
+	GRPC            *service.GRPCServerConfig     `yaml:"grpc"`
+	AuthService     *authsvc.Config               `yaml:"auth-service"`
+	Redis           *redisstore.Config            `yaml:"redis"`
+	TokenFactory    *tknfactory.Config            `yaml:"token-factory"`
+	Postgres        *postgresstore.Config         `yaml:"postgres"`
+	Kafka           *kafka.ForwarderConfig        `yaml:"kafka"`
+	JWTConfig       *jwks.Config                  `yaml:"jwt"`
+	Password        *password.Config              `yaml:"password"`
+	OIDC            *oidc.Config                  `yaml:"oidc"`
+	MFAEncryption   *secretbox.Config             `yaml:"mfa-encryption"`
+	RateLimit       *ratelimit.Config             `yaml:"rate-limit"`
+	Idempotency     *redisstore.IdempotencyConfig `yaml:"idempotency"`
+	DeletionPurge   *jobs.DeletionPurgerConfig    `yaml:"deletion-purge"`
+	OutboxPrune     *outboxpkg.PrunerConfig       `yaml:"outbox-prune"`
+	ShutdownTimeout time.Duration                 `yaml:"shutdown-timeout"`
 }
 
 func main() {
@@ -79,8 +93,11 @@ func main() {
 }
 
 func run(ctx context.Context, cfg *Config, logger *logging.ZapLogger) error {
-	tokenAuth := jwtauth.New(cfg.JWTConfig.Algorithm, []byte(cfg.JWTConfig.Secret), nil)
-	jwtFactory := jwtfactory.New(tokenAuth)
+	keySet, err := jwks.Load(cfg.JWTConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load jwt keys: %w", err)
+	}
+	jwtFactory := jwtfactory.New(keySet)
 	tknFactory := tknfactory.New(jwtFactory, cfg.TokenFactory)
 
 	rxStorage := redisstore.New(cfg.Redis, logger, redisrepo.NewRepos)
@@ -97,30 +114,208 @@ func run(ctx context.Context, cfg *Config, logger *logging.ZapLogger) error {
 	outboxRepo := outboxpkg.NewRepository(pgStorage.Pool())
 	writer := kafka.NewWriter(cfg.Kafka.Brokers)
 	defer service.Close(ctx, writer, "kafka writer", logger)
-	forwarder := outboxpkg.NewForwarder(outboxRepo, writer, cfg.Kafka.PollInterval, cfg.Kafka.BatchSize)
+	outboxListener := outboxpkg.NewListener(pgStorage.Pool(), cfg.Kafka.PollInterval, logger)
+	forwarder := outboxpkg.NewForwarder(outboxRepo, writer, outboxListener, cfg.Kafka, logger)
+	outboxPruner := outboxpkg.NewPruner(outboxRepo, cfg.OutboxPrune, logger)
 
 	playerLocker := playerslocker.NewFromStorage(rxStorage, cfg.AuthService.PlayerLockTTL)
 
-	authService := authsvc.New(cfg.AuthService, pgStore, rxStore, playerLocker, tknFactory)
+	passwordHasher, err := password.NewHasher(cfg.Password)
+	if err != nil {
+		return fmt.Errorf("failed to create password hasher: %w", err)
+	}
+
+	idTokenVerifier, err := oidc.NewVerifier(ctx, cfg.OIDC)
+	if err != nil {
+		return fmt.Errorf("failed to create oidc verifier: %w", err)
+	}
+
+	mfaSecretBox, err := secretbox.New(cfg.MFAEncryption)
+	if err != nil {
+		return fmt.Errorf("failed to create mfa secret box: %w", err)
+	}
+
+	authService := authsvc.New(
+		cfg.AuthService,
+		pgStore,
+		rxStore,
+		playerLocker,
+		tknFactory,
+		passwordHasher,
+		idTokenVerifier,
+		mfaSecretBox,
+		logger,
+	)
+	verifier := authverify.New(keySet, authService)
+	deletionPurger := jobs.NewDeletionPurger(authService, cfg.DeletionPurge, logger)
 	httpHandler := httphand.New(authService, logger)
+	emailHandler := httphand.NewEmailHandler(httpHandler, authService)
+	identityHandler := httphand.NewIdentityHandler(httpHandler, authService)
+	oidcHandler := httphand.NewOIDCHandler(httpHandler, authService)
+	mfaHandler := httphand.NewMFAHandler(httpHandler, authService)
+	sessionHandler := httphand.NewSessionHandler(httpHandler, authService)
+	accountHandler := httphand.NewAccountHandler(httpHandler, authService)
+	serviceClientHandler := httphand.NewServiceClientHandler(httpHandler, authService)
+	adminHandler := httphand.NewAdminHandler(httpHandler, authService)
+	loginTokenHandler := httphand.NewLoginTokenHandler(httpHandler, authService)
+	grpcHandler := grpchand.New(authService, logger)
+	moderationHandler := grpchand.NewModerationHandler(grpcHandler, authService)
+	jwksHandler := httphand.NewJWKSHandler(keySet, logger)
+	redisLimiter := redisstore.NewRateLimiter(rxStorage.Client())
+	rateLimiter := httphand.NewRateLimiter(redisLimiter, cfg.RateLimit)
+	grpcRateLimits, err := grpchand.RateLimitRules(cfg.RateLimit)
+	if err != nil {
+		return fmt.Errorf("failed to build grpc rate limits: %w", err)
+	}
+	idempotency := redisstore.NewIdempotencyStore(rxStorage.Client(), cfg.Idempotency)
+
+	// Internal endpoints are only reachable with a service token. Moderation
+	// methods without a rule of their own require the moderation scope.
+	serviceScopes := authverify.ScopeRules{
+		authv1.AuthService_IntrospectToken_FullMethodName:            {models.ScopeIntrospect},
+		"/" + authv1.ModerationService_ServiceDesc.ServiceName + "/": {models.ScopeModeration},
+		authv1.ModerationService_ListAuditLog_FullMethodName:         {models.ScopeAuditRead},
+	}
+
+	router := gin.Default()
+	if err := router.SetTrustedProxies(cfg.RateLimit.TrustedProxies); err != nil {
+		return fmt.Errorf("failed to set trusted proxies: %w", err)
+	}
+	router.Use(clientinfo.GinMiddleware())
 
 	serv := service.NewBuilder().
+		WithGo(func(ctx context.Context) error {
+			outboxListener.Run(ctx)
+			return nil
+		}).
 		WithGo(func(ctx context.Context) error {
 			forwarder.Run(ctx)
 			return nil
 		}).
+		WithGo(func(ctx context.Context) error {
+			outboxPruner.Run(ctx)
+			return nil
+		}).
+		WithGo(func(ctx context.Context) error {
+			deletionPurger.Run(ctx)
+			return nil
+		}).
 		WithHTTPServer(cfg.HTTP, func() http.Handler {
-			router := gin.Default()
+			router.GET("/.well-known/jwks.json", jwksHandler.JWKS)
 
 			api := router.Group("/api/v1")
 			{
-				api.POST("/login", httpHandler.Login)
-				api.POST("/register", httpHandler.Register)
-				api.POST("/refresh", httpHandler.RefreshToken)
+				api.POST("/login", rateLimiter.ByField("login", "login_token", ratelimit.UUIDValue), httpHandler.Login)
+				// replays of a stored response skip the rate limit
+				api.POST(
+					"/register",
+					idempotency.GinMiddleware("register"),
+					rateLimiter.ByIP("register"),
+					httpHandler.Register,
+				)
+				api.POST("/refresh", rateLimiter.ByField("refresh", "refresh_token", ratelimit.UUIDValue), httpHandler.RefreshToken)
+				api.POST("/logout", httpHandler.Logout)
+				api.POST("/logout/all", httpHandler.LogoutAll)
+				api.POST("/introspect", verifier.ServiceGinMiddleware(models.ScopeIntrospect), httpHandler.Introspect)
+				api.POST("/oauth/token", rateLimiter.ByIP("service-token"), serviceClientHandler.Token)
+			}
+
+			email := router.Group("/api/v1/email")
+			{
+				email.POST(
+					"/register",
+					idempotency.GinMiddleware("email-register"),
+					rateLimiter.ByIP("register"),
+					emailHandler.Register,
+				)
+				email.POST("/login", rateLimiter.ByField("email-login", "email", ratelimit.EmailValue), emailHandler.Login)
+				email.POST("/link", verifier.GinMiddleware(), emailHandler.Link)
+				email.POST("/verify", rateLimiter.ByIP("email-token"), emailHandler.Verify)
+				email.POST("/password-reset", rateLimiter.ByField("password-reset", "email", ratelimit.EmailValue), emailHandler.RequestPasswordReset)
+				email.POST("/password-reset/confirm", rateLimiter.ByIP("email-token"), emailHandler.ResetPassword)
+			}
+
+			router.POST("/api/v1/oidc/login", rateLimiter.ByIP("oidc-login"), oidcHandler.Login)
+
+			mfa := router.Group("/api/v1/mfa")
+			{
+				mfa.POST("/verify", rateLimiter.ByField("mfa-verify", "mfa_token", ratelimit.TokenValue), mfaHandler.Verify)
+				mfa.POST("/totp/enroll", verifier.GinMiddleware(), mfaHandler.EnrollTOTP)
+				mfa.POST("/totp/confirm", verifier.GinMiddleware(), rateLimiter.ByUser("mfa-code"), mfaHandler.ConfirmTOTP)
+				mfa.POST("/totp/disable", verifier.GinMiddleware(), rateLimiter.ByUser("mfa-code"), mfaHandler.DisableTOTP)
+			}
+
+			sessions := router.Group("/api/v1/sessions", verifier.GinMiddleware(), rateLimiter.ByUser("sessions"))
+			{
+				sessions.GET("", sessionHandler.List)
+				sessions.POST("/revoke", sessionHandler.Revoke)
+			}
+
+			identities := router.Group("/api/v1/identities", verifier.GinMiddleware(), rateLimiter.ByUser("identities"))
+			{
+				identities.GET("", identityHandler.List)
+				identities.POST("/guest", identityHandler.LinkGuest)
+				identities.POST("/oidc", oidcHandler.Link)
+				identities.POST("/unlink", identityHandler.Unlink)
+				identities.POST("/merge", identityHandler.MergeGuest)
+			}
+
+			router.POST(
+				"/api/v1/login-token/rotate",
+				verifier.GinMiddleware(),
+				rateLimiter.ByUser("identities"),
+				loginTokenHandler.Rotate,
+			)
+
+			transfer := router.Group("/api/v1/transfer-codes")
+			{
+				transfer.POST("", verifier.GinMiddleware(), rateLimiter.ByUser("transfer-code"), loginTokenHandler.CreateTransferCode)
+				transfer.POST("/redeem", rateLimiter.ByIP("transfer-redeem"), loginTokenHandler.RedeemTransferCode)
+			}
+
+			account := router.Group("/api/v1/account", verifier.GinMiddleware(), rateLimiter.ByUser("account"))
+			{
+				account.POST("/delete", accountHandler.Delete)
+				account.GET("/export", accountHandler.Export)
 			}
 
 			return router
 		}).
+		WithHTTPServer(cfg.AdminHTTP, func() http.Handler {
+			// The admin API of support tooling listens apart from the public
+			// API so that it can be kept off the public network.
+			adminRouter := gin.Default()
+			adminRouter.Use(clientinfo.GinMiddleware())
+
+			players := adminRouter.Group(
+				"/admin/v1/players",
+				verifier.ServiceGinMiddleware(models.ScopeAdmin),
+				httphand.AdminActor(),
+			)
+			{
+				players.GET("", adminHandler.FindPlayer)
+				players.GET("/:id", adminHandler.GetPlayer)
+				players.GET("/:id/audit-log", adminHandler.AuditLog)
+				players.POST("/:id/sessions/revoke", adminHandler.RevokeSession)
+				players.POST("/:id/sessions/revoke-all", adminHandler.RevokeAllSessions)
+				players.POST("/:id/ban", adminHandler.Ban)
+				players.POST("/:id/unban", adminHandler.Unban)
+				players.POST("/:id/login-token", adminHandler.ReissueLoginToken)
+			}
+
+			return adminRouter
+		}).
+		WithGRPCServer(
+			cfg.GRPC,
+			func(s *grpc.Server) {
+				authv1.RegisterAuthServiceServer(s, grpcHandler)
+				authv1.RegisterModerationServiceServer(s, moderationHandler)
+			},
+			grpc.ChainUnaryInterceptor(
+				verifier.ServiceUnaryServerInterceptor(serviceScopes),
+				redisLimiter.UnaryServerInterceptor(grpcRateLimits),
+			),
+		).
 		Build()
 
 	if err := serv.Run(ctx, cfg.ShutdownTimeout); err != nil {
@@ -0,0 +1,104 @@
+// Command auth-outbox-dead lists the outbox events moved to the dead-letter
+// state after failing to be published too many times and requeues them once
+// the cause, e.g. a missing topic, is fixed.
+//
+// Without flags it lists the dead events. With -requeue it requeues the
+// event with the given ID, with -requeue-all all dead events, or only those
+// of -topic.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-game-backend/pkg/outbox"
+	"go-game-backend/pkg/service"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	postgresstore "go-game-backend/pkg/postgres"
+)
+
+// Config holds the PostgreSQL configuration of the auth service.
+type Config struct {
+	Postgres *postgresstore.Config `yaml:"postgres"`
+}
+
+func main() {
+	requeueID := flag.Int64("requeue", 0, "ID of the dead event to requeue")
+	requeueAll := flag.Bool("requeue-all", false, "requeue all dead events")
+	topic := flag.String("topic", "", "requeue only the dead events of this topic, with -requeue-all")
+	limit := flag.Int("limit", 100, "maximum number of dead events to list")
+
+	cfg, err := service.LoadConfig[Config](
+		"./configs/default.yaml",
+		nil,
+		func(err error) { log.Fatal(err) },
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	storage, err := postgresstore.New(ctx, cfg.Postgres, outbox.NewRepository)
+	if err != nil {
+		log.Fatal(fmt.Errorf("create storage: %w", err))
+	}
+	defer func() {
+		if err := storage.Stop(); err != nil {
+			log.Print(err)
+		}
+	}()
+	repo := storage.Raw()
+
+	switch {
+	case *requeueID != 0:
+		err = requeue(ctx, repo, *requeueID)
+	case *requeueAll:
+		err = requeueAllEvents(ctx, repo, *topic)
+	default:
+		err = list(ctx, repo, int32(*limit))
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func requeue(ctx context.Context, repo *outbox.Repository, id int64) error {
+	err := repo.Requeue(ctx, id)
+	if errors.Is(err, outbox.ErrEventNotFound) {
+		return fmt.Errorf("no dead event with ID %d", id)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(os.Stdout, "requeued event %d\n", id)
+	return err
+}
+
+func requeueAllEvents(ctx context.Context, repo *outbox.Repository, topic string) error {
+	n, err := repo.RequeueAll(ctx, topic)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(os.Stdout, "requeued %d events\n", n)
+	return err
+}
+
+func list(ctx context.Context, repo *outbox.Repository, limit int32) error {
+	events, err := repo.ListDead(ctx, limit)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTOPIC\tATTEMPTS\tCREATED\tDEAD\tLAST ERROR")
+	for _, e := range events {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n",
+			e.ID, e.Topic, e.Attempts,
+			e.CreatedAt.Format(time.RFC3339), e.DeadAt.Format(time.RFC3339), e.LastError)
+	}
+	return w.Flush()
+}
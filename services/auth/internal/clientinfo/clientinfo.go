@@ -0,0 +1,140 @@
+// Package clientinfo carries details about the device a request comes from,
+// recorded with the sessions started by the request and in the audit log, and
+// the ID of the request, sent with the events it publishes.
+package clientinfo
+
+import (
+	"context"
+	"net"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Headers clients report their platform and version in. gRPC clients use the
+// lowercase names as metadata keys. Admin tools report the support operator
+// they act for in OperatorHeader. The ID of a request is taken from
+// RequestIDHeader, or generated if missing, and returned in the response.
+const (
+	PlatformHeader   = "X-Platform"
+	AppVersionHeader = "X-App-Version"
+	OperatorHeader   = "X-Operator"
+	RequestIDHeader  = "X-Request-ID"
+)
+
+// userAgentKey is the gRPC metadata key of the client user agent.
+const userAgentKey = "user-agent"
+
+// Bounds of client supplied values stored with a session or audit entry.
+const (
+	maxValueLength     = 64
+	maxUserAgentLength = 256
+)
+
+type ctxKey string
+
+const infoCtxKey ctxKey = "clientInfo"
+
+// Info describes the device a request comes from and identifies the request
+// with RequestID. Requests made through the
+// admin API also carry the service client making them as Actor, and the
+// support operator the client reports acting for as Operator.
+type Info struct {
+	Platform   string
+	AppVersion string
+	IP         string
+	UserAgent  string
+	Actor      string
+	Operator   string
+	RequestID  string
+}
+
+// WithInfo returns a context carrying info.
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoCtxKey, info)
+}
+
+// FromContext returns the client info stored in ctx, or an empty Info.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(infoCtxKey).(Info)
+	return info
+}
+
+// GinMiddleware stores the client info of the request in its context.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := Info{
+			Platform:   truncate(c.GetHeader(PlatformHeader)),
+			AppVersion: truncate(c.GetHeader(AppVersionHeader)),
+			IP:         c.ClientIP(),
+			UserAgent:  truncateTo(c.Request.UserAgent(), maxUserAgentLength),
+			RequestID:  requestID(c.GetHeader(RequestIDHeader)),
+		}
+		c.Header(RequestIDHeader, info.RequestID)
+		c.Request = c.Request.WithContext(WithInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
+
+// WithActor returns a context carrying the client info of ctx with actor and
+// the operator reported in the request as the ones making the request.
+func WithActor(c *gin.Context, actor string) context.Context {
+	info := FromContext(c.Request.Context())
+	info.Actor = actor
+	info.Operator = truncate(c.GetHeader(OperatorHeader))
+	return WithInfo(c.Request.Context(), info)
+}
+
+// FromIncomingGRPC returns a context carrying the client info of an incoming
+// gRPC call.
+func FromIncomingGRPC(ctx context.Context) context.Context {
+	var info Info
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(PlatformHeader); len(v) > 0 {
+		info.Platform = truncate(v[0])
+	}
+	if v := md.Get(AppVersionHeader); len(v) > 0 {
+		info.AppVersion = truncate(v[0])
+	}
+	if v := md.Get(userAgentKey); len(v) > 0 {
+		info.UserAgent = truncateTo(v[0], maxUserAgentLength)
+	}
+	var reqID string
+	if v := md.Get(RequestIDHeader); len(v) > 0 {
+		reqID = v[0]
+	}
+	info.RequestID = requestID(reqID)
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		info.IP = host
+	}
+
+	return WithInfo(ctx, info)
+}
+
+// requestID returns the request ID reported by the client, or a new one if
+// it reported none.
+func requestID(reported string) string {
+	if reported == "" {
+		return uuid.NewString()
+	}
+	return truncate(reported)
+}
+
+func truncate(s string) string {
+	return truncateTo(s, maxValueLength)
+}
+
+func truncateTo(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// AuditEntry records a security-relevant action. A zero PlayerID means the
+// action could not be attributed to a player, e.g. a login attempt with
+// unknown credentials. Details holds event specific fields as a JSON object.
+type AuditEntry struct {
+	ID        int64
+	PlayerID  int64
+	Event     string
+	IP        string
+	UserAgent string
+	Details   []byte
+	CreatedAt time.Time
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// Ban blocks a player from signing in. A zero ExpiresAt means the ban is
+// permanent; a non-zero LiftedAt means it was lifted early.
+type Ban struct {
+	ID        int64
+	PlayerID  int64
+	Reason    string
+	IssuedBy  string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	LiftedAt  time.Time
+}
@@ -0,0 +1,9 @@
+package dto
+
+// EmailCredential is an email and password pair bound to a player.
+type EmailCredential struct {
+	PlayerID     int64
+	Email        string
+	PasswordHash string
+	Verified     bool
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+// Identity is a credential a player can sign in with.
+type Identity struct {
+	Provider  string
+	Subject   string
+	CreatedAt time.Time
+}
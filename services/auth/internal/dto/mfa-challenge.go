@@ -0,0 +1,8 @@
+package dto
+
+// MFAChallenge is a pending login waiting for a second factor.
+type MFAChallenge struct {
+	UserID int64 `redis:"user_id"`
+	// Attempts counts the rejected codes presented for the challenge.
+	Attempts int64 `redis:"attempts"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// Player is the account a player's credentials belong to. A non-zero
+// DeleteAfter means the player asked for the account to be deleted, which
+// happens once that time passes.
+type Player struct {
+	ID                  int64
+	DeletionRequestedAt time.Time
+	DeleteAfter         time.Time
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// ServiceClient is an internal caller allowed to obtain service tokens with
+// the client credentials grant. A non-zero DisabledAt means the client can no
+// longer obtain tokens.
+type ServiceClient struct {
+	ClientID    string
+	SecretHash  string
+	Scopes      []string
+	Description string
+	CreatedAt   time.Time
+	DisabledAt  time.Time
+}
@@ -5,6 +5,8 @@ import "github.com/google/uuid"
 
 // SessionInfo contains session token information associated with a user.
 type SessionInfo struct {
-	UserID       int64
-	SessionToken uuid.UUID
+	UserID       int64     `redis:"user_id"`
+	SessionToken uuid.UUID `redis:"session_token"`
+	// Rotated is set once the refresh token has been exchanged for a new one.
+	Rotated bool `redis:"rotated"`
 }
@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/google/uuid"
+
+// Session describes a session of a user and the device it was started on.
+type Session struct {
+	Token          uuid.UUID `redis:"-"`
+	UserID         int64     `redis:"user_id"`
+	Platform       string    `redis:"platform"`
+	AppVersion     string    `redis:"app_version"`
+	IP             string    `redis:"ip"`
+	CreatedAtUnix  int64     `redis:"created_at"`
+	LastUsedAtUnix int64     `redis:"last_used_at"`
+	ExpiresAtUnix  int64     `redis:"expires_at"`
+}
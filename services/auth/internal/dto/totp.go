@@ -0,0 +1,8 @@
+package dto
+
+// TOTP is the encrypted TOTP secret of a player together with its state.
+type TOTP struct {
+	SecretEncrypted []byte
+	Confirmed       bool
+	LastUsedStep    int64
+}
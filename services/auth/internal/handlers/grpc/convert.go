@@ -0,0 +1,58 @@
+package grpchand
+
+import (
+	"go-game-backend/pkg/protoutils"
+	"go-game-backend/services/auth/pkg/models"
+
+	"google.golang.org/protobuf/proto"
+
+	authv1 "go-game-backend/gen/auth/v1"
+)
+
+func tokenPairToProto(resp *models.LoginRespose) *authv1.TokenPair {
+	if resp.MFAChallenge != nil {
+		return &authv1.TokenPair{
+			MfaChallenge: &authv1.MFAChallenge{
+				MfaToken:  proto.String(resp.MFAChallenge.MFAToken),
+				ExpiresAt: proto.Int64(resp.MFAChallenge.ExpiresAtUnix),
+			},
+		}
+	}
+	return &authv1.TokenPair{
+		AccessToken:  proto.String(resp.AccessToken),
+		RefreshToken: protoutils.UUIDToProto(resp.RefreshToken),
+		ExpiresAt:    proto.Int64(resp.ExpiresAtUnix),
+	}
+}
+
+func introspectionToProto(resp *models.IntrospectResponse) *authv1.IntrospectTokenResponse {
+	res := &authv1.IntrospectTokenResponse{
+		Active: proto.Bool(resp.Active),
+	}
+	// Claims are only known for tokens with a valid signature.
+	if resp.UserID != 0 {
+		res.UserId = proto.Int64(resp.UserID)
+		res.SessionToken = protoutils.UUIDToProto(resp.SessionToken)
+		res.IssuedAt = proto.Int64(resp.IssuedAtUnix)
+		res.ExpiresAt = proto.Int64(resp.ExpiresAtUnix)
+	}
+	return res
+}
+
+func auditLogToProto(resp *models.AuditLogResponse) *authv1.ListAuditLogResponse {
+	res := &authv1.ListAuditLogResponse{
+		Entries: make([]*authv1.AuditEntry, 0, len(resp.Entries)),
+	}
+	for _, entry := range resp.Entries {
+		res.Entries = append(res.Entries, &authv1.AuditEntry{
+			Id:        proto.Int64(entry.ID),
+			UserId:    proto.Int64(entry.UserID),
+			Event:     proto.String(entry.Event),
+			Ip:        proto.String(entry.IP),
+			UserAgent: proto.String(entry.UserAgent),
+			Details:   proto.String(string(entry.Details)),
+			CreatedAt: proto.Int64(entry.CreatedAtUnix),
+		})
+	}
+	return res
+}
@@ -0,0 +1,60 @@
+package grpchand
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.uber.org/zap"
+)
+
+// errorMapping binds a service error to the gRPC status code reported to the
+// client.
+type errorMapping struct {
+	err  error
+	code codes.Code
+}
+
+var errorMappings = []errorMapping{
+	{services.ErrValidationCredentials, codes.Unauthenticated},
+	{services.ErrLoginTokenTaken, codes.AlreadyExists},
+	{services.ErrInvalidMFACode, codes.Unauthenticated},
+	{services.ErrInvalidMFAToken, codes.Unauthenticated},
+	{services.ErrInvalidRefreshToken, codes.Unauthenticated},
+	{services.ErrRefreshTokenReused, codes.Unauthenticated},
+	{services.ErrPlayerBanned, codes.PermissionDenied},
+	{services.ErrPlayerNotFound, codes.NotFound},
+	{services.ErrPlayerNotBanned, codes.FailedPrecondition},
+	{services.ErrBanExpired, codes.InvalidArgument},
+	{services.ErrPlayerLocked, codes.Aborted},
+	// checked before the storage errors, which may wrap it
+	{context.DeadlineExceeded, codes.DeadlineExceeded},
+	{services.ErrStorageUnavailable, codes.Unavailable},
+}
+
+// toStatus logs err and converts it to the gRPC status matching it. Errors
+// without a mapping are reported as internal errors without exposing details.
+func (h *Handler) toStatus(ctx context.Context, msg string, err error) error {
+	for _, m := range errorMappings {
+		if !errors.Is(err, m.err) {
+			continue
+		}
+		if m.code == codes.Unavailable || m.code == codes.DeadlineExceeded {
+			h.logger.ErrorCtx(ctx, msg, zap.Error(err))
+		} else {
+			h.logger.InfoCtx(ctx, msg, zap.Error(err))
+		}
+		return status.Error(m.code, m.err.Error())
+	}
+
+	h.logger.ErrorCtx(ctx, msg, zap.Error(err))
+	return status.Error(codes.Internal, "internal error")
+}
+
+func invalidArgument(field string, err error) error {
+	return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid %s: %v", field, err))
+}
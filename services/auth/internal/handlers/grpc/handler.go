@@ -0,0 +1,186 @@
+// Package grpchand contains gRPC handlers for the auth service.
+package grpchand
+
+import (
+	"context"
+	"go-game-backend/pkg/logging"
+	"go-game-backend/pkg/protoutils"
+	"go-game-backend/services/auth/internal/clientinfo"
+	"go-game-backend/services/auth/pkg/authverify"
+	"go-game-backend/services/auth/pkg/models"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	authv1 "go-game-backend/gen/auth/v1"
+)
+
+// Logic defines the business logic required by the gRPC handler.
+type Logic interface {
+	Register(ctx context.Context, req *models.RegisterRequest) (resp *models.LoginRespose, err error)
+	Login(ctx context.Context, req *models.LoginRequest) (resp *models.LoginRespose, err error)
+	RefreshToken(ctx context.Context, req *models.RefreshTokenRequest) (resp *models.LoginRespose, err error)
+	VerifyMFA(ctx context.Context, req *models.MFAVerifyRequest) (*models.LoginRespose, error)
+	Logout(ctx context.Context, req *models.LogoutRequest) error
+	LogoutAll(ctx context.Context, req *models.LogoutRequest) error
+	IntrospectToken(ctx context.Context, req *models.IntrospectRequest) (*models.IntrospectResponse, error)
+	GetSessions(ctx context.Context, userID int64) ([]models.Session, error)
+}
+
+// Handler implements the auth.v1.AuthService gRPC service.
+type Handler struct {
+	authv1.UnimplementedAuthServiceServer
+
+	logic  Logic
+	logger *logging.ZapLogger
+}
+
+// New creates a new gRPC handler with the provided logic implementation and logger.
+func New(logic Logic, logger *logging.ZapLogger) *Handler {
+	return &Handler{
+		logic:  logic,
+		logger: logger,
+	}
+}
+
+// Register handles user registration requests.
+func (h *Handler) Register(ctx context.Context, req *authv1.RegisterRequest) (*authv1.TokenPair, error) {
+	loginToken, err := protoutils.UUIDFromProto(req.GetLoginToken())
+	if err != nil {
+		return nil, invalidArgument("login_token", err)
+	}
+
+	resp, err := h.logic.Register(clientinfo.FromIncomingGRPC(ctx), &models.RegisterRequest{LoginToken: loginToken})
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to register", err)
+	}
+
+	return tokenPairToProto(resp), nil
+}
+
+// Login processes user login requests.
+func (h *Handler) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.TokenPair, error) {
+	loginToken, err := protoutils.UUIDFromProto(req.GetLoginToken())
+	if err != nil {
+		return nil, invalidArgument("login_token", err)
+	}
+
+	resp, err := h.logic.Login(clientinfo.FromIncomingGRPC(ctx), &models.LoginRequest{LoginToken: loginToken})
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to login", err)
+	}
+
+	return tokenPairToProto(resp), nil
+}
+
+// VerifyMFA completes a login that returned an MFA token.
+func (h *Handler) VerifyMFA(ctx context.Context, req *authv1.VerifyMFARequest) (*authv1.TokenPair, error) {
+	if req.GetMfaToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "mfa_token is required")
+	}
+	if req.GetCode() == "" && req.GetRecoveryCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code or recovery_code is required")
+	}
+
+	resp, err := h.logic.VerifyMFA(clientinfo.FromIncomingGRPC(ctx), &models.MFAVerifyRequest{
+		MFAToken: req.GetMfaToken(),
+		MFACodeRequest: models.MFACodeRequest{
+			Code:         req.GetCode(),
+			RecoveryCode: req.GetRecoveryCode(),
+		},
+	})
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to verify mfa", err)
+	}
+
+	return tokenPairToProto(resp), nil
+}
+
+// Refresh handles token refresh requests.
+func (h *Handler) Refresh(ctx context.Context, req *authv1.RefreshRequest) (*authv1.TokenPair, error) {
+	refreshToken, err := protoutils.UUIDFromProto(req.GetRefreshToken())
+	if err != nil {
+		return nil, invalidArgument("refresh_token", err)
+	}
+
+	resp, err := h.logic.RefreshToken(clientinfo.FromIncomingGRPC(ctx), &models.RefreshTokenRequest{RefreshToken: refreshToken})
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to refresh token", err)
+	}
+
+	return tokenPairToProto(resp), nil
+}
+
+// Logout ends the current session or, when requested, every session of the user.
+func (h *Handler) Logout(ctx context.Context, req *authv1.LogoutRequest) (*authv1.LogoutResponse, error) {
+	refreshToken, err := protoutils.UUIDFromProto(req.GetRefreshToken())
+	if err != nil {
+		return nil, invalidArgument("refresh_token", err)
+	}
+
+	ctx = clientinfo.FromIncomingGRPC(ctx)
+	logoutReq := &models.LogoutRequest{RefreshToken: refreshToken}
+	if req.GetAllSessions() {
+		err = h.logic.LogoutAll(ctx, logoutReq)
+	} else {
+		err = h.logic.Logout(ctx, logoutReq)
+	}
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to logout", err)
+	}
+
+	return &authv1.LogoutResponse{}, nil
+}
+
+// IntrospectToken reports whether an access token is valid and its session is active.
+func (h *Handler) IntrospectToken(
+	ctx context.Context,
+	req *authv1.IntrospectTokenRequest,
+) (*authv1.IntrospectTokenResponse, error) {
+	resp, err := h.logic.IntrospectToken(ctx, &models.IntrospectRequest{AccessToken: req.GetAccessToken()})
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to introspect token", err)
+	}
+
+	return introspectionToProto(resp), nil
+}
+
+// GetSessions lists the sessions of the user the caller's access token
+// belongs to.
+func (h *Handler) GetSessions(ctx context.Context, _ *authv1.GetSessionsRequest) (*authv1.GetSessionsResponse, error) {
+	token := authverify.TokenFromMetadata(ctx)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, authverify.ErrInvalidToken.Error())
+	}
+
+	introspection, err := h.logic.IntrospectToken(ctx, &models.IntrospectRequest{AccessToken: token})
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to introspect token", err)
+	}
+	if !introspection.Active {
+		return nil, status.Error(codes.Unauthenticated, authverify.ErrInvalidToken.Error())
+	}
+
+	sessions, err := h.logic.GetSessions(ctx, introspection.UserID)
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to get sessions", err)
+	}
+
+	resp := &authv1.GetSessionsResponse{
+		Sessions: make([]*authv1.Session, 0, len(sessions)),
+	}
+	for _, s := range sessions {
+		resp.Sessions = append(resp.Sessions, &authv1.Session{
+			SessionToken: protoutils.UUIDToProto(s.SessionToken),
+			ExpiresAt:    proto.Int64(s.ExpiresAtUnix),
+			Platform:     proto.String(s.Platform),
+			AppVersion:   proto.String(s.AppVersion),
+			Ip:           proto.String(s.IP),
+			CreatedAt:    proto.Int64(s.CreatedAtUnix),
+			LastUsedAt:   proto.Int64(s.LastUsedAtUnix),
+			Current:      proto.Bool(s.SessionToken == introspection.SessionToken),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,117 @@
+package grpchand
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/pkg/logging"
+	"go-game-backend/pkg/protoutils"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	authv1 "go-game-backend/gen/auth/v1"
+	"go-game-backend/gen/dto"
+)
+
+type fakeLogic struct {
+	err error
+}
+
+func (f *fakeLogic) Register(context.Context, *models.RegisterRequest) (*models.LoginRespose, error) {
+	return &models.LoginRespose{}, f.err
+}
+
+func (f *fakeLogic) Login(context.Context, *models.LoginRequest) (*models.LoginRespose, error) {
+	return &models.LoginRespose{}, f.err
+}
+
+func (f *fakeLogic) RefreshToken(context.Context, *models.RefreshTokenRequest) (*models.LoginRespose, error) {
+	return &models.LoginRespose{}, f.err
+}
+
+func (f *fakeLogic) VerifyMFA(context.Context, *models.MFAVerifyRequest) (*models.LoginRespose, error) {
+	return &models.LoginRespose{}, f.err
+}
+
+func (f *fakeLogic) Logout(context.Context, *models.LogoutRequest) error {
+	return f.err
+}
+
+func (f *fakeLogic) LogoutAll(context.Context, *models.LogoutRequest) error {
+	return f.err
+}
+
+func (f *fakeLogic) IntrospectToken(context.Context, *models.IntrospectRequest) (*models.IntrospectResponse, error) {
+	return &models.IntrospectResponse{}, f.err
+}
+
+func (f *fakeLogic) GetSessions(context.Context, int64) ([]models.Session, error) {
+	return nil, f.err
+}
+
+func TestHandlerErrorMapping(t *testing.T) {
+	validToken := protoutils.UUIDToProto(uuid.New())
+
+	tests := []struct {
+		name     string
+		token    *dto.UUID
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "success", token: validToken, wantCode: codes.OK},
+		{name: "malformed login token", token: &dto.UUID{Value: []byte{1, 2}}, wantCode: codes.InvalidArgument},
+		{
+			name:     "unknown login token",
+			token:    validToken,
+			err:      fmt.Errorf("find user: %w", services.ErrValidationCredentials),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "duplicate login token",
+			token:    validToken,
+			err:      fmt.Errorf("pg transaction: %w", services.ErrLoginTokenTaken),
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "banned player",
+			token:    validToken,
+			err:      fmt.Errorf("%w permanently", services.ErrPlayerBanned),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "lock contention",
+			token:    validToken,
+			err:      fmt.Errorf("%w: lock not obtained", services.ErrPlayerLocked),
+			wantCode: codes.Aborted,
+		},
+		{
+			name:     "storage outage",
+			token:    validToken,
+			err:      fmt.Errorf("rx transaction: %w", services.ErrStorageUnavailable),
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:     "storage timeout",
+			token:    validToken,
+			err:      fmt.Errorf("pg transaction: %w: %w", services.ErrStorageUnavailable, context.DeadlineExceeded),
+			wantCode: codes.DeadlineExceeded,
+		},
+		{name: "unclassified error", token: validToken, err: errors.New("boom"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(&fakeLogic{err: tt.err}, logging.NewNopLogger())
+
+			_, err := h.Login(context.Background(), &authv1.LoginRequest{LoginToken: tt.token})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %s, want %s (err %v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package grpchand
+
+import (
+	"context"
+	"go-game-backend/services/auth/internal/clientinfo"
+	"go-game-backend/services/auth/pkg/models"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	authv1 "go-game-backend/gen/auth/v1"
+)
+
+// ModerationLogic defines the moderation operations required by the gRPC
+// handler.
+type ModerationLogic interface {
+	BanPlayer(ctx context.Context, req *models.BanPlayerRequest) (*models.BanPlayerResponse, error)
+	UnbanPlayer(ctx context.Context, userID int64) error
+	ListAuditLog(ctx context.Context, req *models.AuditLogRequest) (*models.AuditLogResponse, error)
+}
+
+// maxAuditLogLimit bounds the entries returned by a single ListAuditLog call.
+const maxAuditLogLimit = 1000
+
+// ModerationHandler implements the auth.v1.ModerationService gRPC service.
+type ModerationHandler struct {
+	authv1.UnimplementedModerationServiceServer
+	*Handler
+
+	logic ModerationLogic
+}
+
+// NewModerationHandler creates a ModerationHandler sharing error handling
+// with h.
+func NewModerationHandler(h *Handler, logic ModerationLogic) *ModerationHandler {
+	return &ModerationHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// BanPlayer handles requests to ban a player.
+func (h *ModerationHandler) BanPlayer(
+	ctx context.Context,
+	req *authv1.BanPlayerRequest,
+) (*authv1.BanPlayerResponse, error) {
+	switch {
+	case req.GetUserId() <= 0:
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	case req.GetReason() == "":
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	case req.GetIssuedBy() == "":
+		return nil, status.Error(codes.InvalidArgument, "issued_by is required")
+	case req.GetExpiresAt() < 0:
+		return nil, status.Error(codes.InvalidArgument, "expires_at must not be negative")
+	}
+
+	resp, err := h.logic.BanPlayer(clientinfo.FromIncomingGRPC(ctx), &models.BanPlayerRequest{
+		UserID:        req.GetUserId(),
+		Reason:        req.GetReason(),
+		IssuedBy:      req.GetIssuedBy(),
+		ExpiresAtUnix: req.GetExpiresAt(),
+	})
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to ban player", err)
+	}
+
+	return &authv1.BanPlayerResponse{BanId: proto.Int64(resp.BanID)}, nil
+}
+
+// UnbanPlayer handles requests to lift the bans of a player.
+func (h *ModerationHandler) UnbanPlayer(
+	ctx context.Context,
+	req *authv1.UnbanPlayerRequest,
+) (*authv1.UnbanPlayerResponse, error) {
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := h.logic.UnbanPlayer(clientinfo.FromIncomingGRPC(ctx), req.GetUserId()); err != nil {
+		return nil, h.toStatus(ctx, "failed to unban player", err)
+	}
+
+	return &authv1.UnbanPlayerResponse{}, nil
+}
+
+// ListAuditLog handles requests for the audit log of a player.
+func (h *ModerationHandler) ListAuditLog(
+	ctx context.Context,
+	req *authv1.ListAuditLogRequest,
+) (*authv1.ListAuditLogResponse, error) {
+	switch {
+	case req.GetUserId() <= 0:
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	case req.GetFrom() < 0:
+		return nil, status.Error(codes.InvalidArgument, "from must not be negative")
+	case req.GetTo() != 0 && req.GetTo() <= req.GetFrom():
+		return nil, status.Error(codes.InvalidArgument, "to must be after from")
+	case req.GetLimit() < 0 || req.GetLimit() > maxAuditLogLimit:
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 0 and %d", maxAuditLogLimit)
+	}
+
+	resp, err := h.logic.ListAuditLog(ctx, &models.AuditLogRequest{
+		UserID:   req.GetUserId(),
+		FromUnix: req.GetFrom(),
+		ToUnix:   req.GetTo(),
+		Limit:    int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, h.toStatus(ctx, "failed to list audit log", err)
+	}
+
+	return auditLogToProto(resp), nil
+}
@@ -0,0 +1,62 @@
+package grpchand
+
+import (
+	"fmt"
+	"go-game-backend/pkg/protoutils"
+	"go-game-backend/services/auth/internal/ratelimit"
+
+	redisstore "go-game-backend/pkg/redis"
+
+	authv1 "go-game-backend/gen/auth/v1"
+	"go-game-backend/gen/dto"
+)
+
+// RateLimitRules returns the rate limits of the AuthService methods. They
+// apply the limits of the matching HTTP routes under the same keys, so that
+// the limits cannot be bypassed by calling the gRPC API instead.
+func RateLimitRules(cfg *ratelimit.Config) (map[string][]redisstore.GRPCRateLimitRule, error) {
+	ipKey, err := redisstore.PeerIPKey(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("peer ip key: %w", err)
+	}
+
+	rules := func(route string, key redisstore.GRPCRateLimitKeyFunc) []redisstore.GRPCRateLimitRule {
+		limits := cfg.Routes[route]
+		res := []redisstore.GRPCRateLimitRule{
+			{Scope: route, Limit: limits.PerIP, Key: ipKey},
+		}
+		if key != nil {
+			res = append(res, redisstore.GRPCRateLimitRule{Scope: route, Limit: limits.PerKey, Key: key})
+		}
+		return res
+	}
+
+	return map[string][]redisstore.GRPCRateLimitRule{
+		authv1.AuthService_Register_FullMethodName: rules("register", nil),
+		authv1.AuthService_Login_FullMethodName: rules("login", redisstore.MessageFieldKey(
+			"login_token",
+			func(req *authv1.LoginRequest) string { return protoUUID(req.GetLoginToken()) },
+		)),
+		authv1.AuthService_Refresh_FullMethodName: rules("refresh", redisstore.MessageFieldKey(
+			"refresh_token",
+			func(req *authv1.RefreshRequest) string { return protoUUID(req.GetRefreshToken()) },
+		)),
+		authv1.AuthService_VerifyMFA_FullMethodName: rules("mfa-verify", redisstore.MessageFieldKey(
+			"mfa_token",
+			(*authv1.VerifyMFARequest).GetMfaToken,
+		)),
+	}, nil
+}
+
+// protoUUID returns the UUID in pb in the form ratelimit.UUIDValue
+// canonicalizes it to, or an empty string if it is missing or invalid.
+func protoUUID(pb *dto.UUID) string {
+	if pb == nil {
+		return ""
+	}
+	id, err := protoutils.UUIDFromProto(pb)
+	if err != nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,171 @@
+package grpchand
+
+import (
+	"context"
+	"go-game-backend/pkg/protoutils"
+	"go-game-backend/services/auth/internal/ratelimit"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+
+	redisstore "go-game-backend/pkg/redis"
+
+	authv1 "go-game-backend/gen/auth/v1"
+)
+
+func TestRateLimitRulesShareHTTPKeys(t *testing.T) {
+	loginToken := uuid.New()
+	cfg := &ratelimit.Config{Routes: map[string]ratelimit.RouteLimit{
+		"login":      {PerKey: redisstore.Limit{Requests: 1, Window: 1}},
+		"mfa-verify": {PerKey: redisstore.Limit{Requests: 1, Window: 1}},
+	}}
+	rules, err := RateLimitRules(cfg)
+	if err != nil {
+		t.Fatalf("rate limit rules: %v", err)
+	}
+
+	hexToken := strings.ReplaceAll(loginToken.String(), "-", "")
+	escaped := `\u00` + strconv.FormatInt(int64(loginToken.String()[0]), 16) + loginToken.String()[1:]
+
+	tests := []struct {
+		method    string
+		field     string
+		canonical redisstore.FieldCanonicalizer
+		bodies    []string
+		req       any
+	}{
+		{
+			method:    authv1.AuthService_Login_FullMethodName,
+			field:     "login_token",
+			canonical: ratelimit.UUIDValue,
+			bodies: []string{
+				`{"login_token":"` + loginToken.String() + `"}`,
+				`{"login_token":"` + strings.ToUpper(loginToken.String()) + `"}`,
+				`{"login_token":"{` + loginToken.String() + `}"}`,
+				`{"login_token":"urn:uuid:` + loginToken.String() + `"}`,
+				`{"login_token":"` + hexToken + `"}`,
+				`{"login_token":"` + escaped + `"}`,
+				`{"LOGIN_TOKEN":"` + loginToken.String() + `"} trailing`,
+			},
+			req: &authv1.LoginRequest{LoginToken: protoutils.UUIDToProto(loginToken)},
+		},
+		{
+			method:    authv1.AuthService_VerifyMFA_FullMethodName,
+			field:     "mfa_token",
+			canonical: ratelimit.TokenValue,
+			bodies:    []string{`{"mfa_token":"abc123","code":"000000"}`, `{"mfa_token":"\u0061bc123"}`},
+			req:       &authv1.VerifyMFARequest{MfaToken: proto.String("abc123")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			rs := rules[tt.method]
+			if len(rs) != 2 {
+				t.Fatalf("got %d rules, want 2", len(rs))
+			}
+			want, ok := rs[1].Key(context.Background(), tt.req)
+			if !ok {
+				t.Fatal("no gRPC key")
+			}
+
+			for _, body := range tt.bodies {
+				if got := httpFieldKey(t, tt.field, tt.canonical, body); got != want {
+					t.Fatalf("body %s: got key %q, want %q", body, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONFieldKeyFallbackBucket(t *testing.T) {
+	fallback := httpFieldKey(t, "login_token", ratelimit.UUIDValue, `{"login_token":"not a uuid"}`)
+
+	for _, body := range []string{
+		`{"login_token":42}`,
+		`{"login_token":"` + uuid.NewString() + `","Login_Token":"` + uuid.NewString() + `"}`,
+		`not json`,
+	} {
+		if got := httpFieldKey(t, "login_token", ratelimit.UUIDValue, body); got != fallback {
+			t.Fatalf("body %s: got key %q, want the fallback %q", body, got, fallback)
+		}
+	}
+
+	emailKey := httpFieldKey(t, "email", ratelimit.EmailValue, `{"email":"player@example.com"}`)
+	if got := httpFieldKey(t, "email", ratelimit.EmailValue, `{"email":" Player@Example.COM "}`); got != emailKey {
+		t.Fatalf("got email key %q, want %q", got, emailKey)
+	}
+}
+
+// httpFieldKey returns the key a request with body is counted under by
+// redisstore.JSONFieldKey.
+func httpFieldKey(t *testing.T, field string, canonical redisstore.FieldCanonicalizer, body string) string {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	key, ok := redisstore.JSONFieldKey(field, canonical)(c)
+	if !ok {
+		t.Fatalf("body %s: no HTTP key", body)
+	}
+	return key
+}
+
+func TestRateLimitRulesShareHTTPClientIP(t *testing.T) {
+	trusted := []string{"10.0.0.0/8", "192.168.1.1"}
+	rules, err := RateLimitRules(&ratelimit.Config{TrustedProxies: trusted})
+	if err != nil {
+		t.Fatalf("rate limit rules: %v", err)
+	}
+	ipKey := rules[authv1.AuthService_Register_FullMethodName][0].Key
+
+	router := gin.New()
+	if err := router.SetTrustedProxies(trusted); err != nil {
+		t.Fatalf("set trusted proxies: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded string
+		want      string
+	}{
+		{name: "direct client", remote: "203.0.113.7", want: "203.0.113.7"},
+		{name: "untrusted peer", remote: "203.0.113.7", forwarded: "198.51.100.1", want: "203.0.113.7"},
+		{name: "trusted proxy", remote: "10.1.2.3", forwarded: "198.51.100.1", want: "198.51.100.1"},
+		{name: "proxy chain", remote: "10.1.2.3", forwarded: "198.51.100.1, 203.0.113.9, 192.168.1.1", want: "203.0.113.9"},
+		{name: "all hops trusted", remote: "10.1.2.3", forwarded: "10.0.0.1, 10.0.0.2", want: "10.0.0.1"},
+		{name: "malformed chain", remote: "10.1.2.3", forwarded: "198.51.100.1, bogus", want: "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gin.CreateTestContextOnly(httptest.NewRecorder(), router)
+			c.Request = httptest.NewRequest("POST", "/", nil)
+			c.Request.RemoteAddr = tt.remote + ":4242"
+			md := metadata.MD{}
+			if tt.forwarded != "" {
+				c.Request.Header.Set("X-Forwarded-For", tt.forwarded)
+				md.Set("x-forwarded-for", tt.forwarded)
+			}
+			want, _ := redisstore.ClientIPKey(c)
+			if want != "ip:"+tt.want {
+				t.Fatalf("gin key = %q, want %q", want, "ip:"+tt.want)
+			}
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				Addr: &net.TCPAddr{IP: net.ParseIP(tt.remote), Port: 4242},
+			})
+			ctx = metadata.NewIncomingContext(ctx, md)
+			got, ok := ipKey(ctx, &authv1.RegisterRequest{})
+			if !ok || got != want {
+				t.Fatalf("got key %q, %v, want %q", got, ok, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package httphand
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountLogic defines the account data operations required by the HTTP
+// handler.
+type AccountLogic interface {
+	RequestDeletion(ctx context.Context, userID int64) (*models.AccountDeletionResponse, error)
+	ExportAccount(ctx context.Context, userID int64) (*models.AccountExport, error)
+}
+
+// AccountHandler provides HTTP endpoints for deleting and exporting the
+// account of the current user.
+type AccountHandler struct {
+	*Handler
+
+	logic AccountLogic
+}
+
+// NewAccountHandler creates an AccountHandler sharing error handling with h.
+func NewAccountHandler(h *Handler, logic AccountLogic) *AccountHandler {
+	return &AccountHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// Delete schedules the account of the authenticated user for deletion. It
+// must be routed behind authverify.Verifier.GinMiddleware.
+func (h *AccountHandler) Delete(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.logic.RequestDeletion(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "failed to request account deletion", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// Export returns everything held about the authenticated user as a JSON
+// file. It must be routed behind authverify.Verifier.GinMiddleware.
+func (h *AccountHandler) Export(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.logic.ExportAccount(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "failed to export account", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%d.json"`, userID))
+	c.IndentedJSON(http.StatusOK, resp)
+}
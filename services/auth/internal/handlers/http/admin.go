@@ -0,0 +1,248 @@
+package httphand
+
+import (
+	"context"
+	"go-game-backend/services/auth/internal/clientinfo"
+	"go-game-backend/services/auth/pkg/authverify"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminLogic defines the support operations required by the admin HTTP
+// handler.
+type AdminLogic interface {
+	GetPlayerDetails(ctx context.Context, userID int64) (*models.AdminPlayer, error)
+	FindPlayerByLoginToken(ctx context.Context, loginToken uuid.UUID) (*models.AdminPlayer, error)
+	ListAuditLog(ctx context.Context, req *models.AuditLogRequest) (*models.AuditLogResponse, error)
+	RevokeSession(ctx context.Context, userID int64, req *models.RevokeSessionRequest) error
+	RevokePlayerSessions(ctx context.Context, userID int64) error
+	BanPlayer(ctx context.Context, req *models.BanPlayerRequest) (*models.BanPlayerResponse, error)
+	UnbanPlayer(ctx context.Context, userID int64) error
+	ReissueLoginToken(ctx context.Context, userID int64) (*models.LoginTokenResponse, error)
+}
+
+// AdminHandler provides the HTTP endpoints of support tooling. Every route
+// must be served behind authverify.Verifier.ServiceGinMiddleware and
+// AdminActor, so that the actions are attributed in the audit log.
+type AdminHandler struct {
+	*Handler
+
+	logic AdminLogic
+}
+
+// NewAdminHandler creates an AdminHandler sharing error handling with h.
+func NewAdminHandler(h *Handler, logic AdminLogic) *AdminHandler {
+	return &AdminHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// AdminActor records the service client making the request, and the
+// operator it reports acting for, as the actor of the request. It must be
+// routed behind authverify.Verifier.ServiceGinMiddleware.
+func AdminActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := authverify.ServiceClaimsFromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		c.Request = c.Request.WithContext(clientinfo.WithActor(c, claims.ClientID))
+		c.Next()
+	}
+}
+
+// auditLogQuery holds the query parameters of the audit log endpoint.
+type auditLogQuery struct {
+	FromUnix int64 `form:"from"  binding:"omitempty,gte=0"`
+	ToUnix   int64 `form:"to"    binding:"omitempty,gtfield=FromUnix"`
+	Limit    int   `form:"limit" binding:"omitempty,gt=0,lte=1000"`
+}
+
+// GetPlayer returns the player with the ID from the path.
+func (h *AdminHandler) GetPlayer(c *gin.Context) {
+	userID, ok := h.playerIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.logic.GetPlayerDetails(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "failed to get player", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// FindPlayer returns the player owning the guest login token from the
+// login_token query parameter.
+func (h *AdminHandler) FindPlayer(c *gin.Context) {
+	loginToken, err := uuid.Parse(c.Query("login_token"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.ErrorCodeInvalidRequest,
+			Message: "login_token must be a UUID",
+		})
+		return
+	}
+
+	resp, err := h.logic.FindPlayerByLoginToken(c.Request.Context(), loginToken)
+	if err != nil {
+		h.writeError(c, "failed to find player", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AuditLog returns the audit log of the player, newest first, within the
+// time range of the from and to query parameters.
+func (h *AdminHandler) AuditLog(c *gin.Context) {
+	userID, ok := h.playerIDParam(c)
+	if !ok {
+		return
+	}
+	var query auditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.ErrorCodeInvalidRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.logic.ListAuditLog(c.Request.Context(), &models.AuditLogRequest{
+		UserID:   userID,
+		FromUnix: query.FromUnix,
+		ToUnix:   query.ToUnix,
+		Limit:    query.Limit,
+	})
+	if err != nil {
+		h.writeError(c, "failed to list audit log", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeSession ends a session of the player.
+func (h *AdminHandler) RevokeSession(c *gin.Context) {
+	userID, ok := h.playerIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.RevokeSessionRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.RevokeSession(c.Request.Context(), userID, &req); err != nil {
+		h.writeError(c, "failed to revoke session", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeAllSessions ends every session of the player.
+func (h *AdminHandler) RevokeAllSessions(c *gin.Context) {
+	userID, ok := h.playerIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.logic.RevokePlayerSessions(c.Request.Context(), userID); err != nil {
+		h.writeError(c, "failed to revoke sessions", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Ban blocks the player from signing in.
+func (h *AdminHandler) Ban(c *gin.Context) {
+	userID, ok := h.playerIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.AdminBanRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	issuedBy := req.IssuedBy
+	if issuedBy == "" {
+		info := clientinfo.FromContext(c.Request.Context())
+		issuedBy = info.Operator
+		if issuedBy == "" {
+			issuedBy = info.Actor
+		}
+	}
+
+	resp, err := h.logic.BanPlayer(c.Request.Context(), &models.BanPlayerRequest{
+		UserID:        userID,
+		Reason:        req.Reason,
+		IssuedBy:      issuedBy,
+		ExpiresAtUnix: req.ExpiresAtUnix,
+	})
+	if err != nil {
+		h.writeError(c, "failed to ban player", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Unban lifts every ban of the player that is in effect.
+func (h *AdminHandler) Unban(c *gin.Context) {
+	userID, ok := h.playerIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.logic.UnbanPlayer(c.Request.Context(), userID); err != nil {
+		h.writeError(c, "failed to unban player", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReissueLoginToken replaces the guest login tokens of the player with a new
+// one and returns it.
+func (h *AdminHandler) ReissueLoginToken(c *gin.Context) {
+	userID, ok := h.playerIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.logic.ReissueLoginToken(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "failed to reissue login token", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// playerIDParam parses the player ID from the path and responds with 400
+// when it is malformed.
+func (h *AdminHandler) playerIDParam(c *gin.Context) (int64, bool) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.ErrorCodeInvalidRequest,
+			Message: "player id must be a positive integer",
+		})
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,134 @@
+package httphand
+
+import (
+	"context"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailLogic defines the email credential operations required by the HTTP
+// handler.
+type EmailLogic interface {
+	RegisterEmail(ctx context.Context, req *models.EmailCredentialsRequest) (*models.LoginRespose, error)
+	LoginEmail(ctx context.Context, req *models.EmailCredentialsRequest) (*models.LoginRespose, error)
+	LinkEmail(ctx context.Context, userID int64, req *models.EmailCredentialsRequest) error
+	VerifyEmail(ctx context.Context, req *models.VerifyEmailRequest) error
+	RequestPasswordReset(ctx context.Context, req *models.PasswordResetRequest) error
+	ResetPassword(ctx context.Context, req *models.PasswordResetConfirmRequest) error
+}
+
+// EmailHandler provides HTTP endpoints for email and password credentials.
+type EmailHandler struct {
+	*Handler
+
+	logic EmailLogic
+}
+
+// NewEmailHandler creates an EmailHandler sharing error handling with h.
+func NewEmailHandler(h *Handler, logic EmailLogic) *EmailHandler {
+	return &EmailHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// Register handles registration with an email and password.
+func (h *EmailHandler) Register(c *gin.Context) {
+	var req models.EmailCredentialsRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.logic.RegisterEmail(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "failed to register with email", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Login handles login with an email and password.
+func (h *EmailHandler) Login(c *gin.Context) {
+	var req models.EmailCredentialsRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.logic.LoginEmail(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "failed to login with email", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Link adds an email and password to the authenticated user. It must be
+// routed behind authverify.Verifier.GinMiddleware.
+func (h *EmailHandler) Link(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.EmailCredentialsRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.LinkEmail(c.Request.Context(), userID, &req); err != nil {
+		h.writeError(c, "failed to link email", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Verify confirms an email with the token sent to it.
+func (h *EmailHandler) Verify(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.VerifyEmail(c.Request.Context(), &req); err != nil {
+		h.writeError(c, "failed to verify email", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestPasswordReset sends a password reset token to the email. It responds
+// with 202 whether or not the email is registered.
+func (h *EmailHandler) RequestPasswordReset(c *gin.Context) {
+	var req models.PasswordResetRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.RequestPasswordReset(c.Request.Context(), &req); err != nil {
+		h.writeError(c, "failed to request password reset", err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword sets a new password using a password reset token.
+func (h *EmailHandler) ResetPassword(c *gin.Context) {
+	var req models.PasswordResetConfirmRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.ResetPassword(c.Request.Context(), &req); err != nil {
+		h.writeError(c, "failed to reset password", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,93 @@
+package httphand
+
+import (
+	"context"
+	"errors"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go.uber.org/zap"
+)
+
+// errorMapping binds a service error to the HTTP status and error code
+// reported to the client.
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+var errorMappings = []errorMapping{
+	{services.ErrValidationCredentials, http.StatusUnauthorized, models.ErrorCodeInvalidCredentials},
+	{services.ErrLoginTokenTaken, http.StatusConflict, models.ErrorCodeLoginTokenTaken},
+	{services.ErrEmailTaken, http.StatusConflict, models.ErrorCodeEmailTaken},
+	{services.ErrEmailAlreadyLinked, http.StatusConflict, models.ErrorCodeEmailAlreadyLinked},
+	{services.ErrEmailNotVerified, http.StatusForbidden, models.ErrorCodeEmailNotVerified},
+	{services.ErrInvalidEmailToken, http.StatusBadRequest, models.ErrorCodeInvalidEmailToken},
+	{services.ErrUnknownProvider, http.StatusBadRequest, models.ErrorCodeUnknownProvider},
+	{services.ErrIdentityTaken, http.StatusConflict, models.ErrorCodeIdentityTaken},
+	{services.ErrIdentityNotFound, http.StatusNotFound, models.ErrorCodeIdentityNotFound},
+	{services.ErrLastIdentity, http.StatusConflict, models.ErrorCodeLastIdentity},
+	{services.ErrMergeNotAllowed, http.StatusConflict, models.ErrorCodeMergeNotAllowed},
+	{services.ErrMFAAlreadyEnabled, http.StatusConflict, models.ErrorCodeMFAAlreadyEnabled},
+	{services.ErrMFANotEnrolled, http.StatusConflict, models.ErrorCodeMFANotEnrolled},
+	{services.ErrInvalidMFACode, http.StatusUnauthorized, models.ErrorCodeInvalidMFACode},
+	{services.ErrInvalidMFAToken, http.StatusUnauthorized, models.ErrorCodeInvalidMFAToken},
+	{services.ErrInvalidTransferCode, http.StatusUnauthorized, models.ErrorCodeInvalidTransferCode},
+	{services.ErrSessionNotFound, http.StatusNotFound, models.ErrorCodeSessionNotFound},
+	{services.ErrInvalidRefreshToken, http.StatusUnauthorized, models.ErrorCodeInvalidRefreshToken},
+	{services.ErrRefreshTokenReused, http.StatusUnauthorized, models.ErrorCodeRefreshTokenReused},
+	{services.ErrPlayerBanned, http.StatusForbidden, models.ErrorCodePlayerBanned},
+	{services.ErrPlayerNotFound, http.StatusNotFound, models.ErrorCodePlayerNotFound},
+	{services.ErrPlayerNotBanned, http.StatusConflict, models.ErrorCodePlayerNotBanned},
+	{services.ErrBanExpired, http.StatusBadRequest, models.ErrorCodeBanExpired},
+	{services.ErrPlayerLocked, http.StatusLocked, models.ErrorCodePlayerLocked},
+	{services.ErrInvalidClient, http.StatusUnauthorized, models.ErrorCodeInvalidClient},
+	{services.ErrInvalidScope, http.StatusBadRequest, models.ErrorCodeInvalidScope},
+	{services.ErrUnsupportedGrant, http.StatusBadRequest, models.ErrorCodeUnsupportedGrant},
+	// checked before the storage errors, which may wrap it
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, models.ErrorCodeTimeout},
+	{services.ErrStorageUnavailable, http.StatusServiceUnavailable, models.ErrorCodeServiceUnavailable},
+	{services.ErrProviderUnavailable, http.StatusServiceUnavailable, models.ErrorCodeServiceUnavailable},
+}
+
+// writeError logs err and responds with the status and error body matching it.
+// Errors without a mapping are reported as internal errors without exposing details.
+func (h *Handler) writeError(c *gin.Context, msg string, err error) {
+	ctx := c.Request.Context()
+
+	for _, m := range errorMappings {
+		if !errors.Is(err, m.err) {
+			continue
+		}
+		if m.status >= http.StatusInternalServerError {
+			h.logger.ErrorCtx(ctx, msg, zap.Error(err))
+		} else {
+			h.logger.InfoCtx(ctx, msg, zap.Error(err))
+		}
+		c.JSON(m.status, models.ErrorResponse{Code: m.code, Message: m.err.Error()})
+		return
+	}
+
+	h.logger.ErrorCtx(ctx, msg, zap.Error(err))
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Code:    models.ErrorCodeInternal,
+		Message: "internal error",
+	})
+}
+
+// bindJSON decodes the request body into req and responds with 400 when the
+// body is malformed.
+func (h *Handler) bindJSON(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.ErrorCodeInvalidRequest,
+			Message: err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -8,8 +8,6 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-
-	"go.uber.org/zap"
 )
 
 // Logic defines the business logic required by the HTTP handler.
@@ -17,6 +15,9 @@ type Logic interface {
 	Register(ctx context.Context, req *models.RegisterRequest) (resp *models.LoginRespose, err error)
 	Login(ctx context.Context, req *models.LoginRequest) (resp *models.LoginRespose, err error)
 	RefreshToken(ctx context.Context, req *models.RefreshTokenRequest) (resp *models.LoginRespose, err error)
+	Logout(ctx context.Context, req *models.LogoutRequest) error
+	LogoutAll(ctx context.Context, req *models.LogoutRequest) error
+	IntrospectToken(ctx context.Context, req *models.IntrospectRequest) (*models.IntrospectResponse, error)
 }
 
 // Handler provides HTTP endpoints for authentication operations.
@@ -36,14 +37,13 @@ func New(logic Logic, logger *logging.ZapLogger) *Handler {
 // Register handles user registration requests.
 func (h *Handler) Register(c *gin.Context) {
 	var req models.RegisterRequest
-	if err := c.BindJSON(&req); err != nil {
+	if !h.bindJSON(c, &req) {
 		return
 	}
 
 	resp, err := h.logic.Register(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.ErrorCtx(c.Request.Context(), "failed to register", zap.Error(err))
-		c.Status(http.StatusInternalServerError)
+		h.writeError(c, "failed to register", err)
 		return
 	}
 
@@ -53,14 +53,13 @@ func (h *Handler) Register(c *gin.Context) {
 // Login processes user login requests.
 func (h *Handler) Login(c *gin.Context) {
 	var req models.LoginRequest
-	if err := c.BindJSON(&req); err != nil {
+	if !h.bindJSON(c, &req) {
 		return
 	}
 
 	resp, err := h.logic.Login(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.ErrorCtx(c.Request.Context(), "failed to login", zap.Error(err))
-		c.Status(http.StatusInternalServerError)
+		h.writeError(c, "failed to login", err)
 		return
 	}
 
@@ -70,14 +69,60 @@ func (h *Handler) Login(c *gin.Context) {
 // RefreshToken handles token refresh requests.
 func (h *Handler) RefreshToken(c *gin.Context) {
 	var req models.RefreshTokenRequest
-	if err := c.BindJSON(&req); err != nil {
+	if !h.bindJSON(c, &req) {
 		return
 	}
 
 	resp, err := h.logic.RefreshToken(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.ErrorCtx(c.Request.Context(), "failed to refresh token", zap.Error(err))
-		c.Status(http.StatusInternalServerError)
+		h.writeError(c, "failed to refresh token", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Logout handles requests to end the current session.
+func (h *Handler) Logout(c *gin.Context) {
+	var req models.LogoutRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.Logout(c.Request.Context(), &req); err != nil {
+		h.writeError(c, "failed to logout", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll handles requests to end every session of the user.
+func (h *Handler) LogoutAll(c *gin.Context) {
+	var req models.LogoutRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.LogoutAll(c.Request.Context(), &req); err != nil {
+		h.writeError(c, "failed to logout everywhere", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Introspect reports whether an access token is valid and its session is
+// still the active one.
+func (h *Handler) Introspect(c *gin.Context) {
+	var req models.IntrospectRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.logic.IntrospectToken(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "failed to introspect token", err)
 		return
 	}
 
@@ -0,0 +1,154 @@
+package httphand
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-game-backend/pkg/logging"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeLogic struct {
+	err error
+}
+
+func (f *fakeLogic) Register(context.Context, *models.RegisterRequest) (*models.LoginRespose, error) {
+	return &models.LoginRespose{}, f.err
+}
+
+func (f *fakeLogic) Login(context.Context, *models.LoginRequest) (*models.LoginRespose, error) {
+	return &models.LoginRespose{}, f.err
+}
+
+func (f *fakeLogic) RefreshToken(context.Context, *models.RefreshTokenRequest) (*models.LoginRespose, error) {
+	return &models.LoginRespose{}, f.err
+}
+
+func (f *fakeLogic) Logout(context.Context, *models.LogoutRequest) error {
+	return f.err
+}
+
+func (f *fakeLogic) LogoutAll(context.Context, *models.LogoutRequest) error {
+	return f.err
+}
+
+func (f *fakeLogic) IntrospectToken(context.Context, *models.IntrospectRequest) (*models.IntrospectResponse, error) {
+	return &models.IntrospectResponse{}, f.err
+}
+
+func TestHandlerErrorMapping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type tc struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}
+
+	tests := []tc{
+		{
+			name:       "success",
+			body:       `{"login_token":"6f1c2b1e-8a53-4d3c-9d0a-3b7c1a4f2e10"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "malformed body",
+			body:       `{"login_token":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   models.ErrorCodeInvalidRequest,
+		},
+		{
+			name:       "unknown login token",
+			body:       `{}`,
+			err:        fmt.Errorf("find user: %w", services.ErrValidationCredentials),
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   models.ErrorCodeInvalidCredentials,
+		},
+		{
+			name:       "duplicate login token",
+			body:       `{}`,
+			err:        fmt.Errorf("pg transaction: %w", services.ErrLoginTokenTaken),
+			wantStatus: http.StatusConflict,
+			wantCode:   models.ErrorCodeLoginTokenTaken,
+		},
+		{
+			name:       "unverified email",
+			body:       `{}`,
+			err:        fmt.Errorf("check email credential: %w", services.ErrEmailNotVerified),
+			wantStatus: http.StatusForbidden,
+			wantCode:   models.ErrorCodeEmailNotVerified,
+		},
+		{
+			name:       "unknown refresh token",
+			body:       `{}`,
+			err:        fmt.Errorf("get session info: %w", services.ErrInvalidRefreshToken),
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   models.ErrorCodeInvalidRefreshToken,
+		},
+		{
+			name:       "lock contention",
+			body:       `{}`,
+			err:        fmt.Errorf("%w: lock not obtained", services.ErrPlayerLocked),
+			wantStatus: http.StatusLocked,
+			wantCode:   models.ErrorCodePlayerLocked,
+		},
+		{
+			name:       "storage outage",
+			body:       `{}`,
+			err:        fmt.Errorf("rx transaction: %w", services.ErrStorageUnavailable),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   models.ErrorCodeServiceUnavailable,
+		},
+		{
+			name:       "storage timeout",
+			body:       `{}`,
+			err:        fmt.Errorf("pg transaction: %w: %w", services.ErrStorageUnavailable, context.DeadlineExceeded),
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   models.ErrorCodeTimeout,
+		},
+		{
+			name:       "unclassified error",
+			body:       `{}`,
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   models.ErrorCodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(&fakeLogic{err: tt.err}, logging.NewNopLogger())
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.Login(c)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var body models.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode error body: %v", err)
+			}
+			if body.Code != tt.wantCode {
+				t.Fatalf("code = %q, want %q", body.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package httphand
+
+import (
+	"context"
+	"go-game-backend/services/auth/pkg/authverify"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdentityLogic defines the account linking operations required by the HTTP
+// handler.
+type IdentityLogic interface {
+	ListIdentities(ctx context.Context, userID int64) (*models.IdentitiesResponse, error)
+	LinkGuest(ctx context.Context, userID int64, req *models.LinkGuestRequest) error
+	UnlinkIdentity(ctx context.Context, userID int64, req *models.UnlinkIdentityRequest) error
+	MergeGuest(ctx context.Context, userID int64, req *models.LinkGuestRequest) error
+}
+
+// IdentityHandler provides HTTP endpoints for managing the credentials of the
+// authenticated user. Its routes must be behind authverify.Verifier.GinMiddleware.
+type IdentityHandler struct {
+	*Handler
+
+	logic IdentityLogic
+}
+
+// NewIdentityHandler creates an IdentityHandler sharing error handling with h.
+func NewIdentityHandler(h *Handler, logic IdentityLogic) *IdentityHandler {
+	return &IdentityHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// List responds with the identities of the user.
+func (h *IdentityHandler) List(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.logic.ListIdentities(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "failed to list identities", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// LinkGuest binds a guest login token to the user.
+func (h *IdentityHandler) LinkGuest(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.LinkGuestRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.LinkGuest(c.Request.Context(), userID, &req); err != nil {
+		h.writeError(c, "failed to link guest", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Unlink removes an identity from the user.
+func (h *IdentityHandler) Unlink(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.UnlinkIdentityRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.UnlinkIdentity(c.Request.Context(), userID, &req); err != nil {
+		h.writeError(c, "failed to unlink identity", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MergeGuest merges the guest account owning the login token into the user.
+func (h *IdentityHandler) MergeGuest(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.LinkGuestRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.MergeGuest(c.Request.Context(), userID, &req); err != nil {
+		h.writeError(c, "failed to merge guest", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// userIDFromRequest returns the authenticated user ID and aborts with 401 when
+// the request was not authenticated.
+func userIDFromRequest(c *gin.Context) (int64, bool) {
+	userID, ok := authverify.UserIDFromContext(c.Request.Context())
+	if !ok {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+	return userID, ok
+}
@@ -0,0 +1,48 @@
+package httphand
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/pkg/logging"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+
+	"go.uber.org/zap"
+)
+
+// jwksMaxAge is how long verifiers may cache the published key set.
+const jwksMaxAge = 300
+
+// PublicKeySource provides the public keys published by the JWKS endpoint.
+type PublicKeySource interface {
+	PublicKeySet(ctx context.Context) (jwk.Set, error)
+}
+
+// JWKSHandler serves the public keys used to verify access tokens.
+type JWKSHandler struct {
+	keys   PublicKeySource
+	logger *logging.ZapLogger
+}
+
+// NewJWKSHandler creates a JWKSHandler publishing keys from the given source.
+func NewJWKSHandler(keys PublicKeySource, logger *logging.ZapLogger) *JWKSHandler {
+	return &JWKSHandler{
+		keys:   keys,
+		logger: logger,
+	}
+}
+
+// JWKS responds with the JSON Web Key Set document.
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	set, err := h.keys.PublicKeySet(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorCtx(c.Request.Context(), "failed to build key set", zap.Error(err))
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", jwksMaxAge))
+	c.JSON(http.StatusOK, set)
+}
@@ -0,0 +1,97 @@
+package httphand
+
+import (
+	"context"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginTokenLogic defines the guest login token operations required by the
+// HTTP handler.
+type LoginTokenLogic interface {
+	RotateLoginToken(
+		ctx context.Context,
+		userID int64,
+		req *models.RotateLoginTokenRequest,
+	) (*models.LoginTokenResponse, error)
+	CreateTransferCode(ctx context.Context, userID int64) (*models.TransferCodeResponse, error)
+	RedeemTransferCode(ctx context.Context, req *models.RedeemTransferCodeRequest) (*models.TransferResponse, error)
+}
+
+// LoginTokenHandler provides HTTP endpoints for rotating guest login tokens
+// and moving guest accounts between devices.
+type LoginTokenHandler struct {
+	*Handler
+
+	logic LoginTokenLogic
+}
+
+// NewLoginTokenHandler creates a LoginTokenHandler sharing error handling
+// with h.
+func NewLoginTokenHandler(h *Handler, logic LoginTokenLogic) *LoginTokenHandler {
+	return &LoginTokenHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// Rotate replaces the guest login token from the request with a new one. It
+// must be routed behind authverify.Verifier.GinMiddleware.
+func (h *LoginTokenHandler) Rotate(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+	var req models.RotateLoginTokenRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.logic.RotateLoginToken(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.writeError(c, "failed to rotate login token", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateTransferCode issues a code for moving the account of the
+// authenticated user to another device. It must be routed behind
+// authverify.Verifier.GinMiddleware.
+func (h *LoginTokenHandler) CreateTransferCode(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.logic.CreateTransferCode(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "failed to create transfer code", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// RedeemTransferCode moves the account a transfer code was issued for to the
+// device making the request and signs it in.
+func (h *LoginTokenHandler) RedeemTransferCode(c *gin.Context) {
+	var req models.RedeemTransferCodeRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.logic.RedeemTransferCode(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "failed to redeem transfer code", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
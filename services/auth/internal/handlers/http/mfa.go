@@ -0,0 +1,109 @@
+package httphand
+
+import (
+	"context"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFALogic defines the two-factor authentication operations required by the
+// HTTP handler.
+type MFALogic interface {
+	EnrollTOTP(ctx context.Context, userID int64) (*models.TOTPEnrollResponse, error)
+	ConfirmTOTP(ctx context.Context, userID int64, req *models.TOTPConfirmRequest) (*models.RecoveryCodesResponse, error)
+	DisableTOTP(ctx context.Context, userID int64, req *models.MFACodeRequest) error
+	VerifyMFA(ctx context.Context, req *models.MFAVerifyRequest) (*models.LoginRespose, error)
+}
+
+// MFAHandler provides HTTP endpoints for two-factor authentication.
+type MFAHandler struct {
+	*Handler
+
+	logic MFALogic
+}
+
+// NewMFAHandler creates an MFAHandler sharing error handling with h.
+func NewMFAHandler(h *Handler, logic MFALogic) *MFAHandler {
+	return &MFAHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// EnrollTOTP generates a TOTP secret for the authenticated user. It must be
+// routed behind authverify.Verifier.GinMiddleware.
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.logic.EnrollTOTP(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "failed to enroll totp", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ConfirmTOTP activates the enrolled TOTP secret and returns recovery codes.
+// It must be routed behind authverify.Verifier.GinMiddleware.
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.TOTPConfirmRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.logic.ConfirmTOTP(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.writeError(c, "failed to confirm totp", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DisableTOTP turns two-factor authentication off. It must be routed behind
+// authverify.Verifier.GinMiddleware.
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.MFACodeRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.DisableTOTP(c.Request.Context(), userID, &req); err != nil {
+		h.writeError(c, "failed to disable totp", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Verify completes a login that returned an MFA token.
+func (h *MFAHandler) Verify(c *gin.Context) {
+	var req models.MFAVerifyRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.logic.VerifyMFA(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "failed to verify mfa", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
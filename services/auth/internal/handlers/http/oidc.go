@@ -0,0 +1,69 @@
+package httphand
+
+import (
+	"context"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCLogic defines the external identity provider operations required by
+// the HTTP handler.
+type OIDCLogic interface {
+	LoginOIDC(ctx context.Context, req *models.OIDCLoginRequest) (*models.LoginRespose, error)
+	LinkOIDC(ctx context.Context, userID int64, req *models.OIDCLoginRequest) error
+}
+
+// OIDCHandler provides HTTP endpoints for signing in with external OpenID
+// Connect providers.
+type OIDCHandler struct {
+	*Handler
+
+	logic OIDCLogic
+}
+
+// NewOIDCHandler creates an OIDCHandler sharing error handling with h.
+func NewOIDCHandler(h *Handler, logic OIDCLogic) *OIDCHandler {
+	return &OIDCHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// Login signs in with a provider ID token.
+func (h *OIDCHandler) Login(c *gin.Context) {
+	var req models.OIDCLoginRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.logic.LoginOIDC(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "failed to login with identity provider", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Link binds a provider account to the authenticated user. It must be routed
+// behind authverify.Verifier.GinMiddleware.
+func (h *OIDCHandler) Link(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.OIDCLoginRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.LinkOIDC(c.Request.Context(), userID, &req); err != nil {
+		h.writeError(c, "failed to link identity provider", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
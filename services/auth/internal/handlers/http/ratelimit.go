@@ -0,0 +1,63 @@
+package httphand
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	redisstore "go-game-backend/pkg/redis"
+	"go-game-backend/services/auth/internal/ratelimit"
+	"go-game-backend/services/auth/pkg/authverify"
+)
+
+// RateLimiter builds rate limit middleware for the HTTP routes.
+type RateLimiter struct {
+	limiter *redisstore.RateLimiter
+	cfg     *ratelimit.Config
+}
+
+// NewRateLimiter creates a RateLimiter applying cfg.
+func NewRateLimiter(limiter *redisstore.RateLimiter, cfg *ratelimit.Config) *RateLimiter {
+	return &RateLimiter{
+		limiter: limiter,
+		cfg:     cfg,
+	}
+}
+
+// ByIP limits the route per client IP.
+func (r *RateLimiter) ByIP(route string) gin.HandlerFunc {
+	return r.middleware(route, nil)
+}
+
+// ByField limits the route per client IP and per value of the field of the
+// JSON request body, canonicalized with canonical.
+func (r *RateLimiter) ByField(route, field string, canonical redisstore.FieldCanonicalizer) gin.HandlerFunc {
+	return r.middleware(route, redisstore.JSONFieldKey(field, canonical))
+}
+
+// ByUser limits the route per client IP and per authenticated user. It must
+// be routed behind authverify.Verifier.GinMiddleware.
+func (r *RateLimiter) ByUser(route string) gin.HandlerFunc {
+	return r.middleware(route, userIDKey)
+}
+
+func (r *RateLimiter) middleware(route string, key redisstore.RateLimitKeyFunc) gin.HandlerFunc {
+	limits := r.cfg.Routes[route]
+
+	rules := []redisstore.RateLimitRule{
+		{Scope: route, Limit: limits.PerIP, Key: redisstore.ClientIPKey},
+	}
+	if key != nil {
+		rules = append(rules, redisstore.RateLimitRule{Scope: route, Limit: limits.PerKey, Key: key})
+	}
+
+	return r.limiter.GinMiddleware(rules...)
+}
+
+func userIDKey(c *gin.Context) (string, bool) {
+	userID, ok := authverify.UserIDFromContext(c.Request.Context())
+	if !ok {
+		return "", false
+	}
+	return "user:" + strconv.FormatInt(userID, 10), true
+}
@@ -0,0 +1,59 @@
+package httphand
+
+import (
+	"context"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceClientLogic defines the service token operations required by the
+// HTTP handler.
+type ServiceClientLogic interface {
+	IssueServiceToken(ctx context.Context, req *models.ClientCredentialsRequest) (*models.ServiceTokenResponse, error)
+}
+
+// ServiceClientHandler provides the HTTP token endpoint of internal callers.
+type ServiceClientHandler struct {
+	*Handler
+
+	logic ServiceClientLogic
+}
+
+// NewServiceClientHandler creates a ServiceClientHandler sharing error
+// handling with h.
+func NewServiceClientHandler(h *Handler, logic ServiceClientLogic) *ServiceClientHandler {
+	return &ServiceClientHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// Token issues a service token with the client credentials grant. As in
+// OAuth 2.0 the request may be form encoded or JSON, and the client may
+// authenticate with HTTP Basic instead of the client_id and client_secret
+// fields.
+func (h *ServiceClientHandler) Token(c *gin.Context) {
+	var req models.ClientCredentialsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.ErrorCodeInvalidRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+	if clientID, secret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID = clientID
+		req.ClientSecret = secret
+	}
+
+	resp, err := h.logic.IssueServiceToken(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "failed to issue service token", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
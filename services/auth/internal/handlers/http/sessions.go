@@ -0,0 +1,76 @@
+package httphand
+
+import (
+	"context"
+	"go-game-backend/services/auth/pkg/authverify"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionLogic defines the session management operations required by the
+// HTTP handler.
+type SessionLogic interface {
+	GetSessions(ctx context.Context, userID int64) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID int64, req *models.RevokeSessionRequest) error
+}
+
+// SessionHandler provides HTTP endpoints for managing the sessions of the
+// current user.
+type SessionHandler struct {
+	*Handler
+
+	logic SessionLogic
+}
+
+// NewSessionHandler creates a SessionHandler sharing error handling with h.
+func NewSessionHandler(h *Handler, logic SessionLogic) *SessionHandler {
+	return &SessionHandler{
+		Handler: h,
+		logic:   logic,
+	}
+}
+
+// List returns the sessions of the authenticated user. It must be routed
+// behind authverify.Verifier.GinMiddleware.
+func (h *SessionHandler) List(c *gin.Context) {
+	claims, ok := authverify.ClaimsFromContext(c.Request.Context())
+	if !ok {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := h.logic.GetSessions(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.writeError(c, "failed to list sessions", err)
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].SessionToken == claims.SessionToken
+	}
+
+	c.JSON(http.StatusOK, models.SessionsResponse{Sessions: sessions})
+}
+
+// Revoke ends a session of the authenticated user. It must be routed behind
+// authverify.Verifier.GinMiddleware.
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, ok := userIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.RevokeSessionRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.logic.RevokeSession(c.Request.Context(), userID, &req); err != nil {
+		h.writeError(c, "failed to revoke session", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+// Package jobs contains the background jobs of the auth service.
+package jobs
+
+import (
+	"context"
+	"go-game-backend/pkg/logging"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeletionPurgerConfig holds configuration for DeletionPurger.
+type DeletionPurgerConfig struct {
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batch-size"`
+}
+
+// DeletionLogic defines the operation required by DeletionPurger.
+type DeletionLogic interface {
+	PurgeDeletedPlayers(ctx context.Context, limit int) (int, error)
+}
+
+// DeletionPurger periodically deletes the players whose deletion grace
+// period has passed.
+type DeletionPurger struct {
+	logic  DeletionLogic
+	cfg    *DeletionPurgerConfig
+	logger *logging.ZapLogger
+}
+
+// NewDeletionPurger creates a new DeletionPurger instance.
+func NewDeletionPurger(logic DeletionLogic, cfg *DeletionPurgerConfig, logger *logging.ZapLogger) *DeletionPurger {
+	return &DeletionPurger{logic: logic, cfg: cfg, logger: logger}
+}
+
+// Run starts the purge loop and blocks until the context is done.
+func (p *DeletionPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+// purge deletes due players batch by batch until none are left.
+func (p *DeletionPurger) purge(ctx context.Context) {
+	for ctx.Err() == nil {
+		purged, err := p.logic.PurgeDeletedPlayers(ctx, p.cfg.BatchSize)
+		if purged > 0 {
+			p.logger.InfoCtx(ctx, "purged deleted players", zap.Int("count", purged))
+		}
+		if err != nil {
+			p.logger.ErrorCtx(ctx, "purge deleted players", zap.Error(err))
+			return
+		}
+		if purged < p.cfg.BatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package ratelimit holds the request limits of the auth service, shared by
+// its HTTP routes and the gRPC methods matching them.
+package ratelimit
+
+import (
+	"go-game-backend/services/auth/pkg/models"
+
+	"github.com/google/uuid"
+
+	redisstore "go-game-backend/pkg/redis"
+)
+
+// Config holds the request limits of the auth service. The gRPC methods
+// matching a route share its limits and keys, so the limits cannot be
+// bypassed by switching APIs.
+type Config struct {
+	// TrustedProxies lists the proxies allowed to report the client IP in
+	// X-Forwarded-For, or in x-forwarded-for metadata for gRPC calls. Other
+	// clients are identified by their remote address.
+	TrustedProxies []string              `yaml:"trusted-proxies"`
+	Routes         map[string]RouteLimit `yaml:"routes"`
+}
+
+// RouteLimit limits a route per client IP and per the credential the
+// request targets: a login token, email, MFA token or user ID depending on
+// the route.
+type RouteLimit struct {
+	PerIP  redisstore.Limit `yaml:"per-ip"`
+	PerKey redisstore.Limit `yaml:"per-key"`
+}
+
+// UUIDValue canonicalizes login and refresh tokens, accepting every UUID
+// spelling the handlers parse.
+func UUIDValue(value string) (string, bool) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	return id.String(), true
+}
+
+// EmailValue canonicalizes emails the way the service looks them up.
+func EmailValue(value string) (string, bool) {
+	email := models.NormalizeEmail(value)
+	return email, email != ""
+}
+
+// TokenValue keeps opaque tokens, such as MFA tokens, which are matched
+// exactly.
+func TokenValue(value string) (string, bool) {
+	return value, value != ""
+}
@@ -0,0 +1,87 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/repository/postgres/sqlc"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	postgresstore "go-game-backend/pkg/postgres"
+)
+
+// AuditRepo provides access to the audit log stored in PostgreSQL.
+type AuditRepo struct {
+	postgresstore.BaseRepo[*sqlc.Queries]
+}
+
+// NewAuditRepo creates a new AuditRepo instance bound to the given pool.
+func NewAuditRepo(pool *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool))}
+}
+
+// AddAuditEntry appends an entry to the audit log.
+func (r *AuditRepo) AddAuditEntry(ctx context.Context, entry dto.AuditEntry) error {
+	details := entry.Details
+	if details == nil {
+		details = []byte("{}")
+	}
+
+	err := r.Q(ctx).AddAuditEntry(ctx, sqlc.AddAuditEntryParams{
+		PlayerID:  pgtype.Int8{Int64: entry.PlayerID, Valid: entry.PlayerID != 0},
+		Event:     entry.Event,
+		Ip:        entry.IP,
+		UserAgent: entry.UserAgent,
+		Details:   details,
+	})
+	if err != nil {
+		return fmt.Errorf("insert audit entry query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// ListAuditEntries returns up to limit entries of the player created in
+// [from, to), newest first.
+func (r *AuditRepo) ListAuditEntries(
+	ctx context.Context,
+	playerID int64,
+	from, to time.Time,
+	limit int,
+) ([]dto.AuditEntry, error) {
+	rows, err := r.Q(ctx).ListAuditEntries(ctx, sqlc.ListAuditEntriesParams{
+		PlayerID:   pgtype.Int8{Int64: playerID, Valid: true},
+		FromTime:   pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:     pgtype.Timestamptz{Time: to, Valid: true},
+		MaxEntries: int32(limit), //nolint:gosec // limit is bounded by the caller
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries query: %w", classifyErr(err))
+	}
+
+	entries := make([]dto.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, dto.AuditEntry{
+			ID:        row.ID,
+			PlayerID:  row.PlayerID.Int64,
+			Event:     row.Event,
+			IP:        row.Ip,
+			UserAgent: row.UserAgent,
+			Details:   row.Details,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	return entries, nil
+}
+
+// AnonymizeAuditEntries erases the client details recorded in the entries of
+// the player, keeping what happened and when.
+func (r *AuditRepo) AnonymizeAuditEntries(ctx context.Context, playerID int64) error {
+	err := r.Q(ctx).AnonymizeAuditEntries(ctx, pgtype.Int8{Int64: playerID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("anonymize audit entries query: %w", classifyErr(err))
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/repository/postgres/sqlc"
+	"go-game-backend/services/auth/internal/services"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	postgresstore "go-game-backend/pkg/postgres"
+)
+
+// BanRepo provides access to player bans stored in PostgreSQL.
+type BanRepo struct {
+	postgresstore.BaseRepo[*sqlc.Queries]
+}
+
+// NewBanRepo creates a new BanRepo instance bound to the given pool.
+func NewBanRepo(pool *pgxpool.Pool) *BanRepo {
+	return &BanRepo{BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool))}
+}
+
+// AddBan stores a new ban and returns it with the ID and creation time
+// filled in.
+func (r *BanRepo) AddBan(ctx context.Context, ban dto.Ban) (dto.Ban, error) {
+	row, err := r.Q(ctx).AddBan(ctx, sqlc.AddBanParams{
+		PlayerID:  ban.PlayerID,
+		Reason:    ban.Reason,
+		IssuedBy:  ban.IssuedBy,
+		ExpiresAt: pgtype.Timestamptz{Time: ban.ExpiresAt, Valid: !ban.ExpiresAt.IsZero()},
+	})
+	if err != nil {
+		return dto.Ban{}, fmt.Errorf("insert ban query: %w", classifyErr(err))
+	}
+	ban.ID = row.ID
+	ban.CreatedAt = row.CreatedAt.Time
+	return ban, nil
+}
+
+// FindActiveBan retrieves the ban of the player that lasts the longest among
+// the ones in effect.
+func (r *BanRepo) FindActiveBan(ctx context.Context, playerID int64) (dto.Ban, error) {
+	row, err := r.Q(ctx).FindActiveBan(ctx, playerID)
+	if err != nil {
+		return dto.Ban{}, fmt.Errorf("find active ban query: %w", classifyErr(err))
+	}
+	return dto.Ban{
+		ID:        row.ID,
+		PlayerID:  row.PlayerID,
+		Reason:    row.Reason,
+		IssuedBy:  row.IssuedBy,
+		CreatedAt: row.CreatedAt.Time,
+		ExpiresAt: row.ExpiresAt.Time,
+	}, nil
+}
+
+// LiftBans ends every ban of the player that is in effect.
+func (r *BanRepo) LiftBans(ctx context.Context, playerID int64) error {
+	rows, err := r.Q(ctx).LiftBans(ctx, playerID)
+	if err != nil {
+		return fmt.Errorf("lift bans query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("lift bans query: %w", services.ErrNotFound)
+	}
+	return nil
+}
+
+// ListBans returns every ban of the player, including expired and lifted
+// ones, oldest first.
+func (r *BanRepo) ListBans(ctx context.Context, playerID int64) ([]dto.Ban, error) {
+	rows, err := r.Q(ctx).ListBans(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("list bans query: %w", classifyErr(err))
+	}
+
+	bans := make([]dto.Ban, 0, len(rows))
+	for _, row := range rows {
+		bans = append(bans, dto.Ban{
+			ID:        row.ID,
+			PlayerID:  row.PlayerID,
+			Reason:    row.Reason,
+			IssuedBy:  row.IssuedBy,
+			CreatedAt: row.CreatedAt.Time,
+			ExpiresAt: row.ExpiresAt.Time,
+			LiftedAt:  row.LiftedAt.Time,
+		})
+	}
+	return bans, nil
+}
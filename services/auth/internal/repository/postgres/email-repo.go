@@ -0,0 +1,144 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/repository/postgres/sqlc"
+	"go-game-backend/services/auth/internal/services"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	postgresstore "go-game-backend/pkg/postgres"
+)
+
+// EmailRepo provides access to email credentials and email tokens stored in
+// PostgreSQL.
+type EmailRepo struct {
+	postgresstore.BaseRepo[*sqlc.Queries]
+}
+
+// NewEmailRepo creates a new EmailRepo instance bound to the given pool.
+func NewEmailRepo(pool *pgxpool.Pool) *EmailRepo {
+	return &EmailRepo{BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool))}
+}
+
+// AddEmailCredential binds an email and password hash to the player.
+func (r *EmailRepo) AddEmailCredential(ctx context.Context, playerID int64, email, passwordHash string) error {
+	err := r.Q(ctx).AddEmailCredential(ctx, sqlc.AddEmailCredentialParams{
+		PlayerID:     playerID,
+		Email:        email,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return fmt.Errorf("insert email credential query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// DeleteEmailCredential removes the email credential of the player.
+func (r *EmailRepo) DeleteEmailCredential(ctx context.Context, playerID int64) error {
+	if err := r.Q(ctx).DeleteEmailCredential(ctx, playerID); err != nil {
+		return fmt.Errorf("delete email credential query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// FindEmailCredentialByEmail retrieves the credential registered for the email.
+func (r *EmailRepo) FindEmailCredentialByEmail(ctx context.Context, email string) (dto.EmailCredential, error) {
+	row, err := r.Q(ctx).FindEmailCredentialByEmail(ctx, email)
+	if err != nil {
+		return dto.EmailCredential{}, fmt.Errorf("find email credential query: %w", classifyErr(err))
+	}
+	return dto.EmailCredential{
+		PlayerID:     row.PlayerID,
+		Email:        row.Email,
+		PasswordHash: row.PasswordHash,
+		Verified:     row.VerifiedAt.Valid,
+	}, nil
+}
+
+// FindEmailCredentialByPlayer retrieves the credential bound to the player.
+func (r *EmailRepo) FindEmailCredentialByPlayer(ctx context.Context, playerID int64) (dto.EmailCredential, error) {
+	row, err := r.Q(ctx).FindEmailCredentialByPlayer(ctx, playerID)
+	if err != nil {
+		return dto.EmailCredential{}, fmt.Errorf("find email credential query: %w", classifyErr(err))
+	}
+	return dto.EmailCredential{
+		PlayerID:     row.PlayerID,
+		Email:        row.Email,
+		PasswordHash: row.PasswordHash,
+		Verified:     row.VerifiedAt.Valid,
+	}, nil
+}
+
+// MarkEmailVerified records that the player confirmed ownership of the email.
+func (r *EmailRepo) MarkEmailVerified(ctx context.Context, playerID int64) error {
+	if _, err := r.Q(ctx).MarkEmailVerified(ctx, playerID); err != nil {
+		return fmt.Errorf("mark email verified query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// UpdatePasswordHash replaces the password hash of the player.
+func (r *EmailRepo) UpdatePasswordHash(ctx context.Context, playerID int64, passwordHash string) error {
+	rows, err := r.Q(ctx).UpdatePasswordHash(ctx, sqlc.UpdatePasswordHashParams{
+		PlayerID:     playerID,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return fmt.Errorf("update password hash query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("update password hash query: %w", services.ErrNotFound)
+	}
+	return nil
+}
+
+// AddEmailToken stores the hash of a single-use token sent to the player.
+func (r *EmailRepo) AddEmailToken(
+	ctx context.Context,
+	tokenHash []byte,
+	playerID int64,
+	purpose string,
+	expiresAt time.Time,
+) error {
+	err := r.Q(ctx).AddEmailToken(ctx, sqlc.AddEmailTokenParams{
+		TokenHash: tokenHash,
+		PlayerID:  playerID,
+		Purpose:   purpose,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("insert email token query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// ConsumeEmailToken deletes an unexpired token with the given purpose and
+// returns the player it was issued to.
+func (r *EmailRepo) ConsumeEmailToken(ctx context.Context, tokenHash []byte, purpose string) (int64, error) {
+	playerID, err := r.Q(ctx).ConsumeEmailToken(ctx, sqlc.ConsumeEmailTokenParams{
+		TokenHash: tokenHash,
+		Purpose:   purpose,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("consume email token query: %w", classifyErr(err))
+	}
+	return playerID, nil
+}
+
+// DeleteEmailTokens removes every token with the given purpose issued to the
+// player.
+func (r *EmailRepo) DeleteEmailTokens(ctx context.Context, playerID int64, purpose string) error {
+	err := r.Q(ctx).DeleteEmailTokens(ctx, sqlc.DeleteEmailTokensParams{
+		PlayerID: playerID,
+		Purpose:  purpose,
+	})
+	if err != nil {
+		return fmt.Errorf("delete email tokens query: %w", classifyErr(err))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package postgresrepo
+
+import (
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+
+	"github.com/jackc/pgx/v5"
+
+	postgresstore "go-game-backend/pkg/postgres"
+)
+
+// classifyErr wraps driver errors with the matching service sentinel so the
+// business layer can tell them apart without depending on pgx.
+func classifyErr(err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotFound),
+		errors.Is(err, services.ErrAlreadyExists),
+		errors.Is(err, services.ErrStorageUnavailable):
+		return err
+	case errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("%w: %w", services.ErrNotFound, err)
+	case postgresstore.IsUniqueViolation(err):
+		return fmt.Errorf("%w: %w", services.ErrAlreadyExists, err)
+	case postgresstore.IsUnavailable(err):
+		return fmt.Errorf("%w: %w", services.ErrStorageUnavailable, err)
+	default:
+		return err
+	}
+}
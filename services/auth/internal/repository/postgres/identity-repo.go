@@ -0,0 +1,95 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/repository/postgres/sqlc"
+	"go-game-backend/services/auth/internal/services"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	postgresstore "go-game-backend/pkg/postgres"
+)
+
+// IdentityRepo provides access to player identities stored in PostgreSQL.
+type IdentityRepo struct {
+	postgresstore.BaseRepo[*sqlc.Queries]
+}
+
+// NewIdentityRepo creates a new IdentityRepo instance bound to the given pool.
+func NewIdentityRepo(pool *pgxpool.Pool) *IdentityRepo {
+	return &IdentityRepo{BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool))}
+}
+
+// AddIdentity binds the identity to the player.
+func (r *IdentityRepo) AddIdentity(ctx context.Context, playerID int64, provider, subject string) error {
+	err := r.Q(ctx).AddIdentity(ctx, sqlc.AddIdentityParams{
+		PlayerID: playerID,
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		return fmt.Errorf("insert identity query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// FindPlayerByIdentity retrieves the ID of the player owning the identity.
+func (r *IdentityRepo) FindPlayerByIdentity(ctx context.Context, provider, subject string) (int64, error) {
+	id, err := r.Q(ctx).FindPlayerByIdentity(ctx, sqlc.FindPlayerByIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("find player by identity query: %w", classifyErr(err))
+	}
+	return id, nil
+}
+
+// ListIdentities returns the identities of the player in the order they were
+// added.
+func (r *IdentityRepo) ListIdentities(ctx context.Context, playerID int64) ([]dto.Identity, error) {
+	rows, err := r.Q(ctx).ListIdentities(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("list identities query: %w", classifyErr(err))
+	}
+
+	identities := make([]dto.Identity, 0, len(rows))
+	for _, row := range rows {
+		identities = append(identities, dto.Identity{
+			Provider:  row.Provider,
+			Subject:   row.Subject,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	return identities, nil
+}
+
+// DeleteIdentity removes the identity from the player.
+func (r *IdentityRepo) DeleteIdentity(ctx context.Context, playerID int64, provider, subject string) error {
+	rows, err := r.Q(ctx).DeleteIdentity(ctx, sqlc.DeleteIdentityParams{
+		PlayerID: playerID,
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		return fmt.Errorf("delete identity query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("delete identity query: %w", services.ErrNotFound)
+	}
+	return nil
+}
+
+// MoveIdentities transfers every identity of one player to another.
+func (r *IdentityRepo) MoveIdentities(ctx context.Context, sourcePlayerID, targetPlayerID int64) error {
+	err := r.Q(ctx).MoveIdentities(ctx, sqlc.MoveIdentitiesParams{
+		TargetPlayerID: targetPlayerID,
+		SourcePlayerID: sourcePlayerID,
+	})
+	if err != nil {
+		return fmt.Errorf("move identities query: %w", classifyErr(err))
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/repository/postgres/sqlc"
+	"go-game-backend/services/auth/internal/services"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	postgresstore "go-game-backend/pkg/postgres"
+)
+
+// MFARepo provides access to TOTP secrets and recovery codes stored in
+// PostgreSQL.
+type MFARepo struct {
+	postgresstore.BaseRepo[*sqlc.Queries]
+}
+
+// NewMFARepo creates a new MFARepo instance bound to the given pool.
+func NewMFARepo(pool *pgxpool.Pool) *MFARepo {
+	return &MFARepo{BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool))}
+}
+
+// UpsertTOTPSecret stores a new unconfirmed TOTP secret for the player,
+// replacing a previous unconfirmed one. services.ErrAlreadyExists is returned
+// when the player already has a confirmed secret.
+func (r *MFARepo) UpsertTOTPSecret(ctx context.Context, playerID int64, secretEncrypted []byte) error {
+	rows, err := r.Q(ctx).UpsertTOTPSecret(ctx, sqlc.UpsertTOTPSecretParams{
+		PlayerID:        playerID,
+		SecretEncrypted: secretEncrypted,
+	})
+	if err != nil {
+		return fmt.Errorf("upsert totp secret query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("upsert totp secret query: %w", services.ErrAlreadyExists)
+	}
+	return nil
+}
+
+// GetTOTP retrieves the TOTP secret of the player.
+func (r *MFARepo) GetTOTP(ctx context.Context, playerID int64) (dto.TOTP, error) {
+	row, err := r.Q(ctx).GetTOTP(ctx, playerID)
+	if err != nil {
+		return dto.TOTP{}, fmt.Errorf("get totp query: %w", classifyErr(err))
+	}
+	return dto.TOTP{
+		SecretEncrypted: row.SecretEncrypted,
+		Confirmed:       row.ConfirmedAt.Valid,
+		LastUsedStep:    row.LastUsedStep,
+	}, nil
+}
+
+// ConfirmTOTP marks the unconfirmed TOTP secret of the player as confirmed.
+func (r *MFARepo) ConfirmTOTP(ctx context.Context, playerID int64) error {
+	rows, err := r.Q(ctx).ConfirmTOTP(ctx, playerID)
+	if err != nil {
+		return fmt.Errorf("confirm totp query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("confirm totp query: %w", services.ErrNotFound)
+	}
+	return nil
+}
+
+// UseTOTPStep records the time step of an accepted code.
+// services.ErrAlreadyExists is returned when the step or a later one was
+// already used, so that a code cannot be replayed.
+func (r *MFARepo) UseTOTPStep(ctx context.Context, playerID, step int64) error {
+	rows, err := r.Q(ctx).UseTOTPStep(ctx, sqlc.UseTOTPStepParams{
+		PlayerID:     playerID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return fmt.Errorf("use totp step query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("use totp step query: %w", services.ErrAlreadyExists)
+	}
+	return nil
+}
+
+// DeleteTOTP removes the TOTP secret of the player.
+func (r *MFARepo) DeleteTOTP(ctx context.Context, playerID int64) error {
+	if err := r.Q(ctx).DeleteTOTP(ctx, playerID); err != nil {
+		return fmt.Errorf("delete totp query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// AddRecoveryCodes stores the hashes of new recovery codes for the player.
+func (r *MFARepo) AddRecoveryCodes(ctx context.Context, playerID int64, codeHashes [][]byte) error {
+	for _, codeHash := range codeHashes {
+		err := r.Q(ctx).AddRecoveryCode(ctx, sqlc.AddRecoveryCodeParams{
+			PlayerID: playerID,
+			CodeHash: codeHash,
+		})
+		if err != nil {
+			return fmt.Errorf("insert recovery code query: %w", classifyErr(err))
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of the player as used.
+func (r *MFARepo) UseRecoveryCode(ctx context.Context, playerID int64, codeHash []byte) error {
+	rows, err := r.Q(ctx).UseRecoveryCode(ctx, sqlc.UseRecoveryCodeParams{
+		PlayerID: playerID,
+		CodeHash: codeHash,
+	})
+	if err != nil {
+		return fmt.Errorf("use recovery code query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("use recovery code query: %w", services.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteRecoveryCodes removes every recovery code of the player.
+func (r *MFARepo) DeleteRecoveryCodes(ctx context.Context, playerID int64) error {
+	if err := r.Q(ctx).DeleteRecoveryCodes(ctx, playerID); err != nil {
+		return fmt.Errorf("delete recovery codes query: %w", classifyErr(err))
+	}
+	return nil
+}
@@ -9,20 +9,50 @@ import (
 
 // Repos aggregates all PostgreSQL repositories used by the auth service.
 type Repos struct {
-	user   authsvc.UserRepository
-	outbox authsvc.OutboxRepository
+	user     authsvc.UserRepository
+	identity authsvc.IdentityRepository
+	email    authsvc.EmailRepository
+	mfa      authsvc.MFARepository
+	ban      authsvc.BanRepository
+	audit    authsvc.AuditRepository
+	clients  authsvc.ServiceClientRepository
+	outbox   authsvc.OutboxRepository
 }
 
 // NewRepos creates Repos with initialized sub-repositories.
 func NewRepos(pool *pgxpool.Pool) *Repos {
 	return &Repos{
-		user:   NewUserRepo(pool),
-		outbox: outboxpkg.NewRepository(pool),
+		user:     NewUserRepo(pool),
+		identity: NewIdentityRepo(pool),
+		email:    NewEmailRepo(pool),
+		mfa:      NewMFARepo(pool),
+		ban:      NewBanRepo(pool),
+		audit:    NewAuditRepo(pool),
+		clients:  NewServiceClientRepo(pool),
+		outbox:   outboxpkg.NewRepository(pool),
 	}
 }
 
 // User returns repository for user credentials.
 func (r *Repos) User() authsvc.UserRepository { return r.user }
 
+// Identity returns repository for player identities.
+func (r *Repos) Identity() authsvc.IdentityRepository { return r.identity }
+
+// Email returns repository for email credentials.
+func (r *Repos) Email() authsvc.EmailRepository { return r.email }
+
+// MFA returns repository for second factor credentials.
+func (r *Repos) MFA() authsvc.MFARepository { return r.mfa }
+
+// Ban returns repository for player bans.
+func (r *Repos) Ban() authsvc.BanRepository { return r.ban }
+
+// Audit returns repository for the audit log.
+func (r *Repos) Audit() authsvc.AuditRepository { return r.audit }
+
+// ServiceClient returns repository for service clients.
+func (r *Repos) ServiceClient() authsvc.ServiceClientRepository { return r.clients }
+
 // Outbox returns repository for the outbox table.
 func (r *Repos) Outbox() authsvc.OutboxRepository { return r.outbox }
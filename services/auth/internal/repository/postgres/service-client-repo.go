@@ -0,0 +1,58 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/repository/postgres/sqlc"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	postgresstore "go-game-backend/pkg/postgres"
+)
+
+// ServiceClientRepo provides access to service clients stored in PostgreSQL.
+type ServiceClientRepo struct {
+	postgresstore.BaseRepo[*sqlc.Queries]
+}
+
+// NewServiceClientRepo creates a new ServiceClientRepo instance bound to the
+// given pool.
+func NewServiceClientRepo(pool *pgxpool.Pool) *ServiceClientRepo {
+	return &ServiceClientRepo{BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool))}
+}
+
+// AddServiceClient stores a new service client.
+func (r *ServiceClientRepo) AddServiceClient(ctx context.Context, client dto.ServiceClient) error {
+	scopes := client.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	err := r.Q(ctx).AddServiceClient(ctx, sqlc.AddServiceClientParams{
+		ClientID:    client.ClientID,
+		SecretHash:  client.SecretHash,
+		Scopes:      scopes,
+		Description: client.Description,
+	})
+	if err != nil {
+		return fmt.Errorf("insert service client query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// FindServiceClient retrieves the service client with the given ID.
+func (r *ServiceClientRepo) FindServiceClient(ctx context.Context, clientID string) (dto.ServiceClient, error) {
+	row, err := r.Q(ctx).FindServiceClient(ctx, clientID)
+	if err != nil {
+		return dto.ServiceClient{}, fmt.Errorf("find service client query: %w", classifyErr(err))
+	}
+	return dto.ServiceClient{
+		ClientID:    row.ClientID,
+		SecretHash:  row.SecretHash,
+		Scopes:      row.Scopes,
+		Description: row.Description,
+		CreatedAt:   row.CreatedAt.Time,
+		DisabledAt:  row.DisabledAt.Time,
+	}, nil
+}
@@ -17,6 +17,74 @@ type Outbox struct {
 }
 
 type PlayerCredential struct {
-	ID         int64
-	LoginToken pgtype.UUID
+	ID                  int64
+	DeletionRequestedAt pgtype.Timestamptz
+	DeleteAfter         pgtype.Timestamptz
+}
+
+type AuthAuditLog struct {
+	ID        int64
+	PlayerID  pgtype.Int8
+	Event     string
+	Ip        string
+	UserAgent string
+	Details   []byte
+	CreatedAt pgtype.Timestamptz
+}
+
+type PlayerBan struct {
+	ID        int64
+	PlayerID  int64
+	Reason    string
+	IssuedBy  string
+	CreatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+	LiftedAt  pgtype.Timestamptz
+}
+
+type PlayerEmailCredential struct {
+	PlayerID     int64
+	Email        string
+	PasswordHash string
+	VerifiedAt   pgtype.Timestamptz
+	CreatedAt    pgtype.Timestamptz
+	UpdatedAt    pgtype.Timestamptz
+}
+
+type PlayerIdentity struct {
+	ID        int64
+	PlayerID  int64
+	Provider  string
+	Subject   string
+	CreatedAt pgtype.Timestamptz
+}
+
+type PlayerRecoveryCode struct {
+	PlayerID int64
+	CodeHash []byte
+	UsedAt   pgtype.Timestamptz
+}
+
+type PlayerTotp struct {
+	PlayerID        int64
+	SecretEncrypted []byte
+	ConfirmedAt     pgtype.Timestamptz
+	LastUsedStep    int64
+	CreatedAt       pgtype.Timestamptz
+}
+
+type PlayerEmailToken struct {
+	TokenHash []byte
+	PlayerID  int64
+	Purpose   string
+	ExpiresAt pgtype.Timestamptz
+}
+
+type ServiceClient struct {
+	ClientID    string
+	SecretHash  string
+	Scopes      []string
+	Description string
+	CreatedAt   pgtype.Timestamptz
+	DisabledAt  pgtype.Timestamptz
 }
@@ -11,24 +11,715 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const addUser = `-- name: AddUser :one
-INSERT INTO player_credentials (login_token) VALUES ($1) RETURNING id
+const addPlayer = `-- name: AddPlayer :one
+INSERT INTO player_credentials DEFAULT VALUES RETURNING id
 `
 
-func (q *Queries) AddUser(ctx context.Context, loginToken pgtype.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, addUser, loginToken)
+func (q *Queries) AddPlayer(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, addPlayer)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
-const findUserByLoginToken = `-- name: FindUserByLoginToken :one
-SELECT id FROM player_credentials WHERE login_token = $1
+const lockPlayer = `-- name: LockPlayer :one
+SELECT id FROM player_credentials WHERE id = $1 FOR UPDATE
 `
 
-func (q *Queries) FindUserByLoginToken(ctx context.Context, loginToken pgtype.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, findUserByLoginToken, loginToken)
-	var id int64
+func (q *Queries) LockPlayer(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, lockPlayer, id)
 	err := row.Scan(&id)
 	return id, err
 }
+
+const deletePlayer = `-- name: DeletePlayer :exec
+DELETE FROM player_credentials WHERE id = $1
+`
+
+func (q *Queries) DeletePlayer(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deletePlayer, id)
+	return err
+}
+
+const getPlayer = `-- name: GetPlayer :one
+SELECT id, deletion_requested_at, delete_after FROM player_credentials WHERE id = $1
+`
+
+func (q *Queries) GetPlayer(ctx context.Context, id int64) (PlayerCredential, error) {
+	row := q.db.QueryRow(ctx, getPlayer, id)
+	var i PlayerCredential
+	err := row.Scan(&i.ID, &i.DeletionRequestedAt, &i.DeleteAfter)
+	return i, err
+}
+
+const requestPlayerDeletion = `-- name: RequestPlayerDeletion :one
+UPDATE player_credentials
+SET deletion_requested_at = COALESCE(deletion_requested_at, NOW()),
+    delete_after          = COALESCE(delete_after, $2)
+WHERE id = $1
+RETURNING delete_after
+`
+
+type RequestPlayerDeletionParams struct {
+	ID          int64
+	DeleteAfter pgtype.Timestamptz
+}
+
+func (q *Queries) RequestPlayerDeletion(ctx context.Context, arg RequestPlayerDeletionParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, requestPlayerDeletion, arg.ID, arg.DeleteAfter)
+	var delete_after pgtype.Timestamptz
+	err := row.Scan(&delete_after)
+	return delete_after, err
+}
+
+const cancelPlayerDeletion = `-- name: CancelPlayerDeletion :execrows
+UPDATE player_credentials SET deletion_requested_at = NULL, delete_after = NULL
+WHERE id = $1 AND delete_after IS NOT NULL
+`
+
+func (q *Queries) CancelPlayerDeletion(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, cancelPlayerDeletion, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listPlayersDueForDeletion = `-- name: ListPlayersDueForDeletion :many
+SELECT id FROM player_credentials WHERE delete_after <= $1 ORDER BY delete_after LIMIT $2
+`
+
+type ListPlayersDueForDeletionParams struct {
+	DeleteAfter pgtype.Timestamptz
+	Limit       int32
+}
+
+func (q *Queries) ListPlayersDueForDeletion(ctx context.Context, arg ListPlayersDueForDeletionParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listPlayersDueForDeletion, arg.DeleteAfter, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteDuePlayer = `-- name: DeleteDuePlayer :execrows
+DELETE FROM player_credentials WHERE id = $1 AND delete_after <= $2
+`
+
+type DeleteDuePlayerParams struct {
+	ID          int64
+	DeleteAfter pgtype.Timestamptz
+}
+
+func (q *Queries) DeleteDuePlayer(ctx context.Context, arg DeleteDuePlayerParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDuePlayer, arg.ID, arg.DeleteAfter)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const addEmailCredential = `-- name: AddEmailCredential :exec
+INSERT INTO player_email_credentials (player_id, email, password_hash) VALUES ($1, $2, $3)
+`
+
+type AddEmailCredentialParams struct {
+	PlayerID     int64
+	Email        string
+	PasswordHash string
+}
+
+func (q *Queries) AddEmailCredential(ctx context.Context, arg AddEmailCredentialParams) error {
+	_, err := q.db.Exec(ctx, addEmailCredential, arg.PlayerID, arg.Email, arg.PasswordHash)
+	return err
+}
+
+const findEmailCredentialByEmail = `-- name: FindEmailCredentialByEmail :one
+SELECT player_id, email, password_hash, verified_at FROM player_email_credentials WHERE email = $1
+`
+
+type FindEmailCredentialByEmailRow struct {
+	PlayerID     int64
+	Email        string
+	PasswordHash string
+	VerifiedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) FindEmailCredentialByEmail(ctx context.Context, email string) (FindEmailCredentialByEmailRow, error) {
+	row := q.db.QueryRow(ctx, findEmailCredentialByEmail, email)
+	var i FindEmailCredentialByEmailRow
+	err := row.Scan(
+		&i.PlayerID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const findEmailCredentialByPlayer = `-- name: FindEmailCredentialByPlayer :one
+SELECT player_id, email, password_hash, verified_at FROM player_email_credentials WHERE player_id = $1
+`
+
+type FindEmailCredentialByPlayerRow struct {
+	PlayerID     int64
+	Email        string
+	PasswordHash string
+	VerifiedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) FindEmailCredentialByPlayer(ctx context.Context, playerID int64) (FindEmailCredentialByPlayerRow, error) {
+	row := q.db.QueryRow(ctx, findEmailCredentialByPlayer, playerID)
+	var i FindEmailCredentialByPlayerRow
+	err := row.Scan(
+		&i.PlayerID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const markEmailVerified = `-- name: MarkEmailVerified :execrows
+UPDATE player_email_credentials SET verified_at = NOW(), updated_at = NOW()
+WHERE player_id = $1 AND verified_at IS NULL
+`
+
+func (q *Queries) MarkEmailVerified(ctx context.Context, playerID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, markEmailVerified, playerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updatePasswordHash = `-- name: UpdatePasswordHash :execrows
+UPDATE player_email_credentials SET password_hash = $2, updated_at = NOW() WHERE player_id = $1
+`
+
+type UpdatePasswordHashParams struct {
+	PlayerID     int64
+	PasswordHash string
+}
+
+func (q *Queries) UpdatePasswordHash(ctx context.Context, arg UpdatePasswordHashParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updatePasswordHash, arg.PlayerID, arg.PasswordHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const addEmailToken = `-- name: AddEmailToken :exec
+INSERT INTO player_email_tokens (token_hash, player_id, purpose, expires_at) VALUES ($1, $2, $3, $4)
+`
+
+type AddEmailTokenParams struct {
+	TokenHash []byte
+	PlayerID  int64
+	Purpose   string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) AddEmailToken(ctx context.Context, arg AddEmailTokenParams) error {
+	_, err := q.db.Exec(ctx, addEmailToken,
+		arg.TokenHash,
+		arg.PlayerID,
+		arg.Purpose,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const consumeEmailToken = `-- name: ConsumeEmailToken :one
+DELETE FROM player_email_tokens
+WHERE token_hash = $1 AND purpose = $2 AND expires_at > NOW()
+RETURNING player_id
+`
+
+type ConsumeEmailTokenParams struct {
+	TokenHash []byte
+	Purpose   string
+}
+
+func (q *Queries) ConsumeEmailToken(ctx context.Context, arg ConsumeEmailTokenParams) (int64, error) {
+	row := q.db.QueryRow(ctx, consumeEmailToken, arg.TokenHash, arg.Purpose)
+	var player_id int64
+	err := row.Scan(&player_id)
+	return player_id, err
+}
+
+const deleteEmailTokens = `-- name: DeleteEmailTokens :exec
+DELETE FROM player_email_tokens WHERE player_id = $1 AND purpose = $2
+`
+
+type DeleteEmailTokensParams struct {
+	PlayerID int64
+	Purpose  string
+}
+
+func (q *Queries) DeleteEmailTokens(ctx context.Context, arg DeleteEmailTokensParams) error {
+	_, err := q.db.Exec(ctx, deleteEmailTokens, arg.PlayerID, arg.Purpose)
+	return err
+}
+
+const deleteEmailCredential = `-- name: DeleteEmailCredential :exec
+DELETE FROM player_email_credentials WHERE player_id = $1
+`
+
+func (q *Queries) DeleteEmailCredential(ctx context.Context, playerID int64) error {
+	_, err := q.db.Exec(ctx, deleteEmailCredential, playerID)
+	return err
+}
+
+const addIdentity = `-- name: AddIdentity :exec
+INSERT INTO player_identities (player_id, provider, subject) VALUES ($1, $2, $3)
+`
+
+type AddIdentityParams struct {
+	PlayerID int64
+	Provider string
+	Subject  string
+}
+
+func (q *Queries) AddIdentity(ctx context.Context, arg AddIdentityParams) error {
+	_, err := q.db.Exec(ctx, addIdentity, arg.PlayerID, arg.Provider, arg.Subject)
+	return err
+}
+
+const findPlayerByIdentity = `-- name: FindPlayerByIdentity :one
+SELECT player_id FROM player_identities WHERE provider = $1 AND subject = $2
+`
+
+type FindPlayerByIdentityParams struct {
+	Provider string
+	Subject  string
+}
+
+func (q *Queries) FindPlayerByIdentity(ctx context.Context, arg FindPlayerByIdentityParams) (int64, error) {
+	row := q.db.QueryRow(ctx, findPlayerByIdentity, arg.Provider, arg.Subject)
+	var player_id int64
+	err := row.Scan(&player_id)
+	return player_id, err
+}
+
+const listIdentities = `-- name: ListIdentities :many
+SELECT provider, subject, created_at FROM player_identities WHERE player_id = $1 ORDER BY id
+`
+
+type ListIdentitiesRow struct {
+	Provider  string
+	Subject   string
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) ListIdentities(ctx context.Context, playerID int64) ([]ListIdentitiesRow, error) {
+	rows, err := q.db.Query(ctx, listIdentities, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListIdentitiesRow
+	for rows.Next() {
+		var i ListIdentitiesRow
+		if err := rows.Scan(&i.Provider, &i.Subject, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteIdentity = `-- name: DeleteIdentity :execrows
+DELETE FROM player_identities WHERE player_id = $1 AND provider = $2 AND subject = $3
+`
+
+type DeleteIdentityParams struct {
+	PlayerID int64
+	Provider string
+	Subject  string
+}
+
+func (q *Queries) DeleteIdentity(ctx context.Context, arg DeleteIdentityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteIdentity, arg.PlayerID, arg.Provider, arg.Subject)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const moveIdentities = `-- name: MoveIdentities :exec
+UPDATE player_identities SET player_id = $1 WHERE player_id = $2
+`
+
+type MoveIdentitiesParams struct {
+	TargetPlayerID int64
+	SourcePlayerID int64
+}
+
+func (q *Queries) MoveIdentities(ctx context.Context, arg MoveIdentitiesParams) error {
+	_, err := q.db.Exec(ctx, moveIdentities, arg.TargetPlayerID, arg.SourcePlayerID)
+	return err
+}
+
+const upsertTOTPSecret = `-- name: UpsertTOTPSecret :execrows
+INSERT INTO player_totp (player_id, secret_encrypted) VALUES ($1, $2)
+ON CONFLICT (player_id) DO UPDATE
+SET secret_encrypted = EXCLUDED.secret_encrypted, last_used_step = 0, created_at = NOW()
+WHERE player_totp.confirmed_at IS NULL
+`
+
+type UpsertTOTPSecretParams struct {
+	PlayerID        int64
+	SecretEncrypted []byte
+}
+
+func (q *Queries) UpsertTOTPSecret(ctx context.Context, arg UpsertTOTPSecretParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertTOTPSecret, arg.PlayerID, arg.SecretEncrypted)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getTOTP = `-- name: GetTOTP :one
+SELECT secret_encrypted, confirmed_at, last_used_step FROM player_totp WHERE player_id = $1
+`
+
+type GetTOTPRow struct {
+	SecretEncrypted []byte
+	ConfirmedAt     pgtype.Timestamptz
+	LastUsedStep    int64
+}
+
+func (q *Queries) GetTOTP(ctx context.Context, playerID int64) (GetTOTPRow, error) {
+	row := q.db.QueryRow(ctx, getTOTP, playerID)
+	var i GetTOTPRow
+	err := row.Scan(&i.SecretEncrypted, &i.ConfirmedAt, &i.LastUsedStep)
+	return i, err
+}
+
+const confirmTOTP = `-- name: ConfirmTOTP :execrows
+UPDATE player_totp SET confirmed_at = NOW() WHERE player_id = $1 AND confirmed_at IS NULL
+`
+
+func (q *Queries) ConfirmTOTP(ctx context.Context, playerID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, confirmTOTP, playerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useTOTPStep = `-- name: UseTOTPStep :execrows
+UPDATE player_totp SET last_used_step = $2 WHERE player_id = $1 AND last_used_step < $2
+`
+
+type UseTOTPStepParams struct {
+	PlayerID     int64
+	LastUsedStep int64
+}
+
+func (q *Queries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useTOTPStep, arg.PlayerID, arg.LastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTOTP = `-- name: DeleteTOTP :exec
+DELETE FROM player_totp WHERE player_id = $1
+`
+
+func (q *Queries) DeleteTOTP(ctx context.Context, playerID int64) error {
+	_, err := q.db.Exec(ctx, deleteTOTP, playerID)
+	return err
+}
+
+const addRecoveryCode = `-- name: AddRecoveryCode :exec
+INSERT INTO player_recovery_codes (player_id, code_hash) VALUES ($1, $2)
+`
+
+type AddRecoveryCodeParams struct {
+	PlayerID int64
+	CodeHash []byte
+}
+
+func (q *Queries) AddRecoveryCode(ctx context.Context, arg AddRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, addRecoveryCode, arg.PlayerID, arg.CodeHash)
+	return err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE player_recovery_codes SET used_at = NOW()
+WHERE player_id = $1 AND code_hash = $2 AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	PlayerID int64
+	CodeHash []byte
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.PlayerID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM player_recovery_codes WHERE player_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, playerID int64) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, playerID)
+	return err
+}
+
+const addBan = `-- name: AddBan :one
+INSERT INTO player_bans (player_id, reason, issued_by, expires_at) VALUES ($1, $2, $3, $4)
+RETURNING id, created_at
+`
+
+type AddBanParams struct {
+	PlayerID  int64
+	Reason    string
+	IssuedBy  string
+	ExpiresAt pgtype.Timestamptz
+}
+
+type AddBanRow struct {
+	ID        int64
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) AddBan(ctx context.Context, arg AddBanParams) (AddBanRow, error) {
+	row := q.db.QueryRow(ctx, addBan,
+		arg.PlayerID,
+		arg.Reason,
+		arg.IssuedBy,
+		arg.ExpiresAt,
+	)
+	var i AddBanRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
+const findActiveBan = `-- name: FindActiveBan :one
+SELECT id, player_id, reason, issued_by, created_at, expires_at
+FROM player_bans
+WHERE player_id = $1 AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY expires_at DESC NULLS FIRST
+LIMIT 1
+`
+
+type FindActiveBanRow struct {
+	ID        int64
+	PlayerID  int64
+	Reason    string
+	IssuedBy  string
+	CreatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) FindActiveBan(ctx context.Context, playerID int64) (FindActiveBanRow, error) {
+	row := q.db.QueryRow(ctx, findActiveBan, playerID)
+	var i FindActiveBanRow
+	err := row.Scan(
+		&i.ID,
+		&i.PlayerID,
+		&i.Reason,
+		&i.IssuedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const listBans = `-- name: ListBans :many
+SELECT id, player_id, reason, issued_by, created_at, expires_at, lifted_at
+FROM player_bans
+WHERE player_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListBans(ctx context.Context, playerID int64) ([]PlayerBan, error) {
+	rows, err := q.db.Query(ctx, listBans, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlayerBan
+	for rows.Next() {
+		var i PlayerBan
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlayerID,
+			&i.Reason,
+			&i.IssuedBy,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.LiftedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const liftBans = `-- name: LiftBans :execrows
+UPDATE player_bans SET lifted_at = NOW()
+WHERE player_id = $1 AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+`
+
+func (q *Queries) LiftBans(ctx context.Context, playerID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, liftBans, playerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const addAuditEntry = `-- name: AddAuditEntry :exec
+INSERT INTO auth_audit_log (player_id, event, ip, user_agent, details) VALUES ($1, $2, $3, $4, $5)
+`
+
+type AddAuditEntryParams struct {
+	PlayerID  pgtype.Int8
+	Event     string
+	Ip        string
+	UserAgent string
+	Details   []byte
+}
+
+func (q *Queries) AddAuditEntry(ctx context.Context, arg AddAuditEntryParams) error {
+	_, err := q.db.Exec(ctx, addAuditEntry,
+		arg.PlayerID,
+		arg.Event,
+		arg.Ip,
+		arg.UserAgent,
+		arg.Details,
+	)
+	return err
+}
+
+const listAuditEntries = `-- name: ListAuditEntries :many
+SELECT id, player_id, event, ip, user_agent, details, created_at
+FROM auth_audit_log
+WHERE player_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type ListAuditEntriesParams struct {
+	PlayerID   pgtype.Int8
+	FromTime   pgtype.Timestamptz
+	ToTime     pgtype.Timestamptz
+	MaxEntries int32
+}
+
+func (q *Queries) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuthAuditLog, error) {
+	rows, err := q.db.Query(ctx, listAuditEntries,
+		arg.PlayerID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.MaxEntries,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuthAuditLog
+	for rows.Next() {
+		var i AuthAuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlayerID,
+			&i.Event,
+			&i.Ip,
+			&i.UserAgent,
+			&i.Details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const anonymizeAuditEntries = `-- name: AnonymizeAuditEntries :exec
+UPDATE auth_audit_log SET ip = '', user_agent = '' WHERE player_id = $1
+`
+
+func (q *Queries) AnonymizeAuditEntries(ctx context.Context, playerID pgtype.Int8) error {
+	_, err := q.db.Exec(ctx, anonymizeAuditEntries, playerID)
+	return err
+}
+
+const addServiceClient = `-- name: AddServiceClient :exec
+INSERT INTO service_clients (client_id, secret_hash, scopes, description) VALUES ($1, $2, $3, $4)
+`
+
+type AddServiceClientParams struct {
+	ClientID    string
+	SecretHash  string
+	Scopes      []string
+	Description string
+}
+
+func (q *Queries) AddServiceClient(ctx context.Context, arg AddServiceClientParams) error {
+	_, err := q.db.Exec(ctx, addServiceClient,
+		arg.ClientID,
+		arg.SecretHash,
+		arg.Scopes,
+		arg.Description,
+	)
+	return err
+}
+
+const findServiceClient = `-- name: FindServiceClient :one
+SELECT client_id, secret_hash, scopes, description, created_at, disabled_at
+FROM service_clients
+WHERE client_id = $1
+`
+
+func (q *Queries) FindServiceClient(ctx context.Context, clientID string) (ServiceClient, error) {
+	row := q.db.QueryRow(ctx, findServiceClient, clientID)
+	var i ServiceClient
+	err := row.Scan(
+		&i.ClientID,
+		&i.SecretHash,
+		&i.Scopes,
+		&i.Description,
+		&i.CreatedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
@@ -1,5 +1,145 @@
--- name: AddUser :one
-INSERT INTO player_credentials (login_token) VALUES ($1) RETURNING id;
+-- name: AddPlayer :one
+INSERT INTO player_credentials DEFAULT VALUES RETURNING id;
 
--- name: FindUserByLoginToken :one
-SELECT id FROM player_credentials WHERE login_token = $1;
+-- name: LockPlayer :one
+SELECT id FROM player_credentials WHERE id = $1 FOR UPDATE;
+
+-- name: DeletePlayer :exec
+DELETE FROM player_credentials WHERE id = $1;
+
+-- name: GetPlayer :one
+SELECT id, deletion_requested_at, delete_after FROM player_credentials WHERE id = $1;
+
+-- name: RequestPlayerDeletion :one
+UPDATE player_credentials
+SET deletion_requested_at = COALESCE(deletion_requested_at, NOW()),
+    delete_after          = COALESCE(delete_after, $2)
+WHERE id = $1
+RETURNING delete_after;
+
+-- name: CancelPlayerDeletion :execrows
+UPDATE player_credentials SET deletion_requested_at = NULL, delete_after = NULL
+WHERE id = $1 AND delete_after IS NOT NULL;
+
+-- name: ListPlayersDueForDeletion :many
+SELECT id FROM player_credentials WHERE delete_after <= $1 ORDER BY delete_after LIMIT $2;
+
+-- name: DeleteDuePlayer :execrows
+DELETE FROM player_credentials WHERE id = $1 AND delete_after <= $2;
+
+-- name: AddEmailCredential :exec
+INSERT INTO player_email_credentials (player_id, email, password_hash) VALUES ($1, $2, $3);
+
+-- name: FindEmailCredentialByEmail :one
+SELECT player_id, email, password_hash, verified_at FROM player_email_credentials WHERE email = $1;
+
+-- name: FindEmailCredentialByPlayer :one
+SELECT player_id, email, password_hash, verified_at FROM player_email_credentials WHERE player_id = $1;
+
+-- name: MarkEmailVerified :execrows
+UPDATE player_email_credentials SET verified_at = NOW(), updated_at = NOW()
+WHERE player_id = $1 AND verified_at IS NULL;
+
+-- name: UpdatePasswordHash :execrows
+UPDATE player_email_credentials SET password_hash = $2, updated_at = NOW() WHERE player_id = $1;
+
+-- name: AddEmailToken :exec
+INSERT INTO player_email_tokens (token_hash, player_id, purpose, expires_at) VALUES ($1, $2, $3, $4);
+
+-- name: ConsumeEmailToken :one
+DELETE FROM player_email_tokens
+WHERE token_hash = $1 AND purpose = $2 AND expires_at > NOW()
+RETURNING player_id;
+
+-- name: DeleteEmailTokens :exec
+DELETE FROM player_email_tokens WHERE player_id = $1 AND purpose = $2;
+
+-- name: DeleteEmailCredential :exec
+DELETE FROM player_email_credentials WHERE player_id = $1;
+
+-- name: AddIdentity :exec
+INSERT INTO player_identities (player_id, provider, subject) VALUES ($1, $2, $3);
+
+-- name: FindPlayerByIdentity :one
+SELECT player_id FROM player_identities WHERE provider = $1 AND subject = $2;
+
+-- name: ListIdentities :many
+SELECT provider, subject, created_at FROM player_identities WHERE player_id = $1 ORDER BY id;
+
+-- name: DeleteIdentity :execrows
+DELETE FROM player_identities WHERE player_id = $1 AND provider = $2 AND subject = $3;
+
+-- name: MoveIdentities :exec
+UPDATE player_identities SET player_id = sqlc.arg(target_player_id) WHERE player_id = sqlc.arg(source_player_id);
+
+-- name: UpsertTOTPSecret :execrows
+INSERT INTO player_totp (player_id, secret_encrypted) VALUES ($1, $2)
+ON CONFLICT (player_id) DO UPDATE
+SET secret_encrypted = EXCLUDED.secret_encrypted, last_used_step = 0, created_at = NOW()
+WHERE player_totp.confirmed_at IS NULL;
+
+-- name: GetTOTP :one
+SELECT secret_encrypted, confirmed_at, last_used_step FROM player_totp WHERE player_id = $1;
+
+-- name: ConfirmTOTP :execrows
+UPDATE player_totp SET confirmed_at = NOW() WHERE player_id = $1 AND confirmed_at IS NULL;
+
+-- name: UseTOTPStep :execrows
+UPDATE player_totp SET last_used_step = $2 WHERE player_id = $1 AND last_used_step < $2;
+
+-- name: DeleteTOTP :exec
+DELETE FROM player_totp WHERE player_id = $1;
+
+-- name: AddRecoveryCode :exec
+INSERT INTO player_recovery_codes (player_id, code_hash) VALUES ($1, $2);
+
+-- name: UseRecoveryCode :execrows
+UPDATE player_recovery_codes SET used_at = NOW()
+WHERE player_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM player_recovery_codes WHERE player_id = $1;
+
+-- name: AddBan :one
+INSERT INTO player_bans (player_id, reason, issued_by, expires_at) VALUES ($1, $2, $3, $4)
+RETURNING id, created_at;
+
+-- name: FindActiveBan :one
+SELECT id, player_id, reason, issued_by, created_at, expires_at
+FROM player_bans
+WHERE player_id = $1 AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY expires_at DESC NULLS FIRST
+LIMIT 1;
+
+-- name: ListBans :many
+SELECT id, player_id, reason, issued_by, created_at, expires_at, lifted_at
+FROM player_bans
+WHERE player_id = $1
+ORDER BY id;
+
+-- name: LiftBans :execrows
+UPDATE player_bans SET lifted_at = NOW()
+WHERE player_id = $1 AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: AddAuditEntry :exec
+INSERT INTO auth_audit_log (player_id, event, ip, user_agent, details) VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListAuditEntries :many
+SELECT id, player_id, event, ip, user_agent, details, created_at
+FROM auth_audit_log
+WHERE player_id = sqlc.arg(player_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(max_entries);
+
+-- name: AnonymizeAuditEntries :exec
+UPDATE auth_audit_log SET ip = '', user_agent = '' WHERE player_id = $1;
+
+-- name: AddServiceClient :exec
+INSERT INTO service_clients (client_id, secret_hash, scopes, description) VALUES ($1, $2, $3, $4);
+
+-- name: FindServiceClient :one
+SELECT client_id, secret_hash, scopes, description, created_at, disabled_at
+FROM service_clients
+WHERE client_id = $1;
@@ -4,9 +4,11 @@ package postgresrepo
 import (
 	"context"
 	"fmt"
+	"go-game-backend/services/auth/internal/dto"
 	"go-game-backend/services/auth/internal/repository/postgres/sqlc"
+	"go-game-backend/services/auth/internal/services"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -23,20 +25,97 @@ func NewUserRepo(pool *pgxpool.Pool) *UserRepo {
 	return &UserRepo{BaseRepo: postgresstore.NewBaseRepo(sqlc.New(pool))}
 }
 
-// AddUser inserts a new user with the specified login token and returns its ID.
-func (r *UserRepo) AddUser(ctx context.Context, loginToken uuid.UUID) (int64, error) {
-	id, err := r.Q(ctx).AddUser(ctx, pgtype.UUID{Bytes: loginToken, Valid: true})
+// AddPlayer inserts a new player and returns its ID.
+func (r *UserRepo) AddPlayer(ctx context.Context) (int64, error) {
+	id, err := r.Q(ctx).AddPlayer(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("insert user query: %w", err)
+		return 0, fmt.Errorf("insert player query: %w", classifyErr(err))
 	}
 	return id, nil
 }
 
-// FindUserByLoginToken retrieves a user ID by its login token.
-func (r *UserRepo) FindUserByLoginToken(ctx context.Context, loginToken uuid.UUID) (int64, error) {
-	id, err := r.Q(ctx).FindUserByLoginToken(ctx, pgtype.UUID{Bytes: loginToken, Valid: true})
+// LockPlayer locks the player row until the end of the transaction.
+func (r *UserRepo) LockPlayer(ctx context.Context, playerID int64) error {
+	if _, err := r.Q(ctx).LockPlayer(ctx, playerID); err != nil {
+		return fmt.Errorf("lock player query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// DeletePlayer removes the player together with all of its credentials.
+func (r *UserRepo) DeletePlayer(ctx context.Context, playerID int64) error {
+	if err := r.Q(ctx).DeletePlayer(ctx, playerID); err != nil {
+		return fmt.Errorf("delete player query: %w", classifyErr(err))
+	}
+	return nil
+}
+
+// GetPlayer retrieves the player with the given ID.
+func (r *UserRepo) GetPlayer(ctx context.Context, playerID int64) (dto.Player, error) {
+	row, err := r.Q(ctx).GetPlayer(ctx, playerID)
 	if err != nil {
-		return 0, fmt.Errorf("find user query: %w", err)
+		return dto.Player{}, fmt.Errorf("get player query: %w", classifyErr(err))
 	}
-	return id, nil
+	return dto.Player{
+		ID:                  row.ID,
+		DeletionRequestedAt: row.DeletionRequestedAt.Time,
+		DeleteAfter:         row.DeleteAfter.Time,
+	}, nil
+}
+
+// RequestDeletion schedules the player for deletion at deleteAfter and
+// returns when the player is going to be deleted. A deletion that is already
+// scheduled keeps its original time.
+func (r *UserRepo) RequestDeletion(ctx context.Context, playerID int64, deleteAfter time.Time) (time.Time, error) {
+	at, err := r.Q(ctx).RequestPlayerDeletion(ctx, sqlc.RequestPlayerDeletionParams{
+		ID:          playerID,
+		DeleteAfter: pgtype.Timestamptz{Time: deleteAfter, Valid: true},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("request player deletion query: %w", classifyErr(err))
+	}
+	return at.Time, nil
+}
+
+// CancelDeletion unschedules the deletion of the player. It returns
+// services.ErrNotFound when no deletion is scheduled.
+func (r *UserRepo) CancelDeletion(ctx context.Context, playerID int64) error {
+	rows, err := r.Q(ctx).CancelPlayerDeletion(ctx, playerID)
+	if err != nil {
+		return fmt.Errorf("cancel player deletion query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("cancel player deletion query: %w", services.ErrNotFound)
+	}
+	return nil
+}
+
+// ListPlayersDueForDeletion returns up to limit players whose deletion is
+// scheduled at or before now, the longest overdue first.
+func (r *UserRepo) ListPlayersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	ids, err := r.Q(ctx).ListPlayersDueForDeletion(ctx, sqlc.ListPlayersDueForDeletionParams{
+		DeleteAfter: pgtype.Timestamptz{Time: now, Valid: true},
+		Limit:       int32(limit), //nolint:gosec // limit comes from configuration
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list players due for deletion query: %w", classifyErr(err))
+	}
+	return ids, nil
+}
+
+// DeleteDuePlayer removes the player together with all of its credentials
+// if its deletion is scheduled at or before now. It returns
+// services.ErrNotFound when the deletion was cancelled in the meantime.
+func (r *UserRepo) DeleteDuePlayer(ctx context.Context, playerID int64, now time.Time) error {
+	rows, err := r.Q(ctx).DeleteDuePlayer(ctx, sqlc.DeleteDuePlayerParams{
+		ID:          playerID,
+		DeleteAfter: pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("delete due player query: %w", classifyErr(err))
+	}
+	if rows == 0 {
+		return fmt.Errorf("delete due player query: %w", services.ErrNotFound)
+	}
+	return nil
 }
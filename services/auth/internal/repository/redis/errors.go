@@ -0,0 +1,17 @@
+package redisrepo
+
+import (
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+
+	redisstore "go-game-backend/pkg/redis"
+)
+
+// classifyErr wraps connectivity errors with services.ErrStorageUnavailable.
+func classifyErr(err error) error {
+	if !errors.Is(err, services.ErrStorageUnavailable) && redisstore.IsUnavailable(err) {
+		return fmt.Errorf("%w: %w", services.ErrStorageUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package redisrepo
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	redisstore "go-game-backend/pkg/redis"
+)
+
+// MFAChallengeRepo stores pending logins waiting for a second factor.
+type MFAChallengeRepo struct {
+	redisstore.BaseRepo
+}
+
+// NewMFAChallengeRepo creates a new MFA challenge repository instance.
+func NewMFAChallengeRepo(defaultCmdable redis.Cmdable) *MFAChallengeRepo {
+	return &MFAChallengeRepo{
+		redisstore.NewBaseRepo(defaultCmdable),
+	}
+}
+
+// SetMFAChallenge stores a challenge for the given user under the token hash.
+func (r *MFAChallengeRepo) SetMFAChallenge(
+	ctx context.Context,
+	tokenHash string,
+	userID int64,
+	expiresAt time.Time,
+) error {
+	key := mfaChallengeKey(tokenHash)
+
+	setRes := r.Cmd(ctx).HSet(ctx, key, "user_id", userID, "attempts", 0)
+	if err := setRes.Err(); err != nil {
+		return fmt.Errorf("redis: set '%s': %w", key, classifyErr(err))
+	}
+
+	expRes := r.Cmd(ctx).ExpireAt(ctx, key, expiresAt)
+	if err := expRes.Err(); err != nil {
+		return fmt.Errorf("redis: set '%s' expiration time: %w", key, classifyErr(err))
+	}
+
+	return nil
+}
+
+// GetMFAChallenge retrieves the challenge stored under the token hash.
+func (r *MFAChallengeRepo) GetMFAChallenge(ctx context.Context, tokenHash string) (dto.MFAChallenge, error) {
+	key := mfaChallengeKey(tokenHash)
+
+	resCmd := r.Cmd(ctx).HGetAll(ctx, key)
+	if err := resCmd.Err(); err != nil {
+		return dto.MFAChallenge{}, fmt.Errorf("redis: get '%s': %w", key, classifyErr(err))
+	}
+
+	if len(resCmd.Val()) == 0 {
+		return dto.MFAChallenge{}, fmt.Errorf("redis: get '%s': %w", key, services.ErrNotFound)
+	}
+
+	var challenge dto.MFAChallenge
+	if err := resCmd.Scan(&challenge); err != nil {
+		return dto.MFAChallenge{}, fmt.Errorf("parse mfa challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// IncrMFAChallengeAttempts counts a rejected code and returns the number of
+// rejected codes so far.
+func (r *MFAChallengeRepo) IncrMFAChallengeAttempts(ctx context.Context, tokenHash string) (int64, error) {
+	key := mfaChallengeKey(tokenHash)
+
+	res := r.Cmd(ctx).HIncrBy(ctx, key, "attempts", 1)
+	if err := res.Err(); err != nil {
+		return 0, fmt.Errorf("redis: increment '%s': %w", key, classifyErr(err))
+	}
+
+	return res.Val(), nil
+}
+
+// RemoveMFAChallenge deletes the challenge stored under the token hash.
+// services.ErrNotFound is returned when the challenge no longer exists, so
+// that only one caller can complete it.
+func (r *MFAChallengeRepo) RemoveMFAChallenge(ctx context.Context, tokenHash string) error {
+	key := mfaChallengeKey(tokenHash)
+
+	res := r.Cmd(ctx).Del(ctx, key)
+	if err := res.Err(); err != nil {
+		return fmt.Errorf("redis: remove '%s': %w", key, classifyErr(err))
+	}
+	if res.Val() == 0 {
+		return fmt.Errorf("redis: remove '%s': %w", key, services.ErrNotFound)
+	}
+
+	return nil
+}
+
+func mfaChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("mfa_challenge:%s", tokenHash)
+}
@@ -8,13 +8,17 @@ import (
 
 // Repos aggregates all Redis-backed repositories used by the auth service.
 type Repos struct {
-	session authsvc.SessionRepository
+	session      authsvc.SessionRepository
+	mfaChallenge authsvc.MFAChallengeRepository
+	transferCode authsvc.TransferCodeRepository
 }
 
 // NewRepos creates Repos with initialized sub-repositories.
 func NewRepos(defaultCmdable redis.Cmdable) *Repos {
 	return &Repos{
-		session: NewSessionRepo(defaultCmdable),
+		session:      NewSessionRepo(defaultCmdable),
+		mfaChallenge: NewMFAChallengeRepo(defaultCmdable),
+		transferCode: NewTransferCodeRepo(defaultCmdable),
 	}
 }
 
@@ -22,3 +26,13 @@ func NewRepos(defaultCmdable redis.Cmdable) *Repos {
 func (r Repos) Session() authsvc.SessionRepository {
 	return r.session
 }
+
+// MFAChallenge returns repository for pending second factor challenges.
+func (r Repos) MFAChallenge() authsvc.MFAChallengeRepository {
+	return r.mfaChallenge
+}
+
+// TransferCode returns repository for device transfer codes.
+func (r Repos) TransferCode() authsvc.TransferCodeRepository {
+	return r.transferCode
+}
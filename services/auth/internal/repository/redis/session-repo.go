@@ -3,14 +3,17 @@ package redisrepo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
 	redisstore "go-game-backend/pkg/redis"
+	authredis "go-game-backend/services/auth/pkg/redis"
 )
 
 // SessionRepo implements a session repository backed by Redis.
@@ -25,18 +28,57 @@ func NewSessionRepo(defaultCmdable redis.Cmdable) *SessionRepo {
 	}
 }
 
-// SetSessionToken stores a session token for the given user with an expiration time.
-func (r *SessionRepo) SetSessionToken(ctx context.Context, userID int64, token uuid.UUID, expiresAt time.Time) error {
-	key := fmt.Sprintf("session_token:%v", userID)
+// SetSession stores a session and adds it to the sessions index of its
+// user. The session expires at session.ExpiresAtUnix.
+func (r *SessionRepo) SetSession(ctx context.Context, session dto.Session) error {
+	key := authredis.SessionKey(session.Token)
+	expiresAt := time.Unix(session.ExpiresAtUnix, 0)
 
-	setCmd := r.Cmd(ctx).Set(ctx, key, token.String(), 0)
+	setCmd := r.Cmd(ctx).HSet(ctx, key, &session)
 	if err := setCmd.Err(); err != nil {
-		return fmt.Errorf("redis: set '%s': %w", key, err)
+		return fmt.Errorf("redis: set '%s': %w", key, classifyErr(err))
 	}
 
 	expCmd := r.Cmd(ctx).ExpireAt(ctx, key, expiresAt)
 	if err := expCmd.Err(); err != nil {
-		return fmt.Errorf("redis: set '%s' expiration time: %w", key, err)
+		return fmt.Errorf("redis: set '%s' expiration time: %w", key, classifyErr(err))
+	}
+
+	// Sessions are issued with the same TTL, so the newest one always
+	// outlives the rest of the index.
+	indexKey := authredis.UserSessionsKey(session.UserID)
+	member := redis.Z{Score: float64(session.CreatedAtUnix), Member: session.Token.String()}
+	if err := r.Cmd(ctx).ZAdd(ctx, indexKey, member).Err(); err != nil {
+		return fmt.Errorf("redis: add to '%s': %w", indexKey, classifyErr(err))
+	}
+
+	if err := r.Cmd(ctx).ExpireAt(ctx, indexKey, expiresAt).Err(); err != nil {
+		return fmt.Errorf("redis: set '%s' expiration time: %w", indexKey, classifyErr(err))
+	}
+
+	return nil
+}
+
+// setExistingScript sets the given fields of the hash at KEYS[1], keeping
+// its expiration, unless the hash does not exist.
+var setExistingScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], unpack(ARGV))
+return 1
+`)
+
+// TouchSession records that the session was used from the given IP. A
+// session that expired in the meantime is left expired.
+func (r *SessionRepo) TouchSession(ctx context.Context, sessionToken uuid.UUID, ip string, at time.Time) error {
+	key := authredis.SessionKey(sessionToken)
+
+	// Eval rather than Run, as an unknown script cannot be loaded within a
+	// transaction.
+	res := setExistingScript.Eval(ctx, r.Cmd(ctx), []string{key}, "ip", ip, "last_used_at", at.Unix())
+	if err := res.Err(); err != nil {
+		return fmt.Errorf("redis: set '%s': %w", key, classifyErr(err))
 	}
 
 	return nil
@@ -55,24 +97,157 @@ func (r *SessionRepo) SetRefreshToken(
 	userID := sessionInfo.UserID
 	setRes := r.Cmd(ctx).HSet(ctx, key, "session_token", sessionToken, "user_id", userID)
 	if err := setRes.Err(); err != nil {
-		return fmt.Errorf("redis: set '%s': %w", key, err)
+		return fmt.Errorf("redis: set '%s': %w", key, classifyErr(err))
 	}
 
 	expRes := r.Cmd(ctx).ExpireAt(ctx, key, expiresAt)
 	if err := expRes.Err(); err != nil {
-		return fmt.Errorf("redis: set '%s' expiration time: %w", key, err)
+		return fmt.Errorf("redis: set '%s' expiration time: %w", key, classifyErr(err))
+	}
+
+	// Refresh tokens are issued with the same TTL, so the newest one always
+	// outlives the rest of the indexes.
+	userIndexKey := fmt.Sprintf("user_refresh_tokens:%v", userID)
+	if err := r.addToIndex(ctx, userIndexKey, token, expiresAt); err != nil {
+		return err
+	}
+
+	sessionIndexKey := fmt.Sprintf("session_refresh_tokens:%s", sessionToken)
+	if err := r.addToIndex(ctx, sessionIndexKey, token, expiresAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkRefreshTokenRotated flags a refresh token as exchanged for a new one.
+// The token is kept until it expires so that a later attempt to use it again
+// can be detected. A token that expired in the meantime is left expired.
+func (r *SessionRepo) MarkRefreshTokenRotated(ctx context.Context, token uuid.UUID) error {
+	key := fmt.Sprintf("refresh_token:%s", token)
+
+	res := setExistingScript.Eval(ctx, r.Cmd(ctx), []string{key}, "rotated", true)
+	if err := res.Err(); err != nil {
+		return fmt.Errorf("redis: set '%s': %w", key, classifyErr(err))
 	}
 
 	return nil
 }
 
-// RemoveRefreshToken deletes a refresh token from the store.
-func (r *SessionRepo) RemoveRefreshToken(ctx context.Context, token uuid.UUID) error {
+// RemoveRefreshToken deletes a refresh token of the given user from the store.
+func (r *SessionRepo) RemoveRefreshToken(ctx context.Context, userID int64, token uuid.UUID) error {
 	key := fmt.Sprintf("refresh_token:%s", token)
 
 	res := r.Cmd(ctx).Del(ctx, key)
 	if err := res.Err(); err != nil {
-		return fmt.Errorf("redis: remove '%s': %w", key, err)
+		return fmt.Errorf("redis: remove '%s': %w", key, classifyErr(err))
+	}
+
+	indexKey := fmt.Sprintf("user_refresh_tokens:%v", userID)
+
+	remRes := r.Cmd(ctx).SRem(ctx, indexKey, token.String())
+	if err := remRes.Err(); err != nil {
+		return fmt.Errorf("redis: remove from '%s': %w", indexKey, classifyErr(err))
+	}
+
+	return nil
+}
+
+// GetUserRefreshTokens returns all refresh tokens issued to the given user
+// that have not been removed yet.
+func (r *SessionRepo) GetUserRefreshTokens(ctx context.Context, userID int64) ([]uuid.UUID, error) {
+	return r.getIndex(ctx, fmt.Sprintf("user_refresh_tokens:%v", userID))
+}
+
+// GetSessionRefreshTokens returns the refresh token family of the given
+// session, including tokens that were already rotated.
+func (r *SessionRepo) GetSessionRefreshTokens(ctx context.Context, sessionToken uuid.UUID) ([]uuid.UUID, error) {
+	return r.getIndex(ctx, fmt.Sprintf("session_refresh_tokens:%s", sessionToken))
+}
+
+// RemoveSessionRefreshTokens deletes the refresh token family index of the
+// given session.
+func (r *SessionRepo) RemoveSessionRefreshTokens(ctx context.Context, sessionToken uuid.UUID) error {
+	key := fmt.Sprintf("session_refresh_tokens:%s", sessionToken)
+
+	res := r.Cmd(ctx).Del(ctx, key)
+	if err := res.Err(); err != nil {
+		return fmt.Errorf("redis: remove '%s': %w", key, classifyErr(err))
+	}
+
+	return nil
+}
+
+// GetSession retrieves the session with the given token.
+func (r *SessionRepo) GetSession(ctx context.Context, sessionToken uuid.UUID) (dto.Session, error) {
+	key := authredis.SessionKey(sessionToken)
+
+	resCmd := r.Cmd(ctx).HGetAll(ctx, key)
+	if err := resCmd.Err(); err != nil {
+		return dto.Session{}, fmt.Errorf("redis: get '%s': %w", key, classifyErr(err))
+	}
+
+	if len(resCmd.Val()) == 0 {
+		return dto.Session{}, fmt.Errorf("redis: get '%s': %w", key, services.ErrNotFound)
+	}
+
+	session := dto.Session{Token: sessionToken}
+	if err := resCmd.Scan(&session); err != nil {
+		return dto.Session{}, fmt.Errorf("parse session: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListSessions returns the sessions of the given user, oldest first. Index
+// entries of sessions that have expired are dropped. It must not be called
+// within a transaction.
+func (r *SessionRepo) ListSessions(ctx context.Context, userID int64) ([]dto.Session, error) {
+	indexKey := authredis.UserSessionsKey(userID)
+
+	res := r.Cmd(ctx).ZRange(ctx, indexKey, 0, -1)
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("redis: get '%s': %w", indexKey, classifyErr(err))
+	}
+
+	sessions := make([]dto.Session, 0, len(res.Val()))
+	for _, v := range res.Val() {
+		token, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("parse token '%s' from '%s': %w", v, indexKey, err)
+		}
+
+		session, err := r.GetSession(ctx, token)
+		if errors.Is(err, services.ErrNotFound) {
+			if err := r.Cmd(ctx).ZRem(ctx, indexKey, v).Err(); err != nil {
+				return nil, fmt.Errorf("redis: remove from '%s': %w", indexKey, classifyErr(err))
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// RemoveSession deletes the session and removes it from the sessions index
+// of the given user.
+func (r *SessionRepo) RemoveSession(ctx context.Context, userID int64, sessionToken uuid.UUID) error {
+	key := authredis.SessionKey(sessionToken)
+
+	res := r.Cmd(ctx).Del(ctx, key)
+	if err := res.Err(); err != nil {
+		return fmt.Errorf("redis: remove '%s': %w", key, classifyErr(err))
+	}
+
+	indexKey := authredis.UserSessionsKey(userID)
+
+	remRes := r.Cmd(ctx).ZRem(ctx, indexKey, sessionToken.String())
+	if err := remRes.Err(); err != nil {
+		return fmt.Errorf("redis: remove from '%s': %w", indexKey, classifyErr(err))
 	}
 
 	return nil
@@ -84,7 +259,11 @@ func (r *SessionRepo) GetSessionInfo(ctx context.Context, refreshToken uuid.UUID
 
 	resCmd := r.Cmd(ctx).HGetAll(ctx, key)
 	if err := resCmd.Err(); err != nil {
-		return dto.SessionInfo{}, fmt.Errorf("redis: get '%s': %w", key, err)
+		return dto.SessionInfo{}, fmt.Errorf("redis: get '%s': %w", key, classifyErr(err))
+	}
+
+	if len(resCmd.Val()) == 0 {
+		return dto.SessionInfo{}, fmt.Errorf("redis: get '%s': %w", key, services.ErrNotFound)
 	}
 
 	var sessionInfo dto.SessionInfo
@@ -95,3 +274,35 @@ func (r *SessionRepo) GetSessionInfo(ctx context.Context, refreshToken uuid.UUID
 
 	return sessionInfo, nil
 }
+
+func (r *SessionRepo) addToIndex(ctx context.Context, key string, token uuid.UUID, expiresAt time.Time) error {
+	addRes := r.Cmd(ctx).SAdd(ctx, key, token.String())
+	if err := addRes.Err(); err != nil {
+		return fmt.Errorf("redis: add to '%s': %w", key, classifyErr(err))
+	}
+
+	expRes := r.Cmd(ctx).ExpireAt(ctx, key, expiresAt)
+	if err := expRes.Err(); err != nil {
+		return fmt.Errorf("redis: set '%s' expiration time: %w", key, classifyErr(err))
+	}
+
+	return nil
+}
+
+func (r *SessionRepo) getIndex(ctx context.Context, key string) ([]uuid.UUID, error) {
+	res := r.Cmd(ctx).SMembers(ctx, key)
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("redis: get '%s': %w", key, classifyErr(err))
+	}
+
+	tokens := make([]uuid.UUID, 0, len(res.Val()))
+	for _, v := range res.Val() {
+		token, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("parse token '%s' from '%s': %w", v, key, err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
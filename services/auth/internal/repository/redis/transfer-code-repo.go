@@ -0,0 +1,123 @@
+package redisrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	redisstore "go-game-backend/pkg/redis"
+)
+
+// TransferCodeRepo stores the codes players generate to move their account
+// to another device.
+type TransferCodeRepo struct {
+	redisstore.BaseRepo
+}
+
+// NewTransferCodeRepo creates a new transfer code repository instance.
+func NewTransferCodeRepo(defaultCmdable redis.Cmdable) *TransferCodeRepo {
+	return &TransferCodeRepo{
+		redisstore.NewBaseRepo(defaultCmdable),
+	}
+}
+
+// SetTransferCode stores a code of the user under its hash and remembers it
+// as the current code of the user.
+func (r *TransferCodeRepo) SetTransferCode(
+	ctx context.Context,
+	codeHash string,
+	userID int64,
+	expiresAt time.Time,
+) error {
+	ttl := time.Until(expiresAt)
+
+	codeKey := transferCodeKey(codeHash)
+	if err := r.Cmd(ctx).Set(ctx, codeKey, userID, ttl).Err(); err != nil {
+		return fmt.Errorf("redis: set '%s': %w", codeKey, classifyErr(err))
+	}
+
+	userKey := userTransferCodeKey(userID)
+	if err := r.Cmd(ctx).Set(ctx, userKey, codeHash, ttl).Err(); err != nil {
+		return fmt.Errorf("redis: set '%s': %w", userKey, classifyErr(err))
+	}
+
+	return nil
+}
+
+// GetTransferCode returns the user the code stored under the hash belongs
+// to, leaving the code in place.
+func (r *TransferCodeRepo) GetTransferCode(ctx context.Context, codeHash string) (int64, error) {
+	key := transferCodeKey(codeHash)
+
+	res := r.Cmd(ctx).Get(ctx, key)
+	if errors.Is(res.Err(), redis.Nil) {
+		return 0, fmt.Errorf("redis: get '%s': %w", key, services.ErrNotFound)
+	}
+	if err := res.Err(); err != nil {
+		return 0, fmt.Errorf("redis: get '%s': %w", key, classifyErr(err))
+	}
+
+	userID, err := res.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("parse user id: %w", err)
+	}
+	return userID, nil
+}
+
+// GetUserTransferCode returns the hash of the current code of the user.
+func (r *TransferCodeRepo) GetUserTransferCode(ctx context.Context, userID int64) (string, error) {
+	key := userTransferCodeKey(userID)
+
+	res := r.Cmd(ctx).Get(ctx, key)
+	if errors.Is(res.Err(), redis.Nil) {
+		return "", fmt.Errorf("redis: get '%s': %w", key, services.ErrNotFound)
+	}
+	if err := res.Err(); err != nil {
+		return "", fmt.Errorf("redis: get '%s': %w", key, classifyErr(err))
+	}
+
+	return res.Val(), nil
+}
+
+// ConsumeTransferCode deletes the code stored under the hash and returns the
+// user it belongs to. services.ErrNotFound is returned when the code does not
+// exist, so that a code can be redeemed only once.
+func (r *TransferCodeRepo) ConsumeTransferCode(ctx context.Context, codeHash string) (int64, error) {
+	key := transferCodeKey(codeHash)
+
+	res := r.Cmd(ctx).GetDel(ctx, key)
+	if errors.Is(res.Err(), redis.Nil) {
+		return 0, fmt.Errorf("redis: getdel '%s': %w", key, services.ErrNotFound)
+	}
+	if err := res.Err(); err != nil {
+		return 0, fmt.Errorf("redis: getdel '%s': %w", key, classifyErr(err))
+	}
+
+	userID, err := res.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("parse user id: %w", err)
+	}
+	return userID, nil
+}
+
+// RemoveTransferCode deletes the code stored under the hash together with
+// the reference to it kept for the user.
+func (r *TransferCodeRepo) RemoveTransferCode(ctx context.Context, userID int64, codeHash string) error {
+	res := r.Cmd(ctx).Del(ctx, transferCodeKey(codeHash), userTransferCodeKey(userID))
+	if err := res.Err(); err != nil {
+		return fmt.Errorf("redis: remove transfer code of user %d: %w", userID, classifyErr(err))
+	}
+	return nil
+}
+
+func transferCodeKey(codeHash string) string {
+	return fmt.Sprintf("transfer_code:%s", codeHash)
+}
+
+func userTransferCodeKey(userID int64) string {
+	return fmt.Sprintf("user_transfer_code:%d", userID)
+}
@@ -0,0 +1,245 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"time"
+)
+
+// exportAuditLogLimit bounds the audit entries included in an account
+// export.
+const exportAuditLogLimit = 100000
+
+// RequestDeletion schedules the account of the user for deletion once the
+// grace period passes and revokes every session of the user. Signing in
+// again within the grace period cancels the deletion. Requesting a deletion
+// that is already scheduled keeps its original time.
+func (l *Service) RequestDeletion(ctx context.Context, userID int64) (*models.AccountDeletionResponse, error) {
+	deleteAfter := time.Now().UTC().Add(l.cfg.DeletionGracePeriod)
+
+	err := l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+			var err error
+			deleteAfter, err = r.User().RequestDeletion(ctx, userID, deleteAfter)
+			if errors.Is(err, services.ErrNotFound) {
+				return fmt.Errorf("request deletion: %w", services.ErrPlayerNotFound)
+			}
+			if err != nil {
+				return fmt.Errorf("request deletion: %w", err)
+			}
+
+			return l.audit(ctx, r, models.AuditEventDeletionRequested, userID, map[string]any{
+				"delete_after": deleteAfter.Unix(),
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("pg transaction: %w", err)
+		}
+
+		return l.revokeAllSessions(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AccountDeletionResponse{DeleteAfterUnix: deleteAfter.Unix()}, nil
+}
+
+// cancelDeletion unschedules a pending deletion of the user's account. Must
+// be called while holding the player lock.
+func (l *Service) cancelDeletion(ctx context.Context, userID int64) error {
+	err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		if err := r.User().CancelDeletion(ctx, userID); err != nil {
+			return fmt.Errorf("cancel deletion: %w", err)
+		}
+		return l.audit(ctx, r, models.AuditEventDeletionCancelled, userID, nil)
+	})
+	if errors.Is(err, services.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+	return nil
+}
+
+// PurgeDeletedPlayers deletes for good up to limit players whose deletion
+// grace period has passed, and returns how many were deleted. Players
+// currently locked are left for the next run.
+func (l *Service) PurgeDeletedPlayers(ctx context.Context, limit int) (int, error) {
+	ids, err := l.pgStore.Raw().User().ListPlayersDueForDeletion(ctx, time.Now().UTC(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("list players due for deletion: %w", err)
+	}
+
+	purged := 0
+	for _, userID := range ids {
+		var deleted bool
+		err := l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+			var err error
+			deleted, err = l.purgePlayer(ctx, userID)
+			return err
+		})
+		if errors.Is(err, services.ErrPlayerLocked) {
+			continue
+		}
+		if err != nil {
+			return purged, fmt.Errorf("purge player %d: %w", userID, err)
+		}
+		if deleted {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purgePlayer deletes the player with every credential and its outbox
+// events, which may carry its email addresses, anonymizes its audit entries
+// and tells other services to purge their data. It reports false
+// when the deletion was cancelled in the meantime. Must be called while
+// holding the player lock.
+func (l *Service) purgePlayer(ctx context.Context, userID int64) (bool, error) {
+	now := time.Now().UTC()
+
+	err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		if err := r.User().DeleteDuePlayer(ctx, userID, now); err != nil {
+			return fmt.Errorf("delete due player: %w", err)
+		}
+
+		if err := r.Audit().AnonymizeAuditEntries(ctx, userID); err != nil {
+			return fmt.Errorf("anonymize audit entries: %w", err)
+		}
+
+		if err := r.Outbox().DeleteByKey(ctx, eventKey(userID)); err != nil {
+			return fmt.Errorf("delete outbox events: %w", err)
+		}
+
+		ev := models.UserDeletedEvent{
+			UserID:        userID,
+			DeletedAtUnix: now.Unix(),
+		}
+		if err := l.addEvent(ctx, r, l.cfg.UserDeletedTopic, models.EventTypeUserDeleted, userID, ev); err != nil {
+			return err
+		}
+
+		return l.audit(ctx, r, models.AuditEventPlayerDeleted, userID, nil)
+	})
+	if errors.Is(err, services.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("pg transaction: %w", err)
+	}
+
+	// Sessions were revoked on request, but one may have been started by a
+	// sign-in racing the request.
+	if err := l.revokeAllSessions(ctx, userID); err != nil {
+		return true, fmt.Errorf("revoke all sessions: %w", err)
+	}
+	return true, nil
+}
+
+// ExportAccount gathers everything the auth service holds about the user.
+func (l *Service) ExportAccount(ctx context.Context, userID int64) (*models.AccountExport, error) {
+	now := time.Now().UTC()
+	pg := l.pgStore.Raw()
+
+	player, err := pg.User().GetPlayer(ctx, userID)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, fmt.Errorf("get player: %w", services.ErrPlayerNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get player: %w", err)
+	}
+
+	export := &models.AccountExport{
+		UserID:         userID,
+		ExportedAtUnix: now.Unix(),
+	}
+	if !player.DeleteAfter.IsZero() {
+		export.DeleteAfterUnix = player.DeleteAfter.Unix()
+	}
+
+	identities, err := l.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export.Identities = identities.Identities
+
+	export.Email, err = l.exportEmail(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.TOTPEnabled, err = l.totpEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bans, err := pg.Ban().ListBans(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list bans: %w", err)
+	}
+	export.Bans = make([]models.ExportedBan, 0, len(bans))
+	for _, ban := range bans {
+		export.Bans = append(export.Bans, models.ExportedBan{
+			ID:            ban.ID,
+			Reason:        ban.Reason,
+			CreatedAtUnix: ban.CreatedAt.Unix(),
+			ExpiresAtUnix: unixOrZero(ban.ExpiresAt),
+			LiftedAtUnix:  unixOrZero(ban.LiftedAt),
+		})
+	}
+
+	export.Sessions, err = l.GetSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLog, err := l.ListAuditLog(ctx, &models.AuditLogRequest{
+		UserID: userID,
+		ToUnix: now.Unix() + 1,
+		Limit:  exportAuditLogLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	export.AuditLog = auditLog.Entries
+
+	return export, nil
+}
+
+// exportEmail returns the email credential of the user, or nil when the user
+// has none.
+func (l *Service) exportEmail(ctx context.Context, userID int64) (*models.ExportedEmail, error) {
+	cred, err := l.pgStore.Raw().Email().FindEmailCredentialByPlayer(ctx, userID)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find email credential: %w", err)
+	}
+	return &models.ExportedEmail{Email: cred.Email, Verified: cred.Verified}, nil
+}
+
+// totpEnabled reports whether the user confirmed a TOTP second factor.
+func (l *Service) totpEnabled(ctx context.Context, userID int64) (bool, error) {
+	t, err := l.pgStore.Raw().MFA().GetTOTP(ctx, userID)
+	if errors.Is(err, services.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get totp: %w", err)
+	}
+	return t.Confirmed, nil
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
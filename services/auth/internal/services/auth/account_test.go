@@ -0,0 +1,92 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"go-game-backend/pkg/outbox"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"slices"
+	"testing"
+	"time"
+)
+
+// dueForDeletion requests the deletion of the player and moves it past the
+// grace period.
+func (e *testEnv) dueForDeletion(t *testing.T, userID int64) {
+	t.Helper()
+	if _, err := e.svc.RequestDeletion(context.Background(), userID); err != nil {
+		t.Fatalf("request deletion: %v", err)
+	}
+	e.pg.mu.Lock()
+	defer e.pg.mu.Unlock()
+	player := e.pg.data.players[userID]
+	player.DeleteAfter = time.Now().UTC().Add(-time.Minute)
+	e.pg.data.players[userID] = player
+}
+
+func TestPurgeDeletedPlayers(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	userID, _ := env.register(t)
+	otherID, _ := env.register(t)
+	if err := env.pg.AddEmailCredential(ctx, userID, "player@example.com", "plain:secret"); err != nil {
+		t.Fatalf("add email credential: %v", err)
+	}
+	if err := env.pg.AddIdentity(ctx, userID, "google", "subject"); err != nil {
+		t.Fatalf("add identity: %v", err)
+	}
+	env.enableMFA(t, userID)
+	err := env.pg.AddJSON(ctx, "maintenance", "", map[string]any{}, outbox.Headers{outbox.HeaderEventType: "maintenance"})
+	if err != nil {
+		t.Fatalf("add unkeyed event: %v", err)
+	}
+	env.dueForDeletion(t, userID)
+
+	purged, err := env.svc.PurgeDeletedPlayers(ctx, 10)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("purged = %d, want 1", purged)
+	}
+
+	if _, err := env.pg.GetPlayer(ctx, userID); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("get purged player: %v, want %v", err, services.ErrNotFound)
+	}
+	if _, err := env.pg.FindEmailCredentialByPlayer(ctx, userID); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("find email credential: %v, want %v", err, services.ErrNotFound)
+	}
+	if ids, _ := env.pg.ListIdentities(ctx, userID); len(ids) > 0 {
+		t.Errorf("identities = %v, want none", ids)
+	}
+	if _, err := env.pg.GetTOTP(ctx, userID); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("get totp: %v, want %v", err, services.ErrNotFound)
+	}
+	if codes := env.pg.data.recoveryCodes[userID]; len(codes) > 0 {
+		t.Errorf("recovery codes = %d, want none", len(codes))
+	}
+	if _, err := env.pg.GetPlayer(ctx, otherID); err != nil {
+		t.Errorf("get other player: %v", err)
+	}
+	if purged, err := env.svc.PurgeDeletedPlayers(ctx, 10); err != nil || purged != 0 {
+		t.Errorf("purge again = %d, %v, want nothing purged", purged, err)
+	}
+	if n := len(env.pg.eventsOfType(models.EventTypeUserDeleted)); n != 1 {
+		t.Errorf("user-deleted events = %d, want 1", n)
+	}
+
+	got := env.pg.eventTypesOfKey(eventKey(userID))
+	if want := []string{models.EventTypeUserDeleted}; !slices.Equal(got, want) {
+		t.Errorf("events of the purged player = %v, want %v", got, want)
+	}
+	got = env.pg.eventTypesOfKey(eventKey(otherID))
+	if want := []string{models.EventTypeUserCreated}; !slices.Equal(got, want) {
+		t.Errorf("events of another player = %v, want %v", got, want)
+	}
+	got = env.pg.eventTypesOfKey("")
+	if want := []string{"maintenance"}; !slices.Equal(got, want) {
+		t.Errorf("events without a key = %v, want %v", got, want)
+	}
+}
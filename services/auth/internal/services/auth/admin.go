@@ -0,0 +1,136 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// GetPlayerDetails returns what support needs to know about the player: its
+// credentials, second factor, ban and active sessions. The lookup is recorded
+// in the audit log.
+func (l *Service) GetPlayerDetails(ctx context.Context, userID int64) (*models.AdminPlayer, error) {
+	player, err := l.pgStore.Raw().User().GetPlayer(ctx, userID)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, fmt.Errorf("get player: %w", services.ErrPlayerNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get player: %w", err)
+	}
+
+	resp := &models.AdminPlayer{
+		UserID:                  userID,
+		DeletionRequestedAtUnix: unixOrZero(player.DeletionRequestedAt),
+		DeleteAfterUnix:         unixOrZero(player.DeleteAfter),
+	}
+
+	identities, err := l.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Identities = identities.Identities
+
+	resp.Email, err = l.exportEmail(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.TOTPEnabled, err = l.totpEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ban, err := l.pgStore.Raw().Ban().FindActiveBan(ctx, userID)
+	switch {
+	case err == nil:
+		resp.ActiveBan = &models.ExportedBan{
+			ID:            ban.ID,
+			Reason:        ban.Reason,
+			CreatedAtUnix: ban.CreatedAt.Unix(),
+			ExpiresAtUnix: unixOrZero(ban.ExpiresAt),
+		}
+	case !errors.Is(err, services.ErrNotFound):
+		return nil, fmt.Errorf("find active ban: %w", err)
+	}
+
+	resp.Sessions, err = l.GetSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.audit(ctx, l.pgStore.Raw(), models.AuditEventPlayerViewed, userID, nil); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FindPlayerByLoginToken is GetPlayerDetails for the player owning the guest
+// login token.
+func (l *Service) FindPlayerByLoginToken(ctx context.Context, loginToken uuid.UUID) (*models.AdminPlayer, error) {
+	userID, err := l.pgStore.Raw().Identity().FindPlayerByIdentity(
+		ctx,
+		models.IdentityProviderGuest,
+		loginToken.String(),
+	)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, fmt.Errorf("find player by identity: %w", services.ErrPlayerNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find player by identity: %w", err)
+	}
+
+	return l.GetPlayerDetails(ctx, userID)
+}
+
+// RevokePlayerSessions ends every session of the player on behalf of
+// support.
+func (l *Service) RevokePlayerSessions(ctx context.Context, userID int64) error {
+	if err := l.RevokeAllSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	l.auditCompleted(ctx, models.AuditEventSessionsRevoked, userID, nil)
+	return nil
+}
+
+// ReissueLoginToken replaces the guest login tokens of the player with a new
+// one, e.g. for a player who lost the device holding the old token. Every
+// session of the player is revoked, since they may have been started with a
+// token that is no longer in the player's hands.
+func (l *Service) ReissueLoginToken(ctx context.Context, userID int64) (*models.LoginTokenResponse, error) {
+	loginToken := uuid.New()
+
+	err := l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+			err := r.User().LockPlayer(ctx, userID)
+			if errors.Is(err, services.ErrNotFound) {
+				return fmt.Errorf("lock player: %w", services.ErrPlayerNotFound)
+			}
+			if err != nil {
+				return fmt.Errorf("lock player: %w", err)
+			}
+
+			replaced, err := l.replaceLoginTokens(ctx, r, userID, loginToken)
+			if err != nil {
+				return err
+			}
+			return l.audit(ctx, r, models.AuditEventLoginTokenReissued, userID, map[string]any{
+				"replaced": replaced,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("pg transaction: %w", err)
+		}
+
+		return l.revokeAllSessions(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginTokenResponse{LoginToken: loginToken}, nil
+}
@@ -0,0 +1,157 @@
+package authsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/clientinfo"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reasons recorded with failed logins.
+const (
+	loginFailureUnknownIdentity = "unknown_identity"
+	loginFailureWrongPassword   = "wrong_password"
+	loginFailureEmailUnverified = "email_not_verified"
+	loginFailureInvalidIDToken  = "invalid_id_token"
+	loginFailureInvalidMFACode  = "invalid_mfa_code"
+	loginFailureBanned          = "banned"
+)
+
+const defaultAuditLogLimit = 100
+
+// ListAuditLog returns the audit entries of the player within the requested
+// time range, newest first.
+func (l *Service) ListAuditLog(ctx context.Context, req *models.AuditLogRequest) (*models.AuditLogResponse, error) {
+	from := time.Unix(req.FromUnix, 0).UTC()
+	to := time.Now().UTC()
+	if req.ToUnix != 0 {
+		to = time.Unix(req.ToUnix, 0).UTC()
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAuditLogLimit
+	}
+
+	entries, err := l.pgStore.Raw().Audit().ListAuditEntries(ctx, req.UserID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+
+	resp := &models.AuditLogResponse{
+		Entries: make([]models.AuditEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, models.AuditEntry{
+			ID:            entry.ID,
+			UserID:        entry.PlayerID,
+			Event:         entry.Event,
+			IP:            entry.IP,
+			UserAgent:     entry.UserAgent,
+			Details:       entry.Details,
+			CreatedAtUnix: entry.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// audit appends an entry for the request in ctx to the audit log. Changes
+// made within a pg transaction pass its repositories so that the entry is
+// committed together with the change; other actions pass l.pgStore.Raw().
+// Actions taken through the admin API record who took them.
+func (l *Service) audit(
+	ctx context.Context,
+	r PostgresRepos,
+	event string,
+	userID int64,
+	details map[string]any,
+) error {
+	info := clientinfo.FromContext(ctx)
+	entry := dto.AuditEntry{
+		PlayerID:  userID,
+		Event:     event,
+		IP:        info.IP,
+		UserAgent: info.UserAgent,
+	}
+	if info.Actor != "" {
+		d := map[string]any{"actor": info.Actor}
+		if info.Operator != "" {
+			d["operator"] = info.Operator
+		}
+		for k, v := range details {
+			d[k] = v
+		}
+		details = d
+	}
+	if details != nil {
+		b, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("marshal audit details: %w", err)
+		}
+		entry.Details = b
+	}
+
+	if err := r.Audit().AddAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("add audit entry: %w", err)
+	}
+	return nil
+}
+
+// auditCompleted records an event of an operation that already took effect
+// outside of PostgreSQL, e.g. a session change in Redis, so that the entry
+// cannot share its transaction. Failing to record it is logged rather than
+// turning the completed operation into an error.
+func (l *Service) auditCompleted(ctx context.Context, event string, userID int64, details map[string]any) {
+	if err := l.audit(ctx, l.pgStore.Raw(), event, userID, details); err != nil {
+		l.logger.ErrorCtx(ctx, "record audit entry",
+			zap.String("event", event),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+	}
+}
+
+// auditLoginFailure records a rejected login attempt and returns cause. A
+// zero userID records an attempt with credentials of no known player.
+func (l *Service) auditLoginFailure(
+	ctx context.Context,
+	userID int64,
+	reason string,
+	details map[string]any,
+	cause error,
+) error {
+	d := map[string]any{"reason": reason}
+	for k, v := range details {
+		d[k] = v
+	}
+
+	if err := l.audit(ctx, l.pgStore.Raw(), models.AuditEventLoginFailed, userID, d); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// startLoginSession starts the session of a user who passed every factor and
+// records the outcome of the login.
+func (l *Service) startLoginSession(
+	ctx context.Context,
+	userID int64,
+	details map[string]any,
+) (*models.LoginRespose, error) {
+	resp, err := l.startSessionWithPlayerLock(ctx, userID)
+	if errors.Is(err, services.ErrPlayerBanned) {
+		return nil, l.auditLoginFailure(ctx, userID, loginFailureBanned, details, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("start session with player lock: %w", err)
+	}
+
+	l.auditCompleted(ctx, models.AuditEventLoginSucceeded, userID, details)
+	return resp, nil
+}
@@ -0,0 +1,119 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"time"
+)
+
+// BanPlayer blocks the player from signing in until the ban expires, or for
+// good when no expiration is given. Every session of the player is revoked
+// right away.
+func (l *Service) BanPlayer(ctx context.Context, req *models.BanPlayerRequest) (*models.BanPlayerResponse, error) {
+	ban := dto.Ban{
+		PlayerID: req.UserID,
+		Reason:   req.Reason,
+		IssuedBy: req.IssuedBy,
+	}
+	if req.ExpiresAtUnix != 0 {
+		ban.ExpiresAt = time.Unix(req.ExpiresAtUnix, 0).UTC()
+		if !ban.ExpiresAt.After(time.Now()) {
+			return nil, services.ErrBanExpired
+		}
+	}
+
+	// Holding the player lock keeps a concurrent login from starting a
+	// session between storing the ban and revoking the sessions.
+	err := l.doWithPlayerLock(ctx, req.UserID, func(ctx context.Context) error {
+		err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+			err := r.User().LockPlayer(ctx, req.UserID)
+			if errors.Is(err, services.ErrNotFound) {
+				return fmt.Errorf("lock player: %w", services.ErrPlayerNotFound)
+			}
+			if err != nil {
+				return fmt.Errorf("lock player: %w", err)
+			}
+
+			ban, err = r.Ban().AddBan(ctx, ban)
+			if err != nil {
+				return fmt.Errorf("add ban: %w", err)
+			}
+
+			ev := models.PlayerBannedEvent{
+				UserID:        ban.PlayerID,
+				Reason:        ban.Reason,
+				IssuedBy:      ban.IssuedBy,
+				BannedAtUnix:  ban.CreatedAt.Unix(),
+				ExpiresAtUnix: req.ExpiresAtUnix,
+			}
+			if err := l.addEvent(ctx, r, l.cfg.PlayerBannedTopic, models.EventTypePlayerBanned, ban.PlayerID, ev); err != nil {
+				return err
+			}
+			return l.audit(ctx, r, models.AuditEventPlayerBanned, ban.PlayerID, map[string]any{
+				"ban_id":     ban.ID,
+				"reason":     ban.Reason,
+				"issued_by":  ban.IssuedBy,
+				"expires_at": req.ExpiresAtUnix,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("pg transaction: %w", err)
+		}
+
+		return l.revokeAllSessions(ctx, req.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BanPlayerResponse{BanID: ban.ID}, nil
+}
+
+// UnbanPlayer lifts every ban of the player that is in effect.
+func (l *Service) UnbanPlayer(ctx context.Context, userID int64) error {
+	return l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+			err := r.User().LockPlayer(ctx, userID)
+			if errors.Is(err, services.ErrNotFound) {
+				return fmt.Errorf("lock player: %w", services.ErrPlayerNotFound)
+			}
+			if err != nil {
+				return fmt.Errorf("lock player: %w", err)
+			}
+
+			err = r.Ban().LiftBans(ctx, userID)
+			if errors.Is(err, services.ErrNotFound) {
+				return fmt.Errorf("lift bans: %w", services.ErrPlayerNotBanned)
+			}
+			if err != nil {
+				return fmt.Errorf("lift bans: %w", err)
+			}
+			return l.audit(ctx, r, models.AuditEventPlayerUnbanned, userID, nil)
+		})
+		if err != nil {
+			return fmt.Errorf("pg transaction: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkNotBanned returns services.ErrPlayerBanned when a ban of the user is
+// in effect.
+func (l *Service) checkNotBanned(ctx context.Context, userID int64) error {
+	ban, err := l.pgStore.Raw().Ban().FindActiveBan(ctx, userID)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("find active ban: %w", err)
+	}
+
+	if ban.ExpiresAt.IsZero() {
+		return fmt.Errorf("%w permanently", services.ErrPlayerBanned)
+	}
+	return fmt.Errorf("%w until %s", services.ErrPlayerBanned, ban.ExpiresAt.Format(time.RFC3339))
+}
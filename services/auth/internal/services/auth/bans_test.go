@@ -0,0 +1,139 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// ban bans the player permanently.
+func (e *testEnv) ban(t *testing.T, userID int64) {
+	t.Helper()
+	_, err := e.svc.BanPlayer(context.Background(), &models.BanPlayerRequest{
+		UserID:   userID,
+		Reason:   "cheating",
+		IssuedBy: "moderator",
+	})
+	if err != nil {
+		t.Fatalf("ban: %v", err)
+	}
+}
+
+func TestBannedPlayerIsRefused(t *testing.T) {
+	tests := []struct {
+		name string
+		do   func(env *testEnv, loginToken, refreshToken uuid.UUID) error
+	}{
+		{
+			name: "login",
+			do: func(env *testEnv, loginToken, _ uuid.UUID) error {
+				_, err := env.svc.Login(context.Background(), &models.LoginRequest{LoginToken: loginToken})
+				return err
+			},
+		},
+		{
+			name: "register with the taken login token",
+			do: func(env *testEnv, loginToken, _ uuid.UUID) error {
+				_, err := env.svc.Register(context.Background(), &models.RegisterRequest{LoginToken: loginToken})
+				return err
+			},
+		},
+		{
+			name: "refresh",
+			do: func(env *testEnv, _, refreshToken uuid.UUID) error {
+				_, err := env.svc.RefreshToken(context.Background(), &models.RefreshTokenRequest{RefreshToken: refreshToken})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			loginToken := uuid.New()
+			resp, err := env.svc.Register(ctx, &models.RegisterRequest{LoginToken: loginToken})
+			if err != nil {
+				t.Fatalf("register: %v", err)
+			}
+			userID := env.userOf(t, resp)
+			// the ban is stored as it is while BanPlayer revokes the sessions,
+			// so that the refresh token is still valid
+			if _, err := env.pg.AddBan(ctx, dto.Ban{PlayerID: userID, Reason: "cheating"}); err != nil {
+				t.Fatalf("add ban: %v", err)
+			}
+
+			if err := tt.do(env, loginToken, resp.RefreshToken); !errors.Is(err, services.ErrPlayerBanned) {
+				t.Fatalf("banned: %v, want %v", err, services.ErrPlayerBanned)
+			}
+
+			if err := env.svc.UnbanPlayer(ctx, userID); err != nil {
+				t.Fatalf("unban: %v", err)
+			}
+			if err := tt.do(env, loginToken, resp.RefreshToken); err != nil {
+				t.Fatalf("unbanned: %v", err)
+			}
+		})
+	}
+}
+
+func TestUnbanPlayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, env *testEnv, userID int64) int64
+		wantErr error
+	}{
+		{
+			name: "banned player",
+			prepare: func(t *testing.T, env *testEnv, userID int64) int64 {
+				env.ban(t, userID)
+				return userID
+			},
+		},
+		{
+			name:    "player not banned",
+			wantErr: services.ErrPlayerNotBanned,
+		},
+		{
+			name: "unknown player",
+			prepare: func(*testing.T, *testEnv, int64) int64 {
+				return 404
+			},
+			wantErr: services.ErrPlayerNotFound,
+		},
+		{
+			name: "locked player",
+			prepare: func(t *testing.T, env *testEnv, userID int64) int64 {
+				env.ban(t, userID)
+				env.locker.setLocked(userID, true)
+				return userID
+			},
+			wantErr: services.ErrPlayerLocked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			userID, _ := env.register(t)
+			target := userID
+			if tt.prepare != nil {
+				target = tt.prepare(t, env, userID)
+			}
+
+			err := env.svc.UnbanPlayer(context.Background(), target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unban = %v, want %v", err, tt.wantErr)
+			}
+
+			env.locker.setLocked(userID, false)
+			err = env.svc.checkNotBanned(context.Background(), userID)
+			if banned := errors.Is(err, services.ErrPlayerBanned); banned != (tt.wantErr == services.ErrPlayerLocked) {
+				t.Errorf("check not banned after unban = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package authsvc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"time"
+)
+
+// Purposes of the single-use tokens sent by email.
+const (
+	emailTokenPurposeVerify = "verify_email"
+	emailTokenPurposeReset  = "reset_password"
+)
+
+const emailTokenBytes = 32
+
+// RegisterEmail creates a new user with an email and password and returns a
+// session with access and refresh tokens. A verification token is sent to the
+// email.
+func (l *Service) RegisterEmail(
+	ctx context.Context,
+	req *models.EmailCredentialsRequest,
+) (resp *models.LoginRespose, err error) {
+	email := models.NormalizeEmail(req.Email)
+	passwordHash, err := l.passwordHasher.Hash(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	var userID int64
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		userID, err = r.User().AddPlayer(ctx)
+		if err != nil {
+			return fmt.Errorf("add player failed: %w", err)
+		}
+
+		if err := l.addEmailCredential(ctx, r, userID, email, passwordHash); err != nil {
+			return err
+		}
+
+		ev := models.UserCreatedEvent{UserID: userID}
+		if err := l.addEvent(ctx, r, l.cfg.UserCreatedTopic, models.EventTypeUserCreated, userID, ev); err != nil {
+			return err
+		}
+
+		err = l.audit(ctx, r, models.AuditEventRegistered, userID, map[string]any{
+			"provider": models.IdentityProviderEmail,
+		})
+		if err != nil {
+			return err
+		}
+
+		return l.sendVerificationToken(ctx, r, userID, email)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pg transaction: %w", err)
+	}
+
+	sessionInfo, err := l.startSessionWithPlayerLock(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("start session with player lock: %w", err)
+	}
+
+	return sessionInfo, nil
+}
+
+// LoginEmail authenticates a user by email and password and starts a new
+// session, or returns an MFA challenge when the user enabled a second factor.
+// The email must have been verified, either with the token sent on
+// registration or by resetting the password.
+func (l *Service) LoginEmail(
+	ctx context.Context,
+	req *models.EmailCredentialsRequest,
+) (resp *models.LoginRespose, err error) {
+	details := map[string]any{"provider": models.IdentityProviderEmail}
+	cred, err := l.pgStore.Raw().Email().FindEmailCredentialByEmail(ctx, models.NormalizeEmail(req.Email))
+	if errors.Is(err, services.ErrNotFound) {
+		// Spend the same time as for a known email so accounts cannot be
+		// enumerated by response time.
+		l.passwordHasher.VerifyDummy(req.Password)
+		err = fmt.Errorf("find email credential: %w", services.ErrValidationCredentials)
+		return nil, l.auditLoginFailure(ctx, 0, loginFailureUnknownIdentity, details, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find email credential: %w", err)
+	}
+
+	ok, err := l.passwordHasher.Verify(req.Password, cred.PasswordHash)
+	if err != nil {
+		return nil, fmt.Errorf("verify password: %w", err)
+	}
+	if !ok {
+		err = fmt.Errorf("verify password: %w", services.ErrValidationCredentials)
+		return nil, l.auditLoginFailure(ctx, cred.PlayerID, loginFailureWrongPassword, details, err)
+	}
+	if !cred.Verified {
+		err = fmt.Errorf("check email credential: %w", services.ErrEmailNotVerified)
+		return nil, l.auditLoginFailure(ctx, cred.PlayerID, loginFailureEmailUnverified, details, err)
+	}
+
+	return l.completeLogin(ctx, cred.PlayerID, details)
+}
+
+// LinkEmail adds an email and password to an existing user, so that a guest
+// account can be recovered on another device. A verification token is sent
+// to the email.
+func (l *Service) LinkEmail(ctx context.Context, userID int64, req *models.EmailCredentialsRequest) error {
+	email := models.NormalizeEmail(req.Email)
+	passwordHash, err := l.passwordHasher.Hash(req.Password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		_, err := r.Email().FindEmailCredentialByPlayer(ctx, userID)
+		if err == nil {
+			return fmt.Errorf("find email credential: %w", services.ErrEmailAlreadyLinked)
+		}
+		if !errors.Is(err, services.ErrNotFound) {
+			return fmt.Errorf("find email credential: %w", err)
+		}
+
+		if err := l.addEmailCredential(ctx, r, userID, email, passwordHash); err != nil {
+			return err
+		}
+
+		if err := l.publishIdentitiesChanged(ctx, r, models.IdentityEventLinked, userID, models.IdentityProviderEmail); err != nil {
+			return err
+		}
+
+		return l.sendVerificationToken(ctx, r, userID, email)
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the email the verification token was sent to as
+// verified.
+func (l *Service) VerifyEmail(ctx context.Context, req *models.VerifyEmailRequest) error {
+	err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		userID, err := r.Email().ConsumeEmailToken(ctx, hashEmailToken(req.Token), emailTokenPurposeVerify)
+		if errors.Is(err, services.ErrNotFound) {
+			return fmt.Errorf("consume email token: %w", services.ErrInvalidEmailToken)
+		}
+		if err != nil {
+			return fmt.Errorf("consume email token: %w", err)
+		}
+
+		if err := r.Email().MarkEmailVerified(ctx, userID); err != nil {
+			return fmt.Errorf("mark email verified: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RequestPasswordReset sends a password reset token to the email. Unknown
+// emails are ignored so that registered emails cannot be discovered.
+func (l *Service) RequestPasswordReset(ctx context.Context, req *models.PasswordResetRequest) error {
+	cred, err := l.pgStore.Raw().Email().FindEmailCredentialByEmail(ctx, models.NormalizeEmail(req.Email))
+	if errors.Is(err, services.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("find email credential: %w", err)
+	}
+
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		// Only the latest reset token stays valid.
+		if err := r.Email().DeleteEmailTokens(ctx, cred.PlayerID, emailTokenPurposeReset); err != nil {
+			return fmt.Errorf("delete email tokens: %w", err)
+		}
+
+		return l.sendEmailToken(
+			ctx,
+			r,
+			cred.PlayerID,
+			cred.Email,
+			emailTokenPurposeReset,
+			models.EmailEventPasswordResetRequested,
+			l.cfg.PasswordResetTTL,
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password using a password reset token and revokes
+// every session of the user.
+func (l *Service) ResetPassword(ctx context.Context, req *models.PasswordResetConfirmRequest) error {
+	passwordHash, err := l.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	var userID int64
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		userID, err = r.Email().ConsumeEmailToken(ctx, hashEmailToken(req.Token), emailTokenPurposeReset)
+		if errors.Is(err, services.ErrNotFound) {
+			return fmt.Errorf("consume email token: %w", services.ErrInvalidEmailToken)
+		}
+		if err != nil {
+			return fmt.Errorf("consume email token: %w", err)
+		}
+
+		if err := r.Email().UpdatePasswordHash(ctx, userID, passwordHash); err != nil {
+			return fmt.Errorf("update password hash: %w", err)
+		}
+
+		// Receiving the reset token proves ownership of the email.
+		if err := r.Email().MarkEmailVerified(ctx, userID); err != nil {
+			return fmt.Errorf("mark email verified: %w", err)
+		}
+		return l.audit(ctx, r, models.AuditEventPasswordReset, userID, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	if err := l.RevokeAllSessions(ctx, userID); err != nil {
+		return fmt.Errorf("revoke all sessions: %w", err)
+	}
+
+	return nil
+}
+
+// addEmailCredential stores the email credential together with the matching
+// identity. Must be called within a pg transaction.
+func (l *Service) addEmailCredential(
+	ctx context.Context,
+	r PostgresRepos,
+	userID int64,
+	email string,
+	passwordHash string,
+) error {
+	err := r.Identity().AddIdentity(ctx, userID, models.IdentityProviderEmail, email)
+	if errors.Is(err, services.ErrAlreadyExists) {
+		return fmt.Errorf("add identity: %w", services.ErrEmailTaken)
+	}
+	if err != nil {
+		return fmt.Errorf("add identity: %w", err)
+	}
+
+	if err := r.Email().AddEmailCredential(ctx, userID, email, passwordHash); err != nil {
+		return fmt.Errorf("add email credential: %w", err)
+	}
+	return nil
+}
+
+func (l *Service) sendVerificationToken(ctx context.Context, r PostgresRepos, userID int64, email string) error {
+	return l.sendEmailToken(
+		ctx,
+		r,
+		userID,
+		email,
+		emailTokenPurposeVerify,
+		models.EmailEventVerificationRequested,
+		l.cfg.EmailVerificationTTL,
+	)
+}
+
+// sendEmailToken stores a new single-use token and publishes an event asking
+// the mailer to deliver it. Must be called within a pg transaction.
+func (l *Service) sendEmailToken(
+	ctx context.Context,
+	r PostgresRepos,
+	userID int64,
+	email string,
+	purpose string,
+	eventType string,
+	ttl time.Duration,
+) error {
+	token, err := newRandomToken(emailTokenBytes)
+	if err != nil {
+		return fmt.Errorf("generate email token: %w", err)
+	}
+	expiresAt := time.Now().UTC().Add(ttl)
+
+	if err := r.Email().AddEmailToken(ctx, hashEmailToken(token), userID, purpose, expiresAt); err != nil {
+		return fmt.Errorf("add email token: %w", err)
+	}
+
+	ev := models.EmailEvent{
+		Type:          eventType,
+		UserID:        userID,
+		Email:         email,
+		Token:         token,
+		ExpiresAtUnix: expiresAt.Unix(),
+	}
+	if err := l.addEvent(ctx, r, l.cfg.EmailEventsTopic, models.EventTypeEmail, userID, ev); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// newRandomToken returns size random bytes encoded for use in URLs.
+func newRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read random bytes: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashEmailToken returns the digest under which an email token is stored, so
+// that a database leak does not expose usable tokens.
+func hashEmailToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
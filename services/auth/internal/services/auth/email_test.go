@@ -0,0 +1,115 @@
+package authsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"testing"
+)
+
+// emailToken returns the token of the last email event of the given type.
+func (e *testEnv) emailToken(t *testing.T, emailType string) string {
+	t.Helper()
+	var token string
+	for _, ev := range e.pg.eventsOfType(models.EventTypeEmail) {
+		var email models.EmailEvent
+		if err := json.Unmarshal(ev.Payload, &email); err != nil {
+			t.Fatalf("unmarshal email event: %v", err)
+		}
+		if email.Type == emailType {
+			token = email.Token
+		}
+	}
+	if token == "" {
+		t.Fatalf("no %s email sent", emailType)
+	}
+	return token
+}
+
+func TestLoginEmail(t *testing.T) {
+	const (
+		email    = "Player@Example.com"
+		password = "correct horse"
+	)
+	tests := []struct {
+		name     string
+		verify   func(t *testing.T, env *testEnv)
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "unverified email",
+			password: password,
+			wantErr:  services.ErrEmailNotVerified,
+		},
+		{
+			name:     "unverified email with a wrong password",
+			password: "wrong password",
+			wantErr:  services.ErrValidationCredentials,
+		},
+		{
+			name: "verified email",
+			verify: func(t *testing.T, env *testEnv) {
+				token := env.emailToken(t, models.EmailEventVerificationRequested)
+				if err := env.svc.VerifyEmail(context.Background(), &models.VerifyEmailRequest{Token: token}); err != nil {
+					t.Fatalf("verify email: %v", err)
+				}
+			},
+			password: password,
+		},
+		{
+			name: "email verified by a password reset",
+			verify: func(t *testing.T, env *testEnv) {
+				ctx := context.Background()
+				if err := env.svc.RequestPasswordReset(ctx, &models.PasswordResetRequest{Email: email}); err != nil {
+					t.Fatalf("request password reset: %v", err)
+				}
+				err := env.svc.ResetPassword(ctx, &models.PasswordResetConfirmRequest{
+					Token:       env.emailToken(t, models.EmailEventPasswordResetRequested),
+					NewPassword: password,
+				})
+				if err != nil {
+					t.Fatalf("reset password: %v", err)
+				}
+			},
+			password: password,
+		},
+		{
+			name: "verified email with a wrong password",
+			verify: func(t *testing.T, env *testEnv) {
+				token := env.emailToken(t, models.EmailEventVerificationRequested)
+				if err := env.svc.VerifyEmail(context.Background(), &models.VerifyEmailRequest{Token: token}); err != nil {
+					t.Fatalf("verify email: %v", err)
+				}
+			},
+			password: "wrong password",
+			wantErr:  services.ErrValidationCredentials,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			registered, err := env.svc.RegisterEmail(ctx, &models.EmailCredentialsRequest{Email: email, Password: password})
+			if err != nil {
+				t.Fatalf("register: %v", err)
+			}
+			if tt.verify != nil {
+				tt.verify(t, env)
+			}
+
+			resp, err := env.svc.LoginEmail(ctx, &models.EmailCredentialsRequest{Email: email, Password: tt.password})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("login = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got, want := env.userOf(t, resp), env.userOf(t, registered); got != want {
+				t.Errorf("signed in to user %d, want %d", got, want)
+			}
+		})
+	}
+}
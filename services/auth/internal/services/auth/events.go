@@ -0,0 +1,37 @@
+package authsvc
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/pkg/outbox"
+	"go-game-backend/services/auth/internal/clientinfo"
+	"go-game-backend/services/auth/pkg/models"
+	"strconv"
+)
+
+// producerName is sent in the producer header of the published events.
+const producerName = "auth"
+
+// addEvent saves ev to the outbox to be published to topic once the
+// transaction of r commits. Events are keyed by the player they are about,
+// so that consumers receive the events of a player in order, and carry the
+// ID of the request creating them as correlation ID.
+func (l *Service) addEvent(ctx context.Context, r PostgresRepos, topic, eventType string, userID int64, ev any) error {
+	headers := outbox.Headers{
+		outbox.HeaderEventType:     eventType,
+		outbox.HeaderSchemaVersion: models.EventSchemaVersion,
+		outbox.HeaderProducer:      producerName,
+	}
+	if reqID := clientinfo.FromContext(ctx).RequestID; reqID != "" {
+		headers[outbox.HeaderCorrelationID] = reqID
+	}
+	if err := r.Outbox().AddJSON(ctx, topic, eventKey(userID), ev, headers); err != nil {
+		return fmt.Errorf("save outbox event: %w", err)
+	}
+	return nil
+}
+
+// eventKey returns the key of the events about the player.
+func eventKey(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
@@ -0,0 +1,1010 @@
+package authsvc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-game-backend/pkg/futils"
+	"go-game-backend/pkg/jwks"
+	"go-game-backend/pkg/jwtfactory"
+	"go-game-backend/pkg/logging"
+	"go-game-backend/pkg/outbox"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/internal/services/token"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	redisstore "go-game-backend/pkg/redis"
+)
+
+// memPG is an in-memory PostgresStore. Transactions run one at a time and
+// leave no change behind when they fail.
+type memPG struct {
+	txMu sync.Mutex
+	mu   sync.Mutex
+	data pgData
+}
+
+type pgData struct {
+	lastPlayerID   int64
+	lastBanID      int64
+	lastAuditID    int64
+	players        map[int64]dto.Player
+	identities     []identityRow
+	emails         map[int64]dto.EmailCredential
+	emailTokens    map[string]emailTokenRow
+	totps          map[int64]dto.TOTP
+	recoveryCodes  map[int64]map[string]bool
+	bans           []dto.Ban
+	audit          []dto.AuditEntry
+	serviceClients map[string]dto.ServiceClient
+	events         []memEvent
+}
+
+type identityRow struct {
+	playerID          int64
+	provider, subject string
+}
+
+type emailTokenRow struct {
+	playerID  int64
+	purpose   string
+	expiresAt time.Time
+}
+
+// memEvent is an event saved to the outbox.
+type memEvent struct {
+	Topic   string
+	Key     string
+	Type    string
+	Payload []byte
+}
+
+func newMemPG() *memPG {
+	return &memPG{data: pgData{
+		players:        map[int64]dto.Player{},
+		emails:         map[int64]dto.EmailCredential{},
+		emailTokens:    map[string]emailTokenRow{},
+		totps:          map[int64]dto.TOTP{},
+		recoveryCodes:  map[int64]map[string]bool{},
+		serviceClients: map[string]dto.ServiceClient{},
+	}}
+}
+
+func (d pgData) clone() pgData {
+	c := d
+	c.players = maps.Clone(d.players)
+	c.identities = slices.Clone(d.identities)
+	c.emails = maps.Clone(d.emails)
+	c.emailTokens = maps.Clone(d.emailTokens)
+	c.totps = maps.Clone(d.totps)
+	c.recoveryCodes = make(map[int64]map[string]bool, len(d.recoveryCodes))
+	for id, codes := range d.recoveryCodes {
+		c.recoveryCodes[id] = maps.Clone(codes)
+	}
+	c.bans = slices.Clone(d.bans)
+	c.audit = slices.Clone(d.audit)
+	c.serviceClients = maps.Clone(d.serviceClients)
+	c.events = slices.Clone(d.events)
+	return c
+}
+
+func (s *memPG) DoTx(ctx context.Context, f func(ctx context.Context, r PostgresRepos) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	snapshot := s.data.clone()
+	s.mu.Unlock()
+
+	if err := f(ctx, s); err != nil {
+		s.mu.Lock()
+		s.data = snapshot
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (s *memPG) Raw() PostgresRepos { return s }
+
+func (s *memPG) User() UserRepository                   { return s }
+func (s *memPG) Identity() IdentityRepository           { return s }
+func (s *memPG) Email() EmailRepository                 { return s }
+func (s *memPG) MFA() MFARepository                     { return s }
+func (s *memPG) Ban() BanRepository                     { return s }
+func (s *memPG) Audit() AuditRepository                 { return s }
+func (s *memPG) ServiceClient() ServiceClientRepository { return s }
+func (s *memPG) Outbox() OutboxRepository               { return s }
+
+func (s *memPG) AddPlayer(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.lastPlayerID++
+	s.data.players[s.data.lastPlayerID] = dto.Player{ID: s.data.lastPlayerID}
+	return s.data.lastPlayerID, nil
+}
+
+func (s *memPG) LockPlayer(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.players[playerID]; !ok {
+		return services.ErrNotFound
+	}
+	return nil
+}
+
+func (s *memPG) DeletePlayer(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deletePlayer(playerID)
+	return nil
+}
+
+// deletePlayer removes the player together with the rows referencing it, as
+// the foreign keys cascade.
+func (s *memPG) deletePlayer(playerID int64) {
+	delete(s.data.players, playerID)
+	s.data.identities = slices.DeleteFunc(s.data.identities, func(row identityRow) bool {
+		return row.playerID == playerID
+	})
+	delete(s.data.emails, playerID)
+	maps.DeleteFunc(s.data.emailTokens, func(_ string, row emailTokenRow) bool {
+		return row.playerID == playerID
+	})
+	delete(s.data.totps, playerID)
+	delete(s.data.recoveryCodes, playerID)
+	s.data.bans = slices.DeleteFunc(s.data.bans, func(ban dto.Ban) bool {
+		return ban.PlayerID == playerID
+	})
+}
+
+func (s *memPG) GetPlayer(_ context.Context, playerID int64) (dto.Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	player, ok := s.data.players[playerID]
+	if !ok {
+		return dto.Player{}, services.ErrNotFound
+	}
+	return player, nil
+}
+
+func (s *memPG) RequestDeletion(_ context.Context, playerID int64, deleteAfter time.Time) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	player, ok := s.data.players[playerID]
+	if !ok {
+		return time.Time{}, services.ErrNotFound
+	}
+	if player.DeleteAfter.IsZero() {
+		player.DeletionRequestedAt = time.Now().UTC()
+		player.DeleteAfter = deleteAfter
+		s.data.players[playerID] = player
+	}
+	return player.DeleteAfter, nil
+}
+
+func (s *memPG) CancelDeletion(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	player, ok := s.data.players[playerID]
+	if !ok || player.DeleteAfter.IsZero() {
+		return services.ErrNotFound
+	}
+	s.data.players[playerID] = dto.Player{ID: playerID}
+	return nil
+}
+
+func (s *memPG) ListPlayersDueForDeletion(_ context.Context, now time.Time, limit int) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []int64
+	for id, player := range s.data.players {
+		if !player.DeleteAfter.IsZero() && !player.DeleteAfter.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (s *memPG) DeleteDuePlayer(_ context.Context, playerID int64, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	player, ok := s.data.players[playerID]
+	if !ok || player.DeleteAfter.IsZero() || player.DeleteAfter.After(now) {
+		return services.ErrNotFound
+	}
+	s.deletePlayer(playerID)
+	return nil
+}
+
+func (s *memPG) AddIdentity(_ context.Context, playerID int64, provider, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range s.data.identities {
+		if row.provider == provider && row.subject == subject {
+			return services.ErrAlreadyExists
+		}
+	}
+	s.data.identities = append(s.data.identities, identityRow{playerID, provider, subject})
+	return nil
+}
+
+func (s *memPG) FindPlayerByIdentity(_ context.Context, provider, subject string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range s.data.identities {
+		if row.provider == provider && row.subject == subject {
+			return row.playerID, nil
+		}
+	}
+	return 0, services.ErrNotFound
+}
+
+func (s *memPG) ListIdentities(_ context.Context, playerID int64) ([]dto.Identity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var identities []dto.Identity
+	for _, row := range s.data.identities {
+		if row.playerID == playerID {
+			identities = append(identities, dto.Identity{Provider: row.provider, Subject: row.subject})
+		}
+	}
+	return identities, nil
+}
+
+func (s *memPG) DeleteIdentity(_ context.Context, playerID int64, provider, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.data.identities)
+	s.data.identities = slices.DeleteFunc(s.data.identities, func(row identityRow) bool {
+		return row == identityRow{playerID, provider, subject}
+	})
+	if len(s.data.identities) == n {
+		return services.ErrNotFound
+	}
+	return nil
+}
+
+func (s *memPG) MoveIdentities(_ context.Context, sourcePlayerID, targetPlayerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, row := range s.data.identities {
+		if row.playerID == sourcePlayerID {
+			s.data.identities[i].playerID = targetPlayerID
+		}
+	}
+	return nil
+}
+
+func (s *memPG) AddEmailCredential(_ context.Context, playerID int64, email, passwordHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cred := range s.data.emails {
+		if cred.Email == email {
+			return services.ErrAlreadyExists
+		}
+	}
+	if _, ok := s.data.emails[playerID]; ok {
+		return services.ErrAlreadyExists
+	}
+	s.data.emails[playerID] = dto.EmailCredential{PlayerID: playerID, Email: email, PasswordHash: passwordHash}
+	return nil
+}
+
+func (s *memPG) DeleteEmailCredential(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data.emails, playerID)
+	return nil
+}
+
+func (s *memPG) FindEmailCredentialByEmail(_ context.Context, email string) (dto.EmailCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cred := range s.data.emails {
+		if cred.Email == email {
+			return cred, nil
+		}
+	}
+	return dto.EmailCredential{}, services.ErrNotFound
+}
+
+func (s *memPG) FindEmailCredentialByPlayer(_ context.Context, playerID int64) (dto.EmailCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cred, ok := s.data.emails[playerID]
+	if !ok {
+		return dto.EmailCredential{}, services.ErrNotFound
+	}
+	return cred, nil
+}
+
+func (s *memPG) MarkEmailVerified(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cred, ok := s.data.emails[playerID]; ok {
+		cred.Verified = true
+		s.data.emails[playerID] = cred
+	}
+	return nil
+}
+
+func (s *memPG) UpdatePasswordHash(_ context.Context, playerID int64, passwordHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cred, ok := s.data.emails[playerID]
+	if !ok {
+		return services.ErrNotFound
+	}
+	cred.PasswordHash = passwordHash
+	s.data.emails[playerID] = cred
+	return nil
+}
+
+func (s *memPG) AddEmailToken(
+	_ context.Context,
+	tokenHash []byte,
+	playerID int64,
+	purpose string,
+	expiresAt time.Time,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.emailTokens[string(tokenHash)] = emailTokenRow{playerID, purpose, expiresAt}
+	return nil
+}
+
+func (s *memPG) ConsumeEmailToken(_ context.Context, tokenHash []byte, purpose string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	row, ok := s.data.emailTokens[string(tokenHash)]
+	if !ok || row.purpose != purpose || !row.expiresAt.After(time.Now()) {
+		return 0, services.ErrNotFound
+	}
+	delete(s.data.emailTokens, string(tokenHash))
+	return row.playerID, nil
+}
+
+func (s *memPG) DeleteEmailTokens(_ context.Context, playerID int64, purpose string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	maps.DeleteFunc(s.data.emailTokens, func(_ string, row emailTokenRow) bool {
+		return row.playerID == playerID && row.purpose == purpose
+	})
+	return nil
+}
+
+func (s *memPG) UpsertTOTPSecret(_ context.Context, playerID int64, secretEncrypted []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.totps[playerID].Confirmed {
+		return services.ErrAlreadyExists
+	}
+	s.data.totps[playerID] = dto.TOTP{SecretEncrypted: secretEncrypted}
+	return nil
+}
+
+func (s *memPG) GetTOTP(_ context.Context, playerID int64) (dto.TOTP, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.data.totps[playerID]
+	if !ok {
+		return dto.TOTP{}, services.ErrNotFound
+	}
+	return t, nil
+}
+
+func (s *memPG) ConfirmTOTP(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.data.totps[playerID]
+	if !ok || t.Confirmed {
+		return services.ErrNotFound
+	}
+	t.Confirmed = true
+	s.data.totps[playerID] = t
+	return nil
+}
+
+func (s *memPG) UseTOTPStep(_ context.Context, playerID, step int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.data.totps[playerID]
+	if !ok || t.LastUsedStep >= step {
+		return services.ErrAlreadyExists
+	}
+	t.LastUsedStep = step
+	s.data.totps[playerID] = t
+	return nil
+}
+
+func (s *memPG) DeleteTOTP(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data.totps, playerID)
+	return nil
+}
+
+func (s *memPG) AddRecoveryCodes(_ context.Context, playerID int64, codeHashes [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.recoveryCodes[playerID] == nil {
+		s.data.recoveryCodes[playerID] = map[string]bool{}
+	}
+	for _, codeHash := range codeHashes {
+		s.data.recoveryCodes[playerID][string(codeHash)] = false
+	}
+	return nil
+}
+
+func (s *memPG) UseRecoveryCode(_ context.Context, playerID int64, codeHash []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	used, ok := s.data.recoveryCodes[playerID][string(codeHash)]
+	if !ok || used {
+		return services.ErrNotFound
+	}
+	s.data.recoveryCodes[playerID][string(codeHash)] = true
+	return nil
+}
+
+func (s *memPG) DeleteRecoveryCodes(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data.recoveryCodes, playerID)
+	return nil
+}
+
+func (s *memPG) AddBan(_ context.Context, ban dto.Ban) (dto.Ban, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.lastBanID++
+	ban.ID = s.data.lastBanID
+	ban.CreatedAt = time.Now().UTC()
+	s.data.bans = append(s.data.bans, ban)
+	return ban, nil
+}
+
+func (s *memPG) FindActiveBan(_ context.Context, playerID int64) (dto.Ban, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ban := range s.data.bans {
+		if ban.PlayerID == playerID && banActive(ban) {
+			return ban, nil
+		}
+	}
+	return dto.Ban{}, services.ErrNotFound
+}
+
+func (s *memPG) LiftBans(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lifted := 0
+	for i, ban := range s.data.bans {
+		if ban.PlayerID == playerID && banActive(ban) {
+			s.data.bans[i].LiftedAt = time.Now().UTC()
+			lifted++
+		}
+	}
+	if lifted == 0 {
+		return services.ErrNotFound
+	}
+	return nil
+}
+
+func (s *memPG) ListBans(_ context.Context, playerID int64) ([]dto.Ban, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var bans []dto.Ban
+	for _, ban := range s.data.bans {
+		if ban.PlayerID == playerID {
+			bans = append(bans, ban)
+		}
+	}
+	return bans, nil
+}
+
+func banActive(ban dto.Ban) bool {
+	return ban.LiftedAt.IsZero() && (ban.ExpiresAt.IsZero() || ban.ExpiresAt.After(time.Now()))
+}
+
+func (s *memPG) AddAuditEntry(_ context.Context, entry dto.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.lastAuditID++
+	entry.ID = s.data.lastAuditID
+	entry.CreatedAt = time.Now().UTC()
+	s.data.audit = append(s.data.audit, entry)
+	return nil
+}
+
+func (s *memPG) ListAuditEntries(
+	_ context.Context,
+	playerID int64,
+	from, to time.Time,
+	limit int,
+) ([]dto.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []dto.AuditEntry
+	for _, entry := range slices.Backward(s.data.audit) {
+		if entry.PlayerID == playerID && !entry.CreatedAt.Before(from) && entry.CreatedAt.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (s *memPG) AnonymizeAuditEntries(_ context.Context, playerID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, entry := range s.data.audit {
+		if entry.PlayerID == playerID {
+			s.data.audit[i].IP = ""
+			s.data.audit[i].UserAgent = ""
+		}
+	}
+	return nil
+}
+
+func (s *memPG) AddServiceClient(_ context.Context, client dto.ServiceClient) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.serviceClients[client.ClientID]; ok {
+		return services.ErrAlreadyExists
+	}
+	s.data.serviceClients[client.ClientID] = client
+	return nil
+}
+
+func (s *memPG) FindServiceClient(_ context.Context, clientID string) (dto.ServiceClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	client, ok := s.data.serviceClients[clientID]
+	if !ok {
+		return dto.ServiceClient{}, services.ErrNotFound
+	}
+	return client, nil
+}
+
+func (s *memPG) AddJSON(_ context.Context, topic, key string, payload any, headers outbox.Headers) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.events = append(s.data.events, memEvent{
+		Topic:   topic,
+		Key:     key,
+		Type:    headers[outbox.HeaderEventType],
+		Payload: b,
+	})
+	return nil
+}
+
+func (s *memPG) DeleteByKey(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key == "" {
+		return nil
+	}
+	s.data.events = slices.DeleteFunc(s.data.events, func(ev memEvent) bool {
+		return ev.Key == key
+	})
+	return nil
+}
+
+// eventsOfType returns the saved outbox events of the given type.
+func (s *memPG) eventsOfType(eventType string) []memEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []memEvent
+	for _, ev := range s.data.events {
+		if ev.Type == eventType {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// eventTypesOfKey returns the types of the saved outbox events with the key.
+func (s *memPG) eventTypesOfKey(key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var types []string
+	for _, ev := range s.data.events {
+		if ev.Key == key {
+			types = append(types, ev.Type)
+		}
+	}
+	return types
+}
+
+// auditEvents returns the events recorded in the audit log for the player,
+// oldest first.
+func (s *memPG) auditEvents(playerID int64) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []string
+	for _, entry := range s.data.audit {
+		if entry.PlayerID == playerID {
+			events = append(events, entry.Event)
+		}
+	}
+	return events
+}
+
+// memRedis is an in-memory RedisStore. Expired keys are dropped on read.
+type memRedis struct {
+	mu             sync.Mutex
+	sessions       map[uuid.UUID]dto.Session
+	refreshTokens  map[uuid.UUID]memRefreshToken
+	userRefresh    map[int64]map[uuid.UUID]bool
+	sessionRefresh map[uuid.UUID]map[uuid.UUID]bool
+	challenges     map[string]memChallenge
+	transferCodes  map[string]int64
+	userTransfer   map[int64]string
+
+	// setSessionErr, when set, fails storing sessions.
+	setSessionErr error
+}
+
+type memRefreshToken struct {
+	info      dto.SessionInfo
+	expiresAt time.Time
+}
+
+type memChallenge struct {
+	challenge dto.MFAChallenge
+	expiresAt time.Time
+}
+
+func newMemRedis() *memRedis {
+	return &memRedis{
+		sessions:       map[uuid.UUID]dto.Session{},
+		refreshTokens:  map[uuid.UUID]memRefreshToken{},
+		userRefresh:    map[int64]map[uuid.UUID]bool{},
+		sessionRefresh: map[uuid.UUID]map[uuid.UUID]bool{},
+		challenges:     map[string]memChallenge{},
+		transferCodes:  map[string]int64{},
+		userTransfer:   map[int64]string{},
+	}
+}
+
+// DoTx runs f without isolation, as commands queued in a Redis transaction
+// do not see each other either.
+func (s *memRedis) DoTx(ctx context.Context, f func(ctx context.Context, r RedisRepos) error) error {
+	return f(ctx, s)
+}
+
+func (s *memRedis) Raw() RedisRepos { return s }
+
+func (s *memRedis) Session() SessionRepository           { return s }
+func (s *memRedis) MFAChallenge() MFAChallengeRepository { return s }
+func (s *memRedis) TransferCode() TransferCodeRepository { return s }
+
+func (s *memRedis) SetSession(_ context.Context, session dto.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.setSessionErr != nil {
+		return s.setSessionErr
+	}
+	s.sessions[session.Token] = session
+	return nil
+}
+
+func (s *memRedis) GetSession(_ context.Context, sessionToken uuid.UUID) (dto.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionToken]
+	if !ok || session.ExpiresAtUnix <= time.Now().Unix() {
+		return dto.Session{}, services.ErrNotFound
+	}
+	return session, nil
+}
+
+func (s *memRedis) ListSessions(_ context.Context, userID int64) ([]dto.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sessions []dto.Session
+	for _, session := range s.sessions {
+		if session.UserID == userID && session.ExpiresAtUnix > time.Now().Unix() {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].CreatedAtUnix != sessions[j].CreatedAtUnix {
+			return sessions[i].CreatedAtUnix < sessions[j].CreatedAtUnix
+		}
+		return sessions[i].Token.String() < sessions[j].Token.String()
+	})
+	return sessions, nil
+}
+
+func (s *memRedis) TouchSession(_ context.Context, sessionToken uuid.UUID, ip string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionToken]
+	if !ok {
+		return nil
+	}
+	session.IP = ip
+	session.LastUsedAtUnix = at.Unix()
+	s.sessions[sessionToken] = session
+	return nil
+}
+
+func (s *memRedis) RemoveSession(_ context.Context, _ int64, sessionToken uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionToken)
+	return nil
+}
+
+func (s *memRedis) SetRefreshToken(
+	_ context.Context,
+	token uuid.UUID,
+	sessionInfo dto.SessionInfo,
+	expiresAt time.Time,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshTokens[token] = memRefreshToken{info: sessionInfo, expiresAt: expiresAt}
+	addToSet(s.userRefresh, sessionInfo.UserID, token)
+	addToSet(s.sessionRefresh, sessionInfo.SessionToken, token)
+	return nil
+}
+
+func (s *memRedis) RemoveRefreshToken(_ context.Context, userID int64, token uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.refreshTokens, token)
+	delete(s.userRefresh[userID], token)
+	return nil
+}
+
+func (s *memRedis) GetSessionInfo(_ context.Context, refreshToken uuid.UUID) (dto.SessionInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.refreshTokens[refreshToken]
+	if !ok || !token.expiresAt.After(time.Now()) {
+		return dto.SessionInfo{}, services.ErrNotFound
+	}
+	return token.info, nil
+}
+
+func (s *memRedis) MarkRefreshTokenRotated(_ context.Context, token uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rt, ok := s.refreshTokens[token]; ok {
+		rt.info.Rotated = true
+		s.refreshTokens[token] = rt
+	}
+	return nil
+}
+
+func (s *memRedis) GetUserRefreshTokens(_ context.Context, userID int64) ([]uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Collect(maps.Keys(s.userRefresh[userID])), nil
+}
+
+func (s *memRedis) GetSessionRefreshTokens(_ context.Context, sessionToken uuid.UUID) ([]uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Collect(maps.Keys(s.sessionRefresh[sessionToken])), nil
+}
+
+func (s *memRedis) RemoveSessionRefreshTokens(_ context.Context, sessionToken uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessionRefresh, sessionToken)
+	return nil
+}
+
+func (s *memRedis) SetMFAChallenge(_ context.Context, tokenHash string, userID int64, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenges[tokenHash] = memChallenge{challenge: dto.MFAChallenge{UserID: userID}, expiresAt: expiresAt}
+	return nil
+}
+
+func (s *memRedis) GetMFAChallenge(_ context.Context, tokenHash string) (dto.MFAChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.challenges[tokenHash]
+	if !ok || !c.expiresAt.After(time.Now()) {
+		return dto.MFAChallenge{}, services.ErrNotFound
+	}
+	return c.challenge, nil
+}
+
+func (s *memRedis) IncrMFAChallengeAttempts(_ context.Context, tokenHash string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.challenges[tokenHash]
+	c.challenge.Attempts++
+	s.challenges[tokenHash] = c
+	return c.challenge.Attempts, nil
+}
+
+func (s *memRedis) RemoveMFAChallenge(_ context.Context, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.challenges[tokenHash]; !ok {
+		return services.ErrNotFound
+	}
+	delete(s.challenges, tokenHash)
+	return nil
+}
+
+func (s *memRedis) SetTransferCode(_ context.Context, codeHash string, userID int64, _ time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transferCodes[codeHash] = userID
+	s.userTransfer[userID] = codeHash
+	return nil
+}
+
+func (s *memRedis) GetTransferCode(_ context.Context, codeHash string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	userID, ok := s.transferCodes[codeHash]
+	if !ok {
+		return 0, services.ErrNotFound
+	}
+	return userID, nil
+}
+
+func (s *memRedis) GetUserTransferCode(_ context.Context, userID int64) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	codeHash, ok := s.userTransfer[userID]
+	if !ok {
+		return "", services.ErrNotFound
+	}
+	return codeHash, nil
+}
+
+func (s *memRedis) ConsumeTransferCode(_ context.Context, codeHash string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	userID, ok := s.transferCodes[codeHash]
+	if !ok {
+		return 0, services.ErrNotFound
+	}
+	delete(s.transferCodes, codeHash)
+	return userID, nil
+}
+
+func (s *memRedis) RemoveTransferCode(_ context.Context, userID int64, codeHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.transferCodes, codeHash)
+	delete(s.userTransfer, userID)
+	return nil
+}
+
+func addToSet[K comparable](sets map[K]map[uuid.UUID]bool, key K, token uuid.UUID) {
+	if sets[key] == nil {
+		sets[key] = map[uuid.UUID]bool{}
+	}
+	sets[key][token] = true
+}
+
+// memLocker is a player lock that waits for the lock to be released. Players
+// marked as locked fail like a lock held by another instance does.
+type memLocker struct {
+	mu     sync.Mutex
+	locks  map[int64]*sync.Mutex
+	locked map[int64]bool
+}
+
+func newMemLocker() *memLocker {
+	return &memLocker{locks: map[int64]*sync.Mutex{}, locked: map[int64]bool{}}
+}
+
+func (l *memLocker) DoWithPlayerLock(ctx context.Context, userID int64, f futils.CtxF) error {
+	l.mu.Lock()
+	if l.locked[userID] {
+		l.mu.Unlock()
+		return redisstore.ErrLockNotObtained
+	}
+	lock, ok := l.locks[userID]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[userID] = lock
+	}
+	l.mu.Unlock()
+
+	lock.Lock()
+	defer lock.Unlock()
+	return f(ctx)
+}
+
+func (l *memLocker) setLocked(userID int64, locked bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locked[userID] = locked
+}
+
+// plainHasher stores passwords as they are, which keeps the tests fast.
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error) { return "plain:" + password, nil }
+
+func (plainHasher) Verify(password, encoded string) (bool, error) {
+	return encoded == "plain:"+password, nil
+}
+
+func (plainHasher) VerifyDummy(string) {}
+
+// plainBox stores secrets as they are.
+type plainBox struct{}
+
+func (plainBox) Seal(plaintext []byte) ([]byte, error) { return bytes.Clone(plaintext), nil }
+func (plainBox) Open(sealed []byte) ([]byte, error)    { return bytes.Clone(sealed), nil }
+
+// testEnv is a Service backed by in-memory stores.
+type testEnv struct {
+	svc    *Service
+	pg     *memPG
+	rx     *memRedis
+	locker *memLocker
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	keys, err := jwks.Load(&jwks.Config{Algorithm: "HS256", Secret: strings.Repeat("k", 32)})
+	if err != nil {
+		t.Fatalf("load keys: %v", err)
+	}
+	tokens := tknfactory.New(jwtfactory.New(keys), &tknfactory.Config{
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 24 * time.Hour,
+		ServiceTokenTTL: time.Hour,
+	})
+
+	cfg := &Config{
+		UserCreatedTopic:       "user-created",
+		SecurityEventsTopic:    "security-events",
+		IntrospectionCacheTTL:  time.Second,
+		IntrospectionCacheSize: 16,
+		EmailEventsTopic:       "email-events",
+		EmailVerificationTTL:   time.Hour,
+		PasswordResetTTL:       time.Hour,
+		IdentitiesTopic:        "identities-changed",
+		PlayerBannedTopic:      "player-banned",
+		SessionMode:            SessionModeSingle,
+		SessionReplacedTopic:   "session-replaced",
+		MFAIssuer:              "test",
+		MFAChallengeTTL:        5 * time.Minute,
+		MFAMaxAttempts:         3,
+		DeletionGracePeriod:    time.Hour,
+		UserDeletedTopic:       "user-deleted",
+		TransferCodeTTL:        time.Hour,
+	}
+
+	env := &testEnv{pg: newMemPG(), rx: newMemRedis(), locker: newMemLocker()}
+	env.svc = New(cfg, env.pg, env.rx, env.locker, tokens, plainHasher{}, nil, plainBox{}, logging.NewNopLogger())
+	return env
+}
@@ -0,0 +1,180 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+)
+
+// ListIdentities returns the credentials the user can sign in with.
+func (l *Service) ListIdentities(ctx context.Context, userID int64) (*models.IdentitiesResponse, error) {
+	identities, err := l.pgStore.Raw().Identity().ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list identities: %w", err)
+	}
+
+	resp := &models.IdentitiesResponse{
+		Identities: make([]models.Identity, 0, len(identities)),
+	}
+	for _, identity := range identities {
+		resp.Identities = append(resp.Identities, models.Identity{
+			Provider:      identity.Provider,
+			Subject:       identity.Subject,
+			CreatedAtUnix: identity.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// LinkGuest binds a new guest login token to the user so another device can
+// sign in to the same account.
+func (l *Service) LinkGuest(ctx context.Context, userID int64, req *models.LinkGuestRequest) error {
+	err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		err := r.Identity().AddIdentity(ctx, userID, models.IdentityProviderGuest, req.LoginToken.String())
+		if errors.Is(err, services.ErrAlreadyExists) {
+			return fmt.Errorf("add identity: %w", services.ErrIdentityTaken)
+		}
+		if err != nil {
+			return fmt.Errorf("add identity: %w", err)
+		}
+
+		return l.publishIdentitiesChanged(ctx, r, models.IdentityEventLinked, userID, models.IdentityProviderGuest)
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UnlinkIdentity removes a credential from the user. The last identity cannot
+// be removed.
+func (l *Service) UnlinkIdentity(ctx context.Context, userID int64, req *models.UnlinkIdentityRequest) error {
+	err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		// Serializes concurrent unlinks so they cannot remove every identity.
+		if err := r.User().LockPlayer(ctx, userID); err != nil {
+			return fmt.Errorf("lock player: %w", err)
+		}
+
+		identities, err := r.Identity().ListIdentities(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("list identities: %w", err)
+		}
+		if len(identities) <= 1 {
+			return services.ErrLastIdentity
+		}
+
+		err = r.Identity().DeleteIdentity(ctx, userID, req.Provider, req.Subject)
+		if errors.Is(err, services.ErrNotFound) {
+			return fmt.Errorf("delete identity: %w", services.ErrIdentityNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("delete identity: %w", err)
+		}
+
+		if req.Provider == models.IdentityProviderEmail {
+			if err := r.Email().DeleteEmailCredential(ctx, userID); err != nil {
+				return fmt.Errorf("delete email credential: %w", err)
+			}
+		}
+
+		return l.publishIdentitiesChanged(ctx, r, models.IdentityEventUnlinked, userID, req.Provider)
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MergeGuest moves the identities of the guest account owning the login token
+// to the user and deletes the guest account, ending its sessions.
+func (l *Service) MergeGuest(ctx context.Context, userID int64, req *models.LinkGuestRequest) error {
+	guestID, err := l.pgStore.Raw().Identity().FindPlayerByIdentity(
+		ctx,
+		models.IdentityProviderGuest,
+		req.LoginToken.String(),
+	)
+	if errors.Is(err, services.ErrNotFound) {
+		return fmt.Errorf("find guest: %w", services.ErrValidationCredentials)
+	}
+	if err != nil {
+		return fmt.Errorf("find guest: %w", err)
+	}
+	if guestID == userID {
+		return nil
+	}
+	// Merging must not carry a banned guest's progress into a clean account.
+	if err := l.checkNotBanned(ctx, guestID); err != nil {
+		return err
+	}
+
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		// Lock in a stable order so that opposite merges cannot deadlock.
+		for _, id := range []int64{min(userID, guestID), max(userID, guestID)} {
+			if err := r.User().LockPlayer(ctx, id); err != nil {
+				return fmt.Errorf("lock player: %w", err)
+			}
+		}
+
+		identities, err := r.Identity().ListIdentities(ctx, guestID)
+		if err != nil {
+			return fmt.Errorf("list identities: %w", err)
+		}
+		for _, identity := range identities {
+			if identity.Provider != models.IdentityProviderGuest {
+				return services.ErrMergeNotAllowed
+			}
+		}
+
+		if err := r.Identity().MoveIdentities(ctx, guestID, userID); err != nil {
+			return fmt.Errorf("move identities: %w", err)
+		}
+		if err := r.User().DeletePlayer(ctx, guestID); err != nil {
+			return fmt.Errorf("delete player: %w", err)
+		}
+
+		ev := models.IdentitiesChangedEvent{
+			Type:         models.IdentityEventMerged,
+			UserID:       userID,
+			MergedUserID: guestID,
+		}
+		if err := l.addEvent(ctx, r, l.cfg.IdentitiesTopic, models.EventTypeIdentitiesChanged, userID, ev); err != nil {
+			return err
+		}
+		return l.audit(ctx, r, models.IdentityEventMerged, userID, map[string]any{
+			"merged_user_id": guestID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	if err := l.RevokeAllSessions(ctx, guestID); err != nil {
+		return fmt.Errorf("revoke guest sessions: %w", err)
+	}
+
+	return nil
+}
+
+// publishIdentitiesChanged records an identities-changed event, both in the
+// outbox and in the audit log. Must be called within a pg transaction.
+func (l *Service) publishIdentitiesChanged(
+	ctx context.Context,
+	r PostgresRepos,
+	eventType string,
+	userID int64,
+	provider string,
+) error {
+	ev := models.IdentitiesChangedEvent{
+		Type:     eventType,
+		UserID:   userID,
+		Provider: provider,
+	}
+	if err := l.addEvent(ctx, r, l.cfg.IdentitiesTopic, models.EventTypeIdentitiesChanged, userID, ev); err != nil {
+		return err
+	}
+	return l.audit(ctx, r, eventType, userID, map[string]any{"provider": provider})
+}
@@ -2,6 +2,7 @@ package authsvc
 
 import (
 	"context"
+	"go-game-backend/pkg/outbox"
 	"go-game-backend/services/auth/internal/dto"
 	"time"
 
@@ -10,18 +11,90 @@ import (
 
 // UserRepository defines operations for managing users in persistent storage.
 type UserRepository interface {
-	AddUser(ctx context.Context, loginToken uuid.UUID) (int64, error)
-	FindUserByLoginToken(ctx context.Context, loginToken uuid.UUID) (int64, error)
+	AddPlayer(ctx context.Context) (int64, error)
+	LockPlayer(ctx context.Context, playerID int64) error
+	DeletePlayer(ctx context.Context, playerID int64) error
+	GetPlayer(ctx context.Context, playerID int64) (dto.Player, error)
+	RequestDeletion(ctx context.Context, playerID int64, deleteAfter time.Time) (time.Time, error)
+	CancelDeletion(ctx context.Context, playerID int64) error
+	ListPlayersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	DeleteDuePlayer(ctx context.Context, playerID int64, now time.Time) error
+}
+
+// IdentityRepository defines operations for managing the credentials players
+// sign in with.
+type IdentityRepository interface {
+	AddIdentity(ctx context.Context, playerID int64, provider, subject string) error
+	FindPlayerByIdentity(ctx context.Context, provider, subject string) (int64, error)
+	ListIdentities(ctx context.Context, playerID int64) ([]dto.Identity, error)
+	DeleteIdentity(ctx context.Context, playerID int64, provider, subject string) error
+	MoveIdentities(ctx context.Context, sourcePlayerID, targetPlayerID int64) error
+}
+
+// EmailRepository defines operations for managing email credentials and the
+// single-use tokens sent to those emails.
+type EmailRepository interface {
+	AddEmailCredential(ctx context.Context, playerID int64, email, passwordHash string) error
+	DeleteEmailCredential(ctx context.Context, playerID int64) error
+	FindEmailCredentialByEmail(ctx context.Context, email string) (dto.EmailCredential, error)
+	FindEmailCredentialByPlayer(ctx context.Context, playerID int64) (dto.EmailCredential, error)
+	MarkEmailVerified(ctx context.Context, playerID int64) error
+	UpdatePasswordHash(ctx context.Context, playerID int64, passwordHash string) error
+	AddEmailToken(ctx context.Context, tokenHash []byte, playerID int64, purpose string, expiresAt time.Time) error
+	ConsumeEmailToken(ctx context.Context, tokenHash []byte, purpose string) (int64, error)
+	DeleteEmailTokens(ctx context.Context, playerID int64, purpose string) error
+}
+
+// MFARepository defines operations for managing TOTP secrets and recovery
+// codes.
+type MFARepository interface {
+	UpsertTOTPSecret(ctx context.Context, playerID int64, secretEncrypted []byte) error
+	GetTOTP(ctx context.Context, playerID int64) (dto.TOTP, error)
+	ConfirmTOTP(ctx context.Context, playerID int64) error
+	UseTOTPStep(ctx context.Context, playerID, step int64) error
+	DeleteTOTP(ctx context.Context, playerID int64) error
+	AddRecoveryCodes(ctx context.Context, playerID int64, codeHashes [][]byte) error
+	UseRecoveryCode(ctx context.Context, playerID int64, codeHash []byte) error
+	DeleteRecoveryCodes(ctx context.Context, playerID int64) error
+}
+
+// BanRepository defines operations for managing player bans.
+type BanRepository interface {
+	AddBan(ctx context.Context, ban dto.Ban) (dto.Ban, error)
+	FindActiveBan(ctx context.Context, playerID int64) (dto.Ban, error)
+	LiftBans(ctx context.Context, playerID int64) error
+	ListBans(ctx context.Context, playerID int64) ([]dto.Ban, error)
+}
+
+// AuditRepository defines operations for the append-only audit log.
+type AuditRepository interface {
+	AddAuditEntry(ctx context.Context, entry dto.AuditEntry) error
+	ListAuditEntries(ctx context.Context, playerID int64, from, to time.Time, limit int) ([]dto.AuditEntry, error)
+	AnonymizeAuditEntries(ctx context.Context, playerID int64) error
+}
+
+// ServiceClientRepository defines operations for managing the internal
+// callers allowed to obtain service tokens.
+type ServiceClientRepository interface {
+	AddServiceClient(ctx context.Context, client dto.ServiceClient) error
+	FindServiceClient(ctx context.Context, clientID string) (dto.ServiceClient, error)
 }
 
 // OutboxRepository defines operations for working with outbox events.
 type OutboxRepository interface {
-	AddJSON(ctx context.Context, topic string, payload any) error
+	AddJSON(ctx context.Context, topic, key string, payload any, headers outbox.Headers) error
+	DeleteByKey(ctx context.Context, key string) error
 }
 
 // PostgresRepos aggregates repositories backed by PostgreSQL.
 type PostgresRepos interface {
 	User() UserRepository
+	Identity() IdentityRepository
+	Email() EmailRepository
+	MFA() MFARepository
+	Ban() BanRepository
+	Audit() AuditRepository
+	ServiceClient() ServiceClientRepository
 	Outbox() OutboxRepository
 }
 
@@ -33,15 +106,44 @@ type PostgresStore interface {
 
 // SessionRepository defines operations for managing authentication sessions.
 type SessionRepository interface {
-	SetSessionToken(ctx context.Context, userID int64, token uuid.UUID, expiresAt time.Time) error
+	SetSession(ctx context.Context, session dto.Session) error
+	GetSession(ctx context.Context, sessionToken uuid.UUID) (dto.Session, error)
+	ListSessions(ctx context.Context, userID int64) ([]dto.Session, error)
+	TouchSession(ctx context.Context, sessionToken uuid.UUID, ip string, at time.Time) error
+	RemoveSession(ctx context.Context, userID int64, sessionToken uuid.UUID) error
 	SetRefreshToken(ctx context.Context, token uuid.UUID, sessionInfo dto.SessionInfo, expiresAt time.Time) error
-	RemoveRefreshToken(ctx context.Context, token uuid.UUID) error
+	RemoveRefreshToken(ctx context.Context, userID int64, token uuid.UUID) error
 	GetSessionInfo(ctx context.Context, refreshToken uuid.UUID) (dto.SessionInfo, error)
+	MarkRefreshTokenRotated(ctx context.Context, token uuid.UUID) error
+	GetUserRefreshTokens(ctx context.Context, userID int64) ([]uuid.UUID, error)
+	GetSessionRefreshTokens(ctx context.Context, sessionToken uuid.UUID) ([]uuid.UUID, error)
+	RemoveSessionRefreshTokens(ctx context.Context, sessionToken uuid.UUID) error
+}
+
+// MFAChallengeRepository defines operations for managing logins waiting for
+// a second factor.
+type MFAChallengeRepository interface {
+	SetMFAChallenge(ctx context.Context, tokenHash string, userID int64, expiresAt time.Time) error
+	GetMFAChallenge(ctx context.Context, tokenHash string) (dto.MFAChallenge, error)
+	IncrMFAChallengeAttempts(ctx context.Context, tokenHash string) (int64, error)
+	RemoveMFAChallenge(ctx context.Context, tokenHash string) error
+}
+
+// TransferCodeRepository defines operations for managing the codes players
+// generate to move their account to another device.
+type TransferCodeRepository interface {
+	SetTransferCode(ctx context.Context, codeHash string, userID int64, expiresAt time.Time) error
+	GetTransferCode(ctx context.Context, codeHash string) (int64, error)
+	GetUserTransferCode(ctx context.Context, userID int64) (string, error)
+	ConsumeTransferCode(ctx context.Context, codeHash string) (int64, error)
+	RemoveTransferCode(ctx context.Context, userID int64, codeHash string) error
 }
 
 // RedisRepos aggregates repositories backed by Redis.
 type RedisRepos interface {
 	Session() SessionRepository
+	MFAChallenge() MFAChallengeRepository
+	TransferCode() TransferCodeRepository
 }
 
 // RedisStore provides transactional access to Redis repositories.
@@ -0,0 +1,278 @@
+package authsvc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Transfer codes are made of Crockford base32 symbols, which leave out
+// letters easily mistaken for digits, and shown in groups separated by
+// dashes.
+const (
+	transferCodeAlphabet  = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	transferCodeLength    = 12
+	transferCodeGroupSize = 4
+)
+
+// transferCodeReplacer maps what players may type for a transfer code to its
+// symbols, as Crockford base32 decoding does.
+var transferCodeReplacer = strings.NewReplacer(
+	"-", "", " ", "",
+	"O", "0", "I", "1", "L", "1",
+)
+
+// RotateLoginToken replaces the guest login token of the user with a new
+// one, so that a token that may have leaked off the device stops working.
+// Other guest tokens of the user, linked on other devices, are kept.
+func (l *Service) RotateLoginToken(
+	ctx context.Context,
+	userID int64,
+	req *models.RotateLoginTokenRequest,
+) (*models.LoginTokenResponse, error) {
+	loginToken := uuid.New()
+
+	err := l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+			err := r.Identity().DeleteIdentity(ctx, userID, models.IdentityProviderGuest, req.LoginToken.String())
+			if errors.Is(err, services.ErrNotFound) {
+				return fmt.Errorf("delete identity: %w", services.ErrIdentityNotFound)
+			}
+			if err != nil {
+				return fmt.Errorf("delete identity: %w", err)
+			}
+			err = l.publishIdentitiesChanged(ctx, r, models.IdentityEventUnlinked, userID, models.IdentityProviderGuest)
+			if err != nil {
+				return err
+			}
+
+			err = r.Identity().AddIdentity(ctx, userID, models.IdentityProviderGuest, loginToken.String())
+			if err != nil {
+				return fmt.Errorf("add identity: %w", err)
+			}
+			err = l.publishIdentitiesChanged(ctx, r, models.IdentityEventLinked, userID, models.IdentityProviderGuest)
+			if err != nil {
+				return err
+			}
+
+			return l.audit(ctx, r, models.AuditEventLoginTokenRotated, userID, nil)
+		})
+		if err != nil {
+			return fmt.Errorf("pg transaction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginTokenResponse{LoginToken: loginToken}, nil
+}
+
+// CreateTransferCode issues a short-lived code the user can type in on
+// another device to move the account there. Issuing a code invalidates the
+// previous one.
+func (l *Service) CreateTransferCode(ctx context.Context, userID int64) (*models.TransferCodeResponse, error) {
+	code, err := newTransferCode()
+	if err != nil {
+		return nil, err
+	}
+	codeHash := hashTransferCode(code)
+	expiresAt := time.Now().UTC().Add(l.cfg.TransferCodeTTL)
+
+	err = l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		previous, err := l.rxStore.Raw().TransferCode().GetUserTransferCode(ctx, userID)
+		if err != nil && !errors.Is(err, services.ErrNotFound) {
+			return fmt.Errorf("get user transfer code: %w", err)
+		}
+
+		err = l.rxStore.DoTx(ctx, func(ctx context.Context, r RedisRepos) error {
+			if previous != "" {
+				if err := r.TransferCode().RemoveTransferCode(ctx, userID, previous); err != nil {
+					return fmt.Errorf("remove transfer code: %w", err)
+				}
+			}
+			if err := r.TransferCode().SetTransferCode(ctx, codeHash, userID, expiresAt); err != nil {
+				return fmt.Errorf("set transfer code: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("rx transaction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.auditCompleted(ctx, models.AuditEventTransferCodeIssued, userID, map[string]any{
+		"expires_at": expiresAt.Unix(),
+	})
+
+	return &models.TransferCodeResponse{
+		Code:          formatTransferCode(code),
+		ExpiresAtUnix: expiresAt.Unix(),
+	}, nil
+}
+
+// RedeemTransferCode moves the account the code was issued for to the device
+// making the request. The guest login tokens of the account are replaced
+// with a new one and every session is revoked, so the old device is signed
+// out, and the new device is signed in as with any other login. The code is
+// consumed only once the account is locked and known not to be banned, so a
+// refused attempt leaves it valid. Once the tokens are replaced the new login
+// token is returned even if signing in fails, without the session; the device
+// then signs in with it as usual.
+func (l *Service) RedeemTransferCode(
+	ctx context.Context,
+	req *models.RedeemTransferCodeRequest,
+) (*models.TransferResponse, error) {
+	code := transferCodeReplacer.Replace(strings.ToUpper(req.Code))
+	codeHash := hashTransferCode(code)
+	userID, err := l.rxStore.Raw().TransferCode().GetTransferCode(ctx, codeHash)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, fmt.Errorf("get transfer code: %w", services.ErrInvalidTransferCode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get transfer code: %w", err)
+	}
+
+	loginToken := uuid.New()
+	err = l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		// A banned account would be moved only to be refused at sign-in.
+		if err := l.checkNotBanned(ctx, userID); err != nil {
+			return err
+		}
+
+		_, err := l.rxStore.Raw().TransferCode().ConsumeTransferCode(ctx, codeHash)
+		if errors.Is(err, services.ErrNotFound) {
+			return fmt.Errorf("consume transfer code: %w", services.ErrInvalidTransferCode)
+		}
+		if err != nil {
+			return fmt.Errorf("consume transfer code: %w", err)
+		}
+
+		err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+			// The row lock keeps the deletion purge from deleting the player
+			// while its tokens are replaced.
+			err := r.User().LockPlayer(ctx, userID)
+			if errors.Is(err, services.ErrNotFound) {
+				return fmt.Errorf("lock player: %w", services.ErrInvalidTransferCode)
+			}
+			if err != nil {
+				return fmt.Errorf("lock player: %w", err)
+			}
+
+			replaced, err := l.replaceLoginTokens(ctx, r, userID, loginToken)
+			if err != nil {
+				return err
+			}
+			return l.audit(ctx, r, models.AuditEventAccountTransferred, userID, map[string]any{
+				"replaced": replaced,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("pg transaction: %w", err)
+		}
+
+		return l.revokeAllSessions(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.TransferResponse{LoginToken: loginToken}
+	login, err := l.completeLogin(ctx, userID, map[string]any{"provider": models.IdentityProviderGuest})
+	if err != nil {
+		l.logger.WarnCtx(ctx, "sign in after account transfer",
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return resp, nil
+	}
+	resp.LoginRespose = *login
+
+	return resp, nil
+}
+
+// replaceLoginTokens removes every guest login token of the user and adds
+// loginToken instead, returning how many tokens were replaced. Each change is
+// published as an identities-changed event. Must be called within a pg
+// transaction.
+func (l *Service) replaceLoginTokens(
+	ctx context.Context,
+	r PostgresRepos,
+	userID int64,
+	loginToken uuid.UUID,
+) (int, error) {
+	identities, err := r.Identity().ListIdentities(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("list identities: %w", err)
+	}
+	replaced := 0
+	for _, identity := range identities {
+		if identity.Provider != models.IdentityProviderGuest {
+			continue
+		}
+		if err := r.Identity().DeleteIdentity(ctx, userID, identity.Provider, identity.Subject); err != nil {
+			return 0, fmt.Errorf("delete identity: %w", err)
+		}
+		err := l.publishIdentitiesChanged(ctx, r, models.IdentityEventUnlinked, userID, identity.Provider)
+		if err != nil {
+			return 0, err
+		}
+		replaced++
+	}
+
+	err = r.Identity().AddIdentity(ctx, userID, models.IdentityProviderGuest, loginToken.String())
+	if err != nil {
+		return 0, fmt.Errorf("add identity: %w", err)
+	}
+
+	err = l.publishIdentitiesChanged(ctx, r, models.IdentityEventLinked, userID, models.IdentityProviderGuest)
+	if err != nil {
+		return 0, err
+	}
+	return replaced, nil
+}
+
+// newTransferCode returns a random transfer code without dashes.
+func newTransferCode() (string, error) {
+	b := make([]byte, transferCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read random bytes: %w", err)
+	}
+	// The alphabet has 32 symbols, so the low five bits pick one uniformly.
+	for i := range b {
+		b[i] = transferCodeAlphabet[b[i]&31]
+	}
+	return string(b), nil
+}
+
+// formatTransferCode splits a code into dash separated groups for display.
+func formatTransferCode(code string) string {
+	groups := make([]string, 0, len(code)/transferCodeGroupSize+1)
+	for len(code) > transferCodeGroupSize {
+		groups = append(groups, code[:transferCodeGroupSize])
+		code = code[transferCodeGroupSize:]
+	}
+	groups = append(groups, code)
+	return strings.Join(groups, "-")
+}
+
+// hashTransferCode returns the digest under which a transfer code is stored.
+func hashTransferCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,119 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// register creates a guest player and returns its ID and login token.
+func (e *testEnv) register(t *testing.T) (int64, uuid.UUID) {
+	t.Helper()
+	loginToken := uuid.New()
+	resp, err := e.svc.Register(context.Background(), &models.RegisterRequest{LoginToken: loginToken})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	return e.userOf(t, resp), loginToken
+}
+
+func TestRedeemTransferCode(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// prepare runs after the code is issued and returns a function
+		// undoing what it did.
+		prepare    func(t *testing.T, env *testEnv, userID int64) func()
+		wantErr    error
+		wantMoved  bool
+		wantSigned bool
+	}{
+		{
+			name:       "moves the account and signs in",
+			wantMoved:  true,
+			wantSigned: true,
+		},
+		{
+			name: "locked player keeps the code",
+			prepare: func(_ *testing.T, env *testEnv, userID int64) func() {
+				env.locker.setLocked(userID, true)
+				return func() { env.locker.setLocked(userID, false) }
+			},
+			wantErr: services.ErrPlayerLocked,
+		},
+		{
+			name: "banned player keeps the code",
+			prepare: func(t *testing.T, env *testEnv, userID int64) func() {
+				_, err := env.svc.BanPlayer(ctx, &models.BanPlayerRequest{UserID: userID, Reason: "cheating"})
+				if err != nil {
+					t.Fatalf("ban player: %v", err)
+				}
+				return func() {
+					if err := env.svc.UnbanPlayer(ctx, userID); err != nil {
+						t.Fatalf("unban player: %v", err)
+					}
+				}
+			},
+			wantErr: services.ErrPlayerBanned,
+		},
+		{
+			name: "failed sign-in still returns the login token",
+			prepare: func(_ *testing.T, env *testEnv, _ int64) func() {
+				env.rx.setSessionErr = errors.New("redis is down")
+				return func() {}
+			},
+			wantMoved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			userID, oldToken := env.register(t)
+
+			code, err := env.svc.CreateTransferCode(ctx, userID)
+			if err != nil {
+				t.Fatalf("create transfer code: %v", err)
+			}
+			undo := func() {}
+			if tt.prepare != nil {
+				undo = tt.prepare(t, env, userID)
+			}
+
+			resp, err := env.svc.RedeemTransferCode(ctx, &models.RedeemTransferCodeRequest{Code: code.Code})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("redeem err = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				// The code was not burnt by the refused attempt.
+				undo()
+				if _, err := env.svc.RedeemTransferCode(ctx, &models.RedeemTransferCodeRequest{Code: code.Code}); err != nil {
+					t.Fatalf("redeem after refusal: %v", err)
+				}
+				return
+			}
+
+			if tt.wantMoved {
+				if _, err := env.pg.FindPlayerByIdentity(ctx, models.IdentityProviderGuest, oldToken.String()); !errors.Is(err, services.ErrNotFound) {
+					t.Fatalf("old login token still bound, err = %v", err)
+				}
+				got, err := env.pg.FindPlayerByIdentity(ctx, models.IdentityProviderGuest, resp.LoginToken.String())
+				if err != nil || got != userID {
+					t.Fatalf("new login token bound to %d (err %v), want %d", got, err, userID)
+				}
+			}
+			if signed := resp.AccessToken != ""; signed != tt.wantSigned {
+				t.Fatalf("signed in = %v, want %v", signed, tt.wantSigned)
+			}
+			if _, err := env.svc.RedeemTransferCode(ctx, &models.RedeemTransferCodeRequest{Code: code.Code}); !errors.Is(err, services.ErrInvalidTransferCode) {
+				t.Fatalf("second redeem err = %v, want %v", err, services.ErrInvalidTransferCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package authsvc
+
+import (
+	"context"
+	"fmt"
+	"go-game-backend/services/auth/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// Logout revokes the session the refresh token from the request belongs to,
+// including every refresh token issued within it.
+func (l *Service) Logout(ctx context.Context, req *models.LogoutRequest) error {
+	sessionInfo, err := l.getSessionInfo(ctx, req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	err = l.doWithPlayerLock(ctx, sessionInfo.UserID, func(ctx context.Context) error {
+		return l.revokeSession(ctx, sessionInfo.UserID, sessionInfo.SessionToken)
+	})
+	if err != nil {
+		return err
+	}
+
+	l.auditCompleted(ctx, models.AuditEventLoggedOut, sessionInfo.UserID, map[string]any{
+		"session_token": sessionInfo.SessionToken,
+	})
+	return nil
+}
+
+// LogoutAll revokes every session of the user the refresh token from the
+// request belongs to.
+func (l *Service) LogoutAll(ctx context.Context, req *models.LogoutRequest) error {
+	sessionInfo, err := l.getSessionInfo(ctx, req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	if err := l.RevokeAllSessions(ctx, sessionInfo.UserID); err != nil {
+		return err
+	}
+
+	l.auditCompleted(ctx, models.AuditEventLoggedOutAll, sessionInfo.UserID, nil)
+	return nil
+}
+
+// RevokeAllSessions removes every session and refresh token of the given
+// user.
+func (l *Service) RevokeAllSessions(ctx context.Context, userID int64) error {
+	return l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		return l.revokeAllSessions(ctx, userID)
+	})
+}
+
+// revokeAllSessions is RevokeAllSessions for callers already holding the
+// player lock.
+func (l *Service) revokeAllSessions(ctx context.Context, userID int64) error {
+	sessions, err := l.rxStore.Raw().Session().ListSessions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+
+	refreshTokens, err := l.rxStore.Raw().Session().GetUserRefreshTokens(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user refresh tokens: %w", err)
+	}
+
+	err = l.rxStore.DoTx(ctx, func(ctx context.Context, r RedisRepos) error {
+		for _, token := range refreshTokens {
+			if err := r.Session().RemoveRefreshToken(ctx, userID, token); err != nil {
+				return fmt.Errorf("remove refresh token: %w", err)
+			}
+		}
+
+		for _, session := range sessions {
+			if err := r.Session().RemoveSessionRefreshTokens(ctx, session.Token); err != nil {
+				return fmt.Errorf("remove session refresh tokens: %w", err)
+			}
+			if err := r.Session().RemoveSession(ctx, userID, session.Token); err != nil {
+				return fmt.Errorf("remove session: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("rx transaction: %w", err)
+	}
+	return nil
+}
+
+// revokeSession removes the session together with every refresh token issued
+// within it. Must be called while holding the player lock.
+func (l *Service) revokeSession(ctx context.Context, userID int64, sessionToken uuid.UUID) error {
+	refreshTokens, err := l.rxStore.Raw().Session().GetSessionRefreshTokens(ctx, sessionToken)
+	if err != nil {
+		return fmt.Errorf("get session refresh tokens: %w", err)
+	}
+
+	err = l.rxStore.DoTx(ctx, func(ctx context.Context, r RedisRepos) error {
+		for _, token := range refreshTokens {
+			if err := r.Session().RemoveRefreshToken(ctx, userID, token); err != nil {
+				return fmt.Errorf("remove refresh token: %w", err)
+			}
+		}
+
+		if err := r.Session().RemoveSessionRefreshTokens(ctx, sessionToken); err != nil {
+			return fmt.Errorf("remove session refresh tokens: %w", err)
+		}
+
+		if err := r.Session().RemoveSession(ctx, userID, sessionToken); err != nil {
+			return fmt.Errorf("remove session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("rx transaction: %w", err)
+	}
+	return nil
+}
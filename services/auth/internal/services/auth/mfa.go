@@ -0,0 +1,371 @@
+package authsvc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/internal/services/totp"
+	"go-game-backend/services/auth/pkg/models"
+	"strings"
+	"time"
+)
+
+const (
+	// totpSkew is the number of time steps a code may drift from the server
+	// clock in either direction.
+	totpSkew = 1
+
+	recoveryCodesCount = 10
+	// recoveryCodeBytes yields 16 base32 characters per code.
+	recoveryCodeBytes = 10
+	mfaTokenBytes     = 32
+)
+
+type secretBox interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(sealed []byte) ([]byte, error)
+}
+
+// EnrollTOTP generates a new TOTP secret for the user. The secret stays
+// inactive until it is confirmed with ConfirmTOTP.
+func (l *Service) EnrollTOTP(ctx context.Context, userID int64) (*models.TOTPEnrollResponse, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := l.secretBox.Seal(secret)
+	if err != nil {
+		return nil, fmt.Errorf("seal totp secret: %w", err)
+	}
+
+	err = l.pgStore.Raw().MFA().UpsertTOTPSecret(ctx, userID, sealed)
+	if errors.Is(err, services.ErrAlreadyExists) {
+		return nil, fmt.Errorf("upsert totp secret: %w", services.ErrMFAAlreadyEnabled)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("upsert totp secret: %w", err)
+	}
+
+	account, err := l.totpAccountName(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TOTPEnrollResponse{
+		Secret:          totp.Encoding.EncodeToString(secret),
+		ProvisioningURI: totp.ProvisioningURI(l.cfg.MFAIssuer, account, secret),
+	}, nil
+}
+
+// ConfirmTOTP activates the enrolled TOTP secret once the user proves the
+// authenticator app produces valid codes, and returns a fresh set of
+// one-time recovery codes.
+func (l *Service) ConfirmTOTP(
+	ctx context.Context,
+	userID int64,
+	req *models.TOTPConfirmRequest,
+) (*models.RecoveryCodesResponse, error) {
+	codes, codeHashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		secret, err := l.getTOTPSecret(ctx, r, userID, false)
+		if err != nil {
+			return err
+		}
+
+		if err := l.validateTOTPCode(ctx, r, userID, secret, req.Code); err != nil {
+			return err
+		}
+
+		if err := r.MFA().ConfirmTOTP(ctx, userID); err != nil {
+			return fmt.Errorf("confirm totp: %w", err)
+		}
+
+		if err := r.MFA().DeleteRecoveryCodes(ctx, userID); err != nil {
+			return fmt.Errorf("delete recovery codes: %w", err)
+		}
+		if err := r.MFA().AddRecoveryCodes(ctx, userID, codeHashes); err != nil {
+			return fmt.Errorf("add recovery codes: %w", err)
+		}
+
+		return l.publishSecurityEvent(ctx, r, models.SecurityEventMFAEnabled, userID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP removes the TOTP secret and recovery codes of the user. A
+// valid code or recovery code is required so that a stolen access token
+// alone cannot turn the second factor off.
+func (l *Service) DisableTOTP(ctx context.Context, userID int64, req *models.MFACodeRequest) error {
+	err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		if err := l.verifySecondFactor(ctx, r, userID, req); err != nil {
+			return err
+		}
+
+		if err := r.MFA().DeleteTOTP(ctx, userID); err != nil {
+			return fmt.Errorf("delete totp: %w", err)
+		}
+		if err := r.MFA().DeleteRecoveryCodes(ctx, userID); err != nil {
+			return fmt.Errorf("delete recovery codes: %w", err)
+		}
+
+		return l.publishSecurityEvent(ctx, r, models.SecurityEventMFADisabled, userID)
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyMFA completes a login started with a first factor. The session is
+// started once a valid code or recovery code is presented for the MFA token.
+// The challenge is dropped after too many rejected codes.
+func (l *Service) VerifyMFA(ctx context.Context, req *models.MFAVerifyRequest) (*models.LoginRespose, error) {
+	tokenHash := hashMFAToken(req.MFAToken)
+
+	challenge, err := l.rxStore.Raw().MFAChallenge().GetMFAChallenge(ctx, tokenHash)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, fmt.Errorf("get mfa challenge: %w", services.ErrInvalidMFAToken)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get mfa challenge: %w", err)
+	}
+
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		return l.verifySecondFactor(ctx, r, challenge.UserID, &req.MFACodeRequest)
+	})
+	details := map[string]any{"second_factor": secondFactorName(&req.MFACodeRequest)}
+	if errors.Is(err, services.ErrInvalidMFACode) {
+		if err := l.countRejectedMFACode(ctx, tokenHash); err != nil {
+			return nil, err
+		}
+		err = fmt.Errorf("pg transaction: %w", services.ErrInvalidMFACode)
+		return nil, l.auditLoginFailure(ctx, challenge.UserID, loginFailureInvalidMFACode, details, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pg transaction: %w", err)
+	}
+
+	// Removing the challenge claims it, so concurrent verifications cannot
+	// start more than one session.
+	err = l.rxStore.Raw().MFAChallenge().RemoveMFAChallenge(ctx, tokenHash)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, fmt.Errorf("remove mfa challenge: %w", services.ErrInvalidMFAToken)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("remove mfa challenge: %w", err)
+	}
+
+	return l.startLoginSession(ctx, challenge.UserID, details)
+}
+
+// completeLogin starts a session for a user who passed the first factor, or
+// returns an MFA challenge when the user has a confirmed TOTP secret. The
+// details describe the first factor in the audit log.
+func (l *Service) completeLogin(
+	ctx context.Context,
+	userID int64,
+	details map[string]any,
+) (*models.LoginRespose, error) {
+	t, err := l.pgStore.Raw().MFA().GetTOTP(ctx, userID)
+	if err != nil && !errors.Is(err, services.ErrNotFound) {
+		return nil, fmt.Errorf("get totp: %w", err)
+	}
+	if err == nil && t.Confirmed {
+		resp, err := l.startMFAChallenge(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		l.auditCompleted(ctx, models.AuditEventMFAChallenged, userID, details)
+		return resp, nil
+	}
+
+	return l.startLoginSession(ctx, userID, details)
+}
+
+func (l *Service) startMFAChallenge(ctx context.Context, userID int64) (*models.LoginRespose, error) {
+	token, err := newRandomToken(mfaTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("generate mfa token: %w", err)
+	}
+	expiresAt := time.Now().UTC().Add(l.cfg.MFAChallengeTTL)
+
+	err = l.rxStore.Raw().MFAChallenge().SetMFAChallenge(ctx, hashMFAToken(token), userID, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("set mfa challenge: %w", err)
+	}
+
+	return &models.LoginRespose{
+		MFAChallenge: &models.MFAChallengeResponse{
+			MFAToken:      token,
+			ExpiresAtUnix: expiresAt.Unix(),
+		},
+	}, nil
+}
+
+func (l *Service) countRejectedMFACode(ctx context.Context, tokenHash string) error {
+	attempts, err := l.rxStore.Raw().MFAChallenge().IncrMFAChallengeAttempts(ctx, tokenHash)
+	if err != nil {
+		return fmt.Errorf("increment mfa challenge attempts: %w", err)
+	}
+	if attempts < l.cfg.MFAMaxAttempts {
+		return nil
+	}
+
+	err = l.rxStore.Raw().MFAChallenge().RemoveMFAChallenge(ctx, tokenHash)
+	if err != nil && !errors.Is(err, services.ErrNotFound) {
+		return fmt.Errorf("remove mfa challenge: %w", err)
+	}
+	return nil
+}
+
+// verifySecondFactor checks the TOTP code or, when no code is given, the
+// recovery code of the user. Accepted codes are consumed. Must be called
+// within a pg transaction.
+func (l *Service) verifySecondFactor(
+	ctx context.Context,
+	r PostgresRepos,
+	userID int64,
+	req *models.MFACodeRequest,
+) error {
+	if req.Code != "" {
+		secret, err := l.getTOTPSecret(ctx, r, userID, true)
+		if err != nil {
+			return err
+		}
+		return l.validateTOTPCode(ctx, r, userID, secret, req.Code)
+	}
+
+	if req.RecoveryCode == "" {
+		return fmt.Errorf("verify second factor: %w", services.ErrInvalidMFACode)
+	}
+
+	err := r.MFA().UseRecoveryCode(ctx, userID, hashRecoveryCode(req.RecoveryCode))
+	if errors.Is(err, services.ErrNotFound) {
+		return fmt.Errorf("use recovery code: %w", services.ErrInvalidMFACode)
+	}
+	if err != nil {
+		return fmt.Errorf("use recovery code: %w", err)
+	}
+	return nil
+}
+
+// getTOTPSecret returns the decrypted TOTP secret of the user. confirmed
+// selects whether an active or a pending secret is expected.
+func (l *Service) getTOTPSecret(ctx context.Context, r PostgresRepos, userID int64, confirmed bool) ([]byte, error) {
+	t, err := r.MFA().GetTOTP(ctx, userID)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, fmt.Errorf("get totp: %w", services.ErrMFANotEnrolled)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get totp: %w", err)
+	}
+
+	switch {
+	case confirmed && !t.Confirmed:
+		return nil, fmt.Errorf("get totp: %w", services.ErrMFANotEnrolled)
+	case !confirmed && t.Confirmed:
+		return nil, fmt.Errorf("get totp: %w", services.ErrMFAAlreadyEnabled)
+	}
+
+	secret, err := l.secretBox.Open(t.SecretEncrypted)
+	if err != nil {
+		return nil, fmt.Errorf("open totp secret: %w", err)
+	}
+	return secret, nil
+}
+
+// validateTOTPCode checks the code and records its time step so the same
+// code cannot be used twice.
+func (l *Service) validateTOTPCode(ctx context.Context, r PostgresRepos, userID int64, secret []byte, code string) error {
+	step, ok := totp.Validate(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return fmt.Errorf("validate totp code: %w", services.ErrInvalidMFACode)
+	}
+
+	err := r.MFA().UseTOTPStep(ctx, userID, step)
+	if errors.Is(err, services.ErrAlreadyExists) {
+		return fmt.Errorf("use totp step: %w", services.ErrInvalidMFACode)
+	}
+	if err != nil {
+		return fmt.Errorf("use totp step: %w", err)
+	}
+	return nil
+}
+
+// totpAccountName returns the account label shown in authenticator apps.
+func (l *Service) totpAccountName(ctx context.Context, userID int64) (string, error) {
+	cred, err := l.pgStore.Raw().Email().FindEmailCredentialByPlayer(ctx, userID)
+	if errors.Is(err, services.ErrNotFound) {
+		return fmt.Sprintf("player-%d", userID), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("find email credential: %w", err)
+	}
+	return cred.Email, nil
+}
+
+func (l *Service) publishSecurityEvent(ctx context.Context, r PostgresRepos, eventType string, userID int64) error {
+	ev := models.SecurityEvent{
+		Type:           eventType,
+		UserID:         userID,
+		OccurredAtUnix: time.Now().UTC().Unix(),
+	}
+	if err := l.addEvent(ctx, r, l.cfg.SecurityEventsTopic, models.EventTypeSecurity, userID, ev); err != nil {
+		return err
+	}
+	return l.audit(ctx, r, eventType, userID, nil)
+}
+
+// secondFactorName names the second factor presented in req for the audit
+// log.
+func secondFactorName(req *models.MFACodeRequest) string {
+	if req.Code != "" {
+		return "totp"
+	}
+	return "recovery_code"
+}
+
+// newRecoveryCodes returns recovery codes formatted for display together
+// with the hashes they are stored under.
+func newRecoveryCodes() ([]string, [][]byte, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([][]byte, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		code := strings.ToLower(totp.Encoding.EncodeToString(b))
+		code = code[:len(code)/2] + "-" + code[len(code)/2:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode returns the digest a recovery code is stored under. Case
+// and separators are ignored so codes can be typed loosely.
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
+
+func hashMFAToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
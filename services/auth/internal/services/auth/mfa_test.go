@@ -0,0 +1,160 @@
+package authsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// enableMFA gives the player a confirmed TOTP secret and returns its
+// recovery codes.
+func (e *testEnv) enableMFA(t *testing.T, userID int64) []string {
+	t.Helper()
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatalf("new recovery codes: %v", err)
+	}
+	e.pg.mu.Lock()
+	e.pg.data.totps[userID] = dto.TOTP{SecretEncrypted: []byte("secret"), Confirmed: true}
+	e.pg.mu.Unlock()
+	if err := e.pg.AddRecoveryCodes(context.Background(), userID, hashes); err != nil {
+		t.Fatalf("add recovery codes: %v", err)
+	}
+	return codes
+}
+
+// challenge signs in with the login token and returns the MFA token of the
+// challenge it must answer.
+func (e *testEnv) challenge(t *testing.T, loginToken uuid.UUID) string {
+	t.Helper()
+	resp, err := e.svc.Login(context.Background(), &models.LoginRequest{LoginToken: loginToken})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if resp.MFAChallenge == nil {
+		t.Fatal("login returned no mfa challenge")
+	}
+	return resp.MFAChallenge.MFAToken
+}
+
+func TestLoginWithMFAReturnsChallenge(t *testing.T) {
+	env := newTestEnv(t)
+	userID, loginToken := env.register(t)
+	env.enableMFA(t, userID)
+
+	before := time.Now()
+	resp, err := env.svc.Login(context.Background(), &models.LoginRequest{LoginToken: loginToken})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	if resp.MFAChallenge == nil || resp.MFAChallenge.MFAToken == "" {
+		t.Fatalf("mfa challenge = %+v, want a token", resp.MFAChallenge)
+	}
+	expiresAt := time.Unix(resp.MFAChallenge.ExpiresAtUnix, 0)
+	earliest := before.Add(env.svc.cfg.MFAChallengeTTL).Truncate(time.Second)
+	if expiresAt.Before(earliest) || expiresAt.After(time.Now().Add(env.svc.cfg.MFAChallengeTTL)) {
+		t.Errorf("challenge expires at %v, want the challenge TTL from now", expiresAt)
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(fields) != 1 || fields["mfa_challenge"] == nil {
+		t.Errorf("challenge response = %s, want only mfa_challenge", body)
+	}
+}
+
+func TestVerifyMFAAttemptLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		rejected int
+		wantErr  error
+	}{
+		{name: "below the limit", rejected: 2},
+		{name: "at the limit", rejected: 3, wantErr: services.ErrInvalidMFAToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID, loginToken := env.register(t)
+			codes := env.enableMFA(t, userID)
+			mfaToken := env.challenge(t, loginToken)
+
+			for range tt.rejected {
+				_, err := env.svc.VerifyMFA(ctx, &models.MFAVerifyRequest{
+					MFAToken:       mfaToken,
+					MFACodeRequest: models.MFACodeRequest{RecoveryCode: "wrong-code"},
+				})
+				if !errors.Is(err, services.ErrInvalidMFACode) {
+					t.Fatalf("verify with a wrong code = %v, want %v", err, services.ErrInvalidMFACode)
+				}
+			}
+
+			resp, err := env.svc.VerifyMFA(ctx, &models.MFAVerifyRequest{
+				MFAToken:       mfaToken,
+				MFACodeRequest: models.MFACodeRequest{RecoveryCode: codes[0]},
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verify = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := env.userOf(t, resp); got != userID {
+				t.Errorf("signed in to user %d, want %d", got, userID)
+			}
+		})
+	}
+}
+
+func TestVerifyMFAConsumesRecoveryCode(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID, loginToken := env.register(t)
+	codes := env.enableMFA(t, userID)
+
+	resp, err := env.svc.VerifyMFA(ctx, &models.MFAVerifyRequest{
+		MFAToken:       env.challenge(t, loginToken),
+		MFACodeRequest: models.MFACodeRequest{RecoveryCode: codes[0]},
+	})
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if resp.AccessToken == "" || resp.MFAChallenge != nil {
+		t.Fatalf("verify returned %+v, want tokens", resp)
+	}
+
+	mfaToken := env.challenge(t, loginToken)
+	_, err = env.svc.VerifyMFA(ctx, &models.MFAVerifyRequest{
+		MFAToken:       mfaToken,
+		MFACodeRequest: models.MFACodeRequest{RecoveryCode: codes[0]},
+	})
+	if !errors.Is(err, services.ErrInvalidMFACode) {
+		t.Fatalf("verify with a used code = %v, want %v", err, services.ErrInvalidMFACode)
+	}
+
+	// recovery codes may be typed loosely
+	loose := strings.ToUpper(strings.ReplaceAll(codes[1], "-", " "))
+	_, err = env.svc.VerifyMFA(ctx, &models.MFAVerifyRequest{
+		MFAToken:       mfaToken,
+		MFACodeRequest: models.MFACodeRequest{RecoveryCode: loose},
+	})
+	if err != nil {
+		t.Fatalf("verify with another code: %v", err)
+	}
+}
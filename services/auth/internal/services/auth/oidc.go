@@ -0,0 +1,122 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/internal/services/oidc"
+	"go-game-backend/services/auth/pkg/models"
+)
+
+type idTokenVerifier interface {
+	Verify(ctx context.Context, provider, rawToken string) (*oidc.Claims, error)
+}
+
+// LoginOIDC signs in with an ID token issued by an external OpenID Connect
+// provider. A new user is created on the first sign-in with the provider
+// account. Users with a second factor receive an MFA challenge instead of a
+// session.
+func (l *Service) LoginOIDC(ctx context.Context, req *models.OIDCLoginRequest) (resp *models.LoginRespose, err error) {
+	provider := models.OIDCIdentityProvider(req.Provider)
+	details := map[string]any{"provider": provider}
+
+	claims, err := l.verifyIDToken(ctx, req)
+	if errors.Is(err, services.ErrValidationCredentials) {
+		return nil, l.auditLoginFailure(ctx, 0, loginFailureInvalidIDToken, details, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := l.findOrCreateByIdentity(ctx, provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.completeLogin(ctx, userID, details)
+}
+
+// LinkOIDC binds an external provider account to the user.
+func (l *Service) LinkOIDC(ctx context.Context, userID int64, req *models.OIDCLoginRequest) error {
+	claims, err := l.verifyIDToken(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	provider := models.OIDCIdentityProvider(req.Provider)
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		err := r.Identity().AddIdentity(ctx, userID, provider, claims.Subject)
+		if errors.Is(err, services.ErrAlreadyExists) {
+			return fmt.Errorf("add identity: %w", services.ErrIdentityTaken)
+		}
+		if err != nil {
+			return fmt.Errorf("add identity: %w", err)
+		}
+
+		return l.publishIdentitiesChanged(ctx, r, models.IdentityEventLinked, userID, provider)
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (l *Service) verifyIDToken(ctx context.Context, req *models.OIDCLoginRequest) (*oidc.Claims, error) {
+	claims, err := l.idTokenVerifier.Verify(ctx, req.Provider, req.IDToken)
+	switch {
+	case err == nil:
+		return claims, nil
+	case errors.Is(err, oidc.ErrUnknownProvider):
+		return nil, fmt.Errorf("verify id token: %w: %w", services.ErrUnknownProvider, err)
+	case errors.Is(err, oidc.ErrInvalidIDToken):
+		return nil, fmt.Errorf("verify id token: %w: %w", services.ErrValidationCredentials, err)
+	case errors.Is(err, oidc.ErrProviderUnavailable):
+		return nil, fmt.Errorf("verify id token: %w: %w", services.ErrProviderUnavailable, err)
+	default:
+		return nil, fmt.Errorf("verify id token: %w", err)
+	}
+}
+
+// findOrCreateByIdentity returns the user owning the identity, creating one
+// when the identity is seen for the first time.
+func (l *Service) findOrCreateByIdentity(ctx context.Context, provider, subject string) (int64, error) {
+	userID, err := l.pgStore.Raw().Identity().FindPlayerByIdentity(ctx, provider, subject)
+	if err == nil {
+		return userID, nil
+	}
+	if !errors.Is(err, services.ErrNotFound) {
+		return 0, fmt.Errorf("find player by identity: %w", err)
+	}
+
+	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		userID, err = r.User().AddPlayer(ctx)
+		if err != nil {
+			return fmt.Errorf("add player failed: %w", err)
+		}
+
+		if err := r.Identity().AddIdentity(ctx, userID, provider, subject); err != nil {
+			return fmt.Errorf("add identity failed: %w", err)
+		}
+
+		ev := models.UserCreatedEvent{UserID: userID}
+		if err := l.addEvent(ctx, r, l.cfg.UserCreatedTopic, models.EventTypeUserCreated, userID, ev); err != nil {
+			return err
+		}
+		return l.audit(ctx, r, models.AuditEventRegistered, userID, map[string]any{"provider": provider})
+	})
+	if errors.Is(err, services.ErrAlreadyExists) {
+		// A concurrent sign-in created the user first.
+		userID, err = l.pgStore.Raw().Identity().FindPlayerByIdentity(ctx, provider, subject)
+		if err != nil {
+			return 0, fmt.Errorf("find player by identity: %w", err)
+		}
+		return userID, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return userID, nil
+}
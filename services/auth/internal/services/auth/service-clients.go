@@ -0,0 +1,73 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+// serviceTokenType is the token type reported with service tokens.
+const serviceTokenType = "Bearer"
+
+// IssueServiceToken authenticates a service client with its secret and
+// issues a short-lived service token granted the requested scopes, or every
+// scope of the client when none are requested. Service tokens are not bound
+// to a session and cannot be refreshed; callers ask for a new one instead.
+func (l *Service) IssueServiceToken(
+	ctx context.Context,
+	req *models.ClientCredentialsRequest,
+) (*models.ServiceTokenResponse, error) {
+	if req.GrantType != models.GrantTypeClientCredentials {
+		return nil, fmt.Errorf("grant type '%s': %w", req.GrantType, services.ErrUnsupportedGrant)
+	}
+
+	client, err := l.pgStore.Raw().ServiceClient().FindServiceClient(ctx, req.ClientID)
+	if errors.Is(err, services.ErrNotFound) {
+		// Spend the same time as for a known client so client IDs cannot be
+		// enumerated by response time.
+		l.passwordHasher.VerifyDummy(req.ClientSecret)
+		return nil, fmt.Errorf("find service client: %w", services.ErrInvalidClient)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find service client: %w", err)
+	}
+
+	ok, err := l.passwordHasher.Verify(req.ClientSecret, client.SecretHash)
+	if err != nil {
+		return nil, fmt.Errorf("verify client secret: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("verify client secret: %w", services.ErrInvalidClient)
+	}
+	if !client.DisabledAt.IsZero() {
+		return nil, fmt.Errorf("service client '%s' is disabled: %w", client.ClientID, services.ErrInvalidClient)
+	}
+
+	scopes := client.Scopes
+	if req.Scope != "" {
+		scopes = strings.Fields(req.Scope)
+		for _, scope := range scopes {
+			if !slices.Contains(client.Scopes, scope) {
+				return nil, fmt.Errorf("scope '%s': %w", scope, services.ErrInvalidScope)
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	tkn, expiresAt, err := l.tokensFactory.CreateServiceToken(client.ClientID, scopes, now)
+	if err != nil {
+		return nil, fmt.Errorf("create service token: %w", err)
+	}
+
+	return &models.ServiceTokenResponse{
+		AccessToken: tkn,
+		TokenType:   serviceTokenType,
+		ExpiresIn:   int64(expiresAt.Sub(now).Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
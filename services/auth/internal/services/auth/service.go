@@ -3,24 +3,77 @@ package authsvc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-game-backend/pkg/futils"
+	"go-game-backend/pkg/logging"
+	"go-game-backend/pkg/ttlcache"
+	"go-game-backend/services/auth/internal/clientinfo"
 	"go-game-backend/services/auth/internal/dto"
+	"go-game-backend/services/auth/internal/services"
 	"go-game-backend/services/auth/internal/services/token"
 	"go-game-backend/services/auth/pkg/models"
 	"time"
+
+	"github.com/google/uuid"
+
+	redisstore "go-game-backend/pkg/redis"
 )
 
 // Config holds configuration for the authentication service.
 type Config struct {
-	PlayerLockTTL    time.Duration `yaml:"player-lock-ttl"`
-	UserCreatedTopic string        `yaml:"user-created-topic"`
+	PlayerLockTTL       time.Duration `yaml:"player-lock-ttl"`
+	UserCreatedTopic    string        `yaml:"user-created-topic"`
+	SecurityEventsTopic string        `yaml:"security-events-topic"`
+	// IntrospectionCacheTTL bounds how long an introspection result is reused,
+	// and therefore how long a revoked session may still be reported active.
+	IntrospectionCacheTTL  time.Duration `yaml:"introspection-cache-ttl"`
+	IntrospectionCacheSize int           `yaml:"introspection-cache-size"`
+	EmailEventsTopic       string        `yaml:"email-events-topic"`
+	EmailVerificationTTL   time.Duration `yaml:"email-verification-ttl"`
+	PasswordResetTTL       time.Duration `yaml:"password-reset-ttl"`
+	IdentitiesTopic        string        `yaml:"identities-topic"`
+	PlayerBannedTopic      string        `yaml:"player-banned-topic"`
+	// SessionMode selects whether a user keeps a single session or several
+	// concurrent ones, see SessionModeSingle and SessionModeMulti.
+	SessionMode string `yaml:"session-mode"`
+	// MaxSessions caps the concurrent sessions of a user in multi mode. Zero
+	// means no limit.
+	MaxSessions          int    `yaml:"max-sessions"`
+	SessionReplacedTopic string `yaml:"session-replaced-topic"`
+	// MFAIssuer is the account issuer shown in authenticator apps.
+	MFAIssuer       string        `yaml:"mfa-issuer"`
+	MFAChallengeTTL time.Duration `yaml:"mfa-challenge-ttl"`
+	MFAMaxAttempts  int64         `yaml:"mfa-max-attempts"`
+	// DeletionGracePeriod is how long a player can cancel an account deletion
+	// by signing in again before the account is deleted for good.
+	DeletionGracePeriod time.Duration `yaml:"deletion-grace-period"`
+	UserDeletedTopic    string        `yaml:"user-deleted-topic"`
+	// TransferCodeTTL is how long a code for moving an account to another
+	// device can be redeemed.
+	TransferCodeTTL time.Duration `yaml:"transfer-code-ttl"`
 }
 
+// Session modes selectable with Config.SessionMode.
+const (
+	// SessionModeSingle keeps one session per user; signing in on another
+	// device replaces the previous session.
+	SessionModeSingle = "single"
+	// SessionModeMulti keeps up to Config.MaxSessions concurrent sessions per
+	// user, replacing the oldest one when the limit is reached.
+	SessionModeMulti = "multi"
+)
+
 type playerLocker interface {
 	DoWithPlayerLock(ctx context.Context, userID int64, f futils.CtxF) error
 }
 
+type passwordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	VerifyDummy(password string)
+}
+
 // Service provides authentication related operations such as registration,
 // login and token refresh.
 type Service struct {
@@ -29,6 +82,12 @@ type Service struct {
 	rxStore       RedisStore
 	playerLocker  playerLocker
 	tokensFactory *tknfactory.TokensFactory
+
+	passwordHasher     passwordHasher
+	idTokenVerifier    idTokenVerifier
+	secretBox          secretBox
+	introspectionCache *ttlcache.Cache[string, models.IntrospectResponse]
+	logger             *logging.ZapLogger
 }
 
 // New creates a new Service instance with the supplied dependencies.
@@ -38,6 +97,10 @@ func New(
 	rxStore RedisStore,
 	playerLocker playerLocker,
 	tokensFactory *tknfactory.TokensFactory,
+	passwordHasher passwordHasher,
+	idTokenVerifier idTokenVerifier,
+	secretBox secretBox,
+	logger *logging.ZapLogger,
 ) *Service {
 	return &Service{
 		cfg:           cfg,
@@ -45,27 +108,48 @@ func New(
 		rxStore:       rxStore,
 		playerLocker:  playerLocker,
 		tokensFactory: tokensFactory,
+
+		passwordHasher:     passwordHasher,
+		idTokenVerifier:    idTokenVerifier,
+		secretBox:          secretBox,
+		introspectionCache: ttlcache.New[string, models.IntrospectResponse](cfg.IntrospectionCacheSize),
+		logger:             logger,
 	}
 }
 
 // Register creates a new user using the provided login token and returns a
-// session with access and refresh tokens.
+// session with access and refresh tokens. Registering a login token that is
+// already registered signs in to the user owning it, so that a client that
+// lost the response to its registration can safely retry.
 func (l *Service) Register(ctx context.Context, req *models.RegisterRequest) (resp *models.LoginRespose, err error) {
 	var userID int64
 
 	err = l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
-		userID, err = r.User().AddUser(ctx, req.LoginToken)
+		userID, err = r.User().AddPlayer(ctx)
+		if err != nil {
+			return fmt.Errorf("add player failed: %w", err)
+		}
+
+		err = r.Identity().AddIdentity(ctx, userID, models.IdentityProviderGuest, req.LoginToken.String())
+		if errors.Is(err, services.ErrAlreadyExists) {
+			return fmt.Errorf("add identity failed: %w", services.ErrLoginTokenTaken)
+		}
 		if err != nil {
-			return fmt.Errorf("add user failed: %w", err)
+			return fmt.Errorf("add identity failed: %w", err)
 		}
 
 		ev := models.UserCreatedEvent{UserID: userID}
-		if err := r.Outbox().AddJSON(ctx, l.cfg.UserCreatedTopic, ev); err != nil {
-			return fmt.Errorf("save outbox event: %w", err)
+		if err := l.addEvent(ctx, r, l.cfg.UserCreatedTopic, models.EventTypeUserCreated, userID, ev); err != nil {
+			return err
 		}
 
-		return nil
+		return l.audit(ctx, r, models.AuditEventRegistered, userID, map[string]any{
+			"provider": models.IdentityProviderGuest,
+		})
 	})
+	if errors.Is(err, services.ErrLoginTokenTaken) {
+		return l.Login(ctx, &models.LoginRequest{LoginToken: req.LoginToken})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("pg transaction: %w", err)
 	}
@@ -78,23 +162,36 @@ func (l *Service) Register(ctx context.Context, req *models.RegisterRequest) (re
 	return sessionInfo, nil
 }
 
-// Login authenticates a user and starts a new session.
+// Login authenticates a user and starts a new session, or returns an MFA
+// challenge when the user enabled a second factor.
 func (l *Service) Login(ctx context.Context, req *models.LoginRequest) (resp *models.LoginRespose, err error) {
-	userID, err := l.pgStore.Raw().User().FindUserByLoginToken(ctx, req.LoginToken)
-	if err != nil {
-		return nil, fmt.Errorf("find user: %w", err)
+	userID, err := l.pgStore.Raw().Identity().FindPlayerByIdentity(
+		ctx,
+		models.IdentityProviderGuest,
+		req.LoginToken.String(),
+	)
+	details := map[string]any{"provider": models.IdentityProviderGuest}
+	if errors.Is(err, services.ErrNotFound) {
+		err = fmt.Errorf("find user: %w", services.ErrValidationCredentials)
+		return nil, l.auditLoginFailure(ctx, 0, loginFailureUnknownIdentity, details, err)
 	}
-
-	sessionInfo, err := l.startSessionWithPlayerLock(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("start session with player lock: %w", err)
+		return nil, fmt.Errorf("find user: %w", err)
 	}
 
-	return sessionInfo, nil
+	return l.completeLogin(ctx, userID, details)
 }
 
 func (l *Service) startSessionWithPlayerLock(ctx context.Context, userID int64) (resp *models.LoginRespose, err error) {
-	err = l.playerLocker.DoWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+	err = l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		if err := l.checkNotBanned(ctx, userID); err != nil {
+			return err
+		}
+
+		if err := l.cancelDeletion(ctx, userID); err != nil {
+			return fmt.Errorf("cancel deletion: %w", err)
+		}
+
 		resp, err = l.startSession(ctx, userID)
 		if err != nil {
 			return fmt.Errorf("start session: %w", err)
@@ -108,7 +205,25 @@ func (l *Service) startSessionWithPlayerLock(ctx context.Context, userID int64)
 	return resp, nil
 }
 
+// doWithPlayerLock runs f while holding the player lock and reports lock
+// contention as services.ErrPlayerLocked.
+func (l *Service) doWithPlayerLock(ctx context.Context, userID int64, f futils.CtxF) error {
+	err := l.playerLocker.DoWithPlayerLock(ctx, userID, f)
+	if errors.Is(err, redisstore.ErrLockNotObtained) {
+		return fmt.Errorf("%w: %w", services.ErrPlayerLocked, err)
+	}
+	return err
+}
+
+// startSession starts a new session for the user on the device the request
+// comes from. Sessions beyond the limit of the session mode are replaced,
+// oldest first. Must be called while holding the player lock.
 func (l *Service) startSession(ctx context.Context, userID int64) (resp *models.LoginRespose, err error) {
+	existing, err := l.rxStore.Raw().Session().ListSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
 	utcNow := time.Now().UTC()
 
 	sessionToken, sessionTokenExpiresAt := l.tokensFactory.CreateSessionToken(utcNow)
@@ -118,10 +233,21 @@ func (l *Service) startSession(ctx context.Context, userID int64) (resp *models.
 	}
 	refreshToken, refreshTokenExpiresAt := l.tokensFactory.CreateRefreshToken(utcNow)
 
+	info := clientinfo.FromContext(ctx)
+	session := dto.Session{
+		Token:          sessionToken,
+		UserID:         userID,
+		Platform:       info.Platform,
+		AppVersion:     info.AppVersion,
+		IP:             info.IP,
+		CreatedAtUnix:  utcNow.Unix(),
+		LastUsedAtUnix: utcNow.Unix(),
+		ExpiresAtUnix:  sessionTokenExpiresAt.Unix(),
+	}
+
 	err = l.rxStore.DoTx(ctx, func(ctx context.Context, r RedisRepos) error {
-		err := r.Session().SetSessionToken(ctx, userID, sessionToken, sessionTokenExpiresAt)
-		if err != nil {
-			return fmt.Errorf("set session token: %w", err)
+		if err := r.Session().SetSession(ctx, session); err != nil {
+			return fmt.Errorf("set session: %w", err)
 		}
 
 		sessionInfo := dto.SessionInfo{
@@ -138,6 +264,10 @@ func (l *Service) startSession(ctx context.Context, userID int64) (resp *models.
 		return nil, fmt.Errorf("rx transaction: %w", err)
 	}
 
+	if err := l.replaceSessions(ctx, userID, existing, sessionToken); err != nil {
+		return nil, err
+	}
+
 	resp = &models.LoginRespose{
 		AccessToken:   accessToken,
 		RefreshToken:  refreshToken,
@@ -146,14 +276,126 @@ func (l *Service) startSession(ctx context.Context, userID int64) (resp *models.
 	return resp, nil
 }
 
+// replaceSessions revokes the oldest of the existing sessions so that the
+// user keeps no more sessions than the session mode allows, counting the new
+// one, and tells connected servers to disconnect the replaced devices. Must
+// be called while holding the player lock.
+func (l *Service) replaceSessions(
+	ctx context.Context,
+	userID int64,
+	existing []dto.Session,
+	replacedBy uuid.UUID,
+) error {
+	limit := l.sessionLimit()
+	if limit <= 0 || len(existing) < limit {
+		return nil
+	}
+	replaced := existing[:len(existing)-limit+1]
+
+	for _, session := range replaced {
+		if err := l.revokeSession(ctx, userID, session.Token); err != nil {
+			return fmt.Errorf("revoke session: %w", err)
+		}
+	}
+
+	err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		for _, session := range replaced {
+			ev := models.SessionReplacedEvent{
+				UserID:         userID,
+				SessionToken:   session.Token,
+				ReplacedBy:     replacedBy,
+				OccurredAtUnix: time.Now().UTC().Unix(),
+			}
+			if err := l.addEvent(ctx, r, l.cfg.SessionReplacedTopic, models.EventTypeSessionReplaced, userID, ev); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
+}
+
+// sessionLimit returns how many sessions a user may hold at once, or zero
+// when there is no limit.
+func (l *Service) sessionLimit() int {
+	if l.cfg.SessionMode == SessionModeMulti {
+		return l.cfg.MaxSessions
+	}
+	return 1
+}
+
 // RefreshToken exchanges a refresh token for a new pair of access and refresh tokens.
+// Presenting a refresh token that was already exchanged revokes the whole
+// session it belongs to.
 func (l *Service) RefreshToken(ctx context.Context, req *models.RefreshTokenRequest) (resp *models.LoginRespose, err error) {
-	utcNow := time.Now().UTC()
+	sessionInfo, err := l.getSessionInfo(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.doWithPlayerLock(ctx, sessionInfo.UserID, func(ctx context.Context) error {
+		// Re-read under the lock so concurrent rotations of the same token are
+		// serialized and the loser is treated as a reuse.
+		sessionInfo, err = l.getSessionInfo(ctx, req.RefreshToken)
+		if err != nil {
+			return err
+		}
+
+		if sessionInfo.Rotated {
+			if err := l.handleRefreshTokenReuse(ctx, sessionInfo); err != nil {
+				return fmt.Errorf("handle refresh token reuse: %w", err)
+			}
+			return services.ErrRefreshTokenReused
+		}
+
+		if err := l.checkNotBanned(ctx, sessionInfo.UserID); err != nil {
+			return err
+		}
+
+		// Refresh tokens are rotated with a fresh TTL and may outlive the
+		// session they were issued for.
+		_, err = l.rxStore.Raw().Session().GetSession(ctx, sessionInfo.SessionToken)
+		if errors.Is(err, services.ErrNotFound) {
+			return fmt.Errorf("get session: %w", services.ErrInvalidRefreshToken)
+		}
+		if err != nil {
+			return fmt.Errorf("get session: %w", err)
+		}
+
+		resp, err = l.rotateRefreshToken(ctx, req.RefreshToken, sessionInfo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.auditCompleted(ctx, models.AuditEventTokenRefreshed, sessionInfo.UserID, map[string]any{
+		"session_token": sessionInfo.SessionToken,
+	})
+	return resp, nil
+}
 
-	sessionInfo, err := l.rxStore.Raw().Session().GetSessionInfo(ctx, req.RefreshToken)
+func (l *Service) getSessionInfo(ctx context.Context, refreshToken uuid.UUID) (dto.SessionInfo, error) {
+	sessionInfo, err := l.rxStore.Raw().Session().GetSessionInfo(ctx, refreshToken)
+	if errors.Is(err, services.ErrNotFound) {
+		return dto.SessionInfo{}, fmt.Errorf("get session info: %w", services.ErrInvalidRefreshToken)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("get session info: %w", err)
+		return dto.SessionInfo{}, fmt.Errorf("get session info: %w", err)
 	}
+	return sessionInfo, nil
+}
+
+func (l *Service) rotateRefreshToken(
+	ctx context.Context,
+	oldRefreshToken uuid.UUID,
+	sessionInfo dto.SessionInfo,
+) (*models.LoginRespose, error) {
+	utcNow := time.Now().UTC()
 
 	accessToken, expiresAt, err := l.tokensFactory.CreateAccessToken(sessionInfo.UserID, sessionInfo.SessionToken, utcNow)
 	if err != nil {
@@ -162,25 +404,57 @@ func (l *Service) RefreshToken(ctx context.Context, req *models.RefreshTokenRequ
 	refreshToken, refreshTokenExpiresAt := l.tokensFactory.CreateRefreshToken(utcNow)
 
 	err = l.rxStore.DoTx(ctx, func(ctx context.Context, r RedisRepos) error {
-		err := r.Session().RemoveRefreshToken(ctx, req.RefreshToken)
+		err := r.Session().MarkRefreshTokenRotated(ctx, oldRefreshToken)
 		if err != nil {
-			return fmt.Errorf("remove refresh token: %w", err)
+			return fmt.Errorf("mark refresh token rotated: %w", err)
 		}
 
 		err = r.Session().SetRefreshToken(ctx, refreshToken, sessionInfo, refreshTokenExpiresAt)
 		if err != nil {
 			return fmt.Errorf("set refresh token: %w", err)
 		}
+
+		err = r.Session().TouchSession(ctx, sessionInfo.SessionToken, clientinfo.FromContext(ctx).IP, utcNow)
+		if err != nil {
+			return fmt.Errorf("touch session: %w", err)
+		}
 		return nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("rx transaction: %w", err)
 	}
 
-	resp = &models.LoginRespose{
+	return &models.LoginRespose{
 		AccessToken:   accessToken,
 		RefreshToken:  refreshToken,
 		ExpiresAtUnix: expiresAt.Unix(),
+	}, nil
+}
+
+// handleRefreshTokenReuse revokes the session of a reused refresh token and
+// records a security event. Must be called while holding the player lock.
+func (l *Service) handleRefreshTokenReuse(ctx context.Context, sessionInfo dto.SessionInfo) error {
+	if err := l.revokeSession(ctx, sessionInfo.UserID, sessionInfo.SessionToken); err != nil {
+		return fmt.Errorf("revoke session: %w", err)
 	}
-	return resp, nil
+
+	ev := models.SecurityEvent{
+		Type:           models.SecurityEventRefreshTokenReused,
+		UserID:         sessionInfo.UserID,
+		SessionToken:   sessionInfo.SessionToken,
+		OccurredAtUnix: time.Now().UTC().Unix(),
+	}
+	err := l.pgStore.DoTx(ctx, func(ctx context.Context, r PostgresRepos) error {
+		if err := l.addEvent(ctx, r, l.cfg.SecurityEventsTopic, models.EventTypeSecurity, sessionInfo.UserID, ev); err != nil {
+			return err
+		}
+		return l.audit(ctx, r, models.SecurityEventRefreshTokenReused, sessionInfo.UserID, map[string]any{
+			"session_token": sessionInfo.SessionToken,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("pg transaction: %w", err)
+	}
+
+	return nil
 }
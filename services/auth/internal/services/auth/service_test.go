@@ -0,0 +1,182 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/internal/services/token"
+	"go-game-backend/services/auth/pkg/models"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// claimsOf returns the claims of the access token of resp.
+func (e *testEnv) claimsOf(t *testing.T, resp *models.LoginRespose) tknfactory.AccessTokenClaims {
+	t.Helper()
+	claims, err := e.svc.tokensFactory.ParseAccessToken(context.Background(), resp.AccessToken)
+	if err != nil {
+		t.Fatalf("parse access token: %v", err)
+	}
+	return claims
+}
+
+// userOf returns the user the access token of resp was issued to.
+func (e *testEnv) userOf(t *testing.T, resp *models.LoginRespose) int64 {
+	t.Helper()
+	return e.claimsOf(t, resp).UserID
+}
+
+func TestRegisterTwiceSignsInToSamePlayer(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	req := &models.RegisterRequest{LoginToken: uuid.New()}
+
+	first, err := env.svc.Register(ctx, req)
+	if err != nil {
+		t.Fatalf("first register: %v", err)
+	}
+	second, err := env.svc.Register(ctx, req)
+	if err != nil {
+		t.Fatalf("second register: %v", err)
+	}
+
+	userID := env.userOf(t, first)
+	if got := env.userOf(t, second); got != userID {
+		t.Fatalf("second register signed in to user %d, want %d", got, userID)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("second register returned the refresh token of the first")
+	}
+	if n := len(env.pg.eventsOfType(models.EventTypeUserCreated)); n != 1 {
+		t.Fatalf("user-created events = %d, want 1", n)
+	}
+	if n := len(env.pg.data.players); n != 1 {
+		t.Fatalf("players = %d, want 1", n)
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	first, err := env.svc.Register(ctx, &models.RegisterRequest{LoginToken: uuid.New()})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	sessionToken := env.claimsOf(t, first).SessionToken
+
+	second, err := env.svc.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh returned the same refresh token")
+	}
+	if got := env.claimsOf(t, second).SessionToken; got != sessionToken {
+		t.Errorf("session = %v, want %v", got, sessionToken)
+	}
+
+	// the rotated token is kept, flagged, to detect its reuse
+	old, err := env.rx.GetSessionInfo(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("get rotated token: %v", err)
+	}
+	if !old.Rotated {
+		t.Error("rotated token not flagged rotated")
+	}
+	if old.SessionToken != sessionToken {
+		t.Errorf("rotated token session = %v, want %v", old.SessionToken, sessionToken)
+	}
+	current, err := env.rx.GetSessionInfo(ctx, second.RefreshToken)
+	if err != nil {
+		t.Fatalf("get new token: %v", err)
+	}
+	if current.Rotated {
+		t.Error("new token flagged rotated")
+	}
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	first, err := env.svc.Register(ctx, &models.RegisterRequest{LoginToken: uuid.New()})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	claims := env.claimsOf(t, first)
+	second, err := env.svc.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	third, err := env.svc.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: second.RefreshToken})
+	if err != nil {
+		t.Fatalf("refresh again: %v", err)
+	}
+
+	_, err = env.svc.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	if !errors.Is(err, services.ErrRefreshTokenReused) {
+		t.Fatalf("reuse error = %v, want %v", err, services.ErrRefreshTokenReused)
+	}
+
+	// every token of the family is revoked with the session
+	for _, token := range []uuid.UUID{first.RefreshToken, second.RefreshToken, third.RefreshToken} {
+		_, err := env.svc.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: token})
+		if !errors.Is(err, services.ErrInvalidRefreshToken) {
+			t.Errorf("refresh with %v after reuse = %v, want %v", token, err, services.ErrInvalidRefreshToken)
+		}
+	}
+	if _, err := env.rx.GetSession(ctx, claims.SessionToken); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("session after reuse: %v, want %v", err, services.ErrNotFound)
+	}
+	if n := len(env.pg.eventsOfType(models.EventTypeSecurity)); n != 1 {
+		t.Errorf("security events = %d, want 1", n)
+	}
+}
+
+func TestConcurrentRefresh(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	resp, err := env.svc.Register(ctx, &models.RegisterRequest{LoginToken: uuid.New()})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	const n = 8
+	results := make([]*models.LoginRespose, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = env.svc.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: resp.RefreshToken})
+		}()
+	}
+	wg.Wait()
+
+	var succeeded, reused int
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+			// the winner's token belongs to the revoked family
+			_, err := env.svc.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: results[i].RefreshToken})
+			if !errors.Is(err, services.ErrInvalidRefreshToken) {
+				t.Errorf("refresh with the rotated family = %v, want %v", err, services.ErrInvalidRefreshToken)
+			}
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			reused++
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			// the session was revoked before the refresh took the lock
+		default:
+			t.Errorf("refresh: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("succeeded = %d, want 1", succeeded)
+	}
+	if reused == 0 {
+		t.Error("no refresh detected the reuse")
+	}
+}
@@ -0,0 +1,127 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/pkg/jwtfactory"
+	"go-game-backend/services/auth/internal/services"
+	"go-game-backend/services/auth/pkg/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IntrospectToken validates an access token and reports whether the session
+// it was issued for is still the active one. Invalid tokens are not an error
+// and are reported as inactive. Results are cached for a short time so game
+// servers can introspect on every request.
+func (l *Service) IntrospectToken(ctx context.Context, req *models.IntrospectRequest) (*models.IntrospectResponse, error) {
+	if cached, ok := l.introspectionCache.Get(req.AccessToken); ok {
+		return &cached, nil
+	}
+
+	resp, err := l.introspectToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(l.cfg.IntrospectionCacheTTL)
+	if resp.ExpiresAtUnix != 0 {
+		expiresAt = minTime(expiresAt, time.Unix(resp.ExpiresAtUnix, 0))
+	}
+	l.introspectionCache.Set(req.AccessToken, *resp, expiresAt)
+
+	return resp, nil
+}
+
+func (l *Service) introspectToken(ctx context.Context, accessToken string) (*models.IntrospectResponse, error) {
+	claims, err := l.tokensFactory.ParseAccessToken(ctx, accessToken)
+	if errors.Is(err, jwtfactory.ErrInvalidToken) {
+		return &models.IntrospectResponse{Active: false}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse access token: %w", err)
+	}
+
+	resp := &models.IntrospectResponse{
+		UserID:        claims.UserID,
+		SessionToken:  claims.SessionToken,
+		IssuedAtUnix:  claims.IssuedAt.Unix(),
+		ExpiresAtUnix: claims.ExpiresAt.Unix(),
+	}
+
+	resp.Active, err = l.IsActiveSession(ctx, claims.UserID, claims.SessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// IsActiveSession reports whether sessionToken is an active session of the
+// user. It lets the service act as an authverify.SessionChecker.
+func (l *Service) IsActiveSession(ctx context.Context, userID int64, sessionToken uuid.UUID) (bool, error) {
+	session, err := l.rxStore.Raw().Session().GetSession(ctx, sessionToken)
+	if errors.Is(err, services.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get session: %w", err)
+	}
+	return session.UserID == userID, nil
+}
+
+// GetSessions returns the active sessions of the given user together with
+// the devices they were started on, oldest first.
+func (l *Service) GetSessions(ctx context.Context, userID int64) ([]models.Session, error) {
+	sessions, err := l.rxStore.Raw().Session().ListSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	res := make([]models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, models.Session{
+			SessionToken:   session.Token,
+			Platform:       session.Platform,
+			AppVersion:     session.AppVersion,
+			IP:             session.IP,
+			CreatedAtUnix:  session.CreatedAtUnix,
+			LastUsedAtUnix: session.LastUsedAtUnix,
+			ExpiresAtUnix:  session.ExpiresAtUnix,
+		})
+	}
+	return res, nil
+}
+
+// RevokeSession ends a session of the user, e.g. one started on a lost
+// device.
+func (l *Service) RevokeSession(ctx context.Context, userID int64, req *models.RevokeSessionRequest) error {
+	session, err := l.rxStore.Raw().Session().GetSession(ctx, req.SessionToken)
+	if errors.Is(err, services.ErrNotFound) || (err == nil && session.UserID != userID) {
+		return fmt.Errorf("get session: %w", services.ErrSessionNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+
+	err = l.doWithPlayerLock(ctx, userID, func(ctx context.Context) error {
+		return l.revokeSession(ctx, userID, req.SessionToken)
+	})
+	if err != nil {
+		return err
+	}
+
+	l.auditCompleted(ctx, models.AuditEventSessionRevoked, userID, map[string]any{
+		"session_token": req.SessionToken,
+	})
+	return nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -5,3 +5,121 @@ import "errors"
 
 // ErrValidationCredentials is returned when provided credentials are invalid.
 var ErrValidationCredentials = errors.New("invalid credentials")
+
+// ErrLoginTokenTaken is returned when registering a login token that already
+// belongs to another user.
+var ErrLoginTokenTaken = errors.New("login token already registered")
+
+// ErrEmailTaken is returned when registering an email that already belongs to
+// another user.
+var ErrEmailTaken = errors.New("email already registered")
+
+// ErrEmailAlreadyLinked is returned when linking an email to a user that
+// already has one.
+var ErrEmailAlreadyLinked = errors.New("user already has an email")
+
+// ErrEmailNotVerified is returned when signing in with an email and password
+// before the email was verified.
+var ErrEmailNotVerified = errors.New("email not verified")
+
+// ErrInvalidEmailToken is returned when an email verification or password
+// reset token is unknown, already used or expired.
+var ErrInvalidEmailToken = errors.New("invalid email token")
+
+// ErrUnknownProvider is returned when signing in with an identity provider
+// that is not configured.
+var ErrUnknownProvider = errors.New("unknown identity provider")
+
+// ErrProviderUnavailable is returned when an external identity provider
+// cannot be reached.
+var ErrProviderUnavailable = errors.New("identity provider unavailable")
+
+// ErrIdentityTaken is returned when linking an identity that already belongs
+// to another user.
+var ErrIdentityTaken = errors.New("identity belongs to another user")
+
+// ErrIdentityNotFound is returned when unlinking an identity the user does
+// not have.
+var ErrIdentityNotFound = errors.New("identity not found")
+
+// ErrLastIdentity is returned when unlinking the only identity of a user,
+// which would make the account unreachable.
+var ErrLastIdentity = errors.New("cannot unlink the last identity")
+
+// ErrMergeNotAllowed is returned when merging an account that is not a guest
+// account.
+var ErrMergeNotAllowed = errors.New("only guest accounts can be merged")
+
+// ErrMFAAlreadyEnabled is returned when enrolling a second factor for a user
+// who already has one.
+var ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+
+// ErrMFANotEnrolled is returned when confirming or using a second factor the
+// user has not enrolled.
+var ErrMFANotEnrolled = errors.New("two-factor authentication not enrolled")
+
+// ErrInvalidMFACode is returned when a TOTP code or recovery code is wrong or
+// was already used.
+var ErrInvalidMFACode = errors.New("invalid two-factor code")
+
+// ErrInvalidMFAToken is returned when an MFA challenge token is unknown or
+// has expired.
+var ErrInvalidMFAToken = errors.New("invalid mfa token")
+
+// ErrInvalidTransferCode is returned when a device transfer code is unknown,
+// already redeemed or has expired.
+var ErrInvalidTransferCode = errors.New("invalid transfer code")
+
+// ErrPlayerBanned is returned when a banned player tries to sign in or
+// refresh a session.
+var ErrPlayerBanned = errors.New("player is banned")
+
+// ErrBanExpired is returned when issuing a ban that expires in the past.
+var ErrBanExpired = errors.New("ban expiration is in the past")
+
+// ErrPlayerNotBanned is returned when lifting the ban of a player who is not
+// banned.
+var ErrPlayerNotBanned = errors.New("player is not banned")
+
+// ErrPlayerNotFound is returned when an operation targets a player that does
+// not exist.
+var ErrPlayerNotFound = errors.New("player not found")
+
+// ErrSessionNotFound is returned when revoking a session the user does not
+// have.
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrInvalidRefreshToken is returned when a refresh token is unknown or has
+// expired.
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// ErrRefreshTokenReused is returned when a refresh token that was already
+// exchanged is presented again. The session it belongs to is revoked.
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+
+// ErrPlayerLocked is returned when another operation on the same player is in
+// progress.
+var ErrPlayerLocked = errors.New("player is locked")
+
+// ErrInvalidClient is returned when a service client is unknown, disabled or
+// presents a wrong secret.
+var ErrInvalidClient = errors.New("invalid client credentials")
+
+// ErrInvalidScope is returned when a service client asks for a scope it is
+// not allowed.
+var ErrInvalidScope = errors.New("scope not allowed for client")
+
+// ErrUnsupportedGrant is returned when asking for a service token with a
+// grant other than client credentials.
+var ErrUnsupportedGrant = errors.New("unsupported grant type")
+
+// ErrStorageUnavailable is returned when a backing storage cannot be reached.
+var ErrStorageUnavailable = errors.New("storage unavailable")
+
+// ErrNotFound is returned by repositories when the requested record does not
+// exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrAlreadyExists is returned by repositories when a record violates a
+// uniqueness constraint.
+var ErrAlreadyExists = errors.New("already exists")
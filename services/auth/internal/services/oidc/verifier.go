@@ -0,0 +1,134 @@
+// Package oidc verifies ID tokens issued by external OpenID Connect
+// providers.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-game-backend/pkg/jwks"
+	"slices"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+// ErrUnknownProvider is returned when an ID token is presented for a provider
+// that is not configured.
+var ErrUnknownProvider = errors.New("unknown identity provider")
+
+// ErrInvalidIDToken is returned when an ID token is malformed, expired, has
+// an invalid signature or was issued for another issuer or client.
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// ErrProviderUnavailable is returned when the keys of a provider cannot be
+// fetched.
+var ErrProviderUnavailable = errors.New("identity provider unavailable")
+
+// Config lists the providers players can sign in with, keyed by the name used
+// in requests (e.g. "google" or "apple").
+type Config struct {
+	Providers map[string]*ProviderConfig `yaml:"providers"`
+	// ClockSkew is the tolerated difference between our clock and the
+	// provider's when validating exp, iat and nbf.
+	ClockSkew time.Duration `yaml:"clock-skew"`
+}
+
+// ProviderConfig describes a single OpenID Connect provider.
+type ProviderConfig struct {
+	// Issuer must match the "iss" claim of the ID tokens.
+	Issuer string `yaml:"issuer"`
+	// JWKS is the endpoint publishing the provider's signing keys.
+	JWKS *jwks.RemoteConfig `yaml:"jwks"`
+	// ClientIDs are the OAuth client IDs of our apps; the "aud" claim must
+	// contain one of them.
+	ClientIDs []string `yaml:"client-ids"`
+}
+
+// Claims holds the verified contents of an ID token.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+type provider struct {
+	cfg  *ProviderConfig
+	keys *jwks.Remote
+}
+
+// Verifier validates ID tokens against the configured providers.
+type Verifier struct {
+	cfg       *Config
+	providers map[string]*provider
+}
+
+// NewVerifier creates a Verifier for the configured providers. Provider keys
+// are fetched on first use and refreshed in the background until ctx is done.
+func NewVerifier(ctx context.Context, cfg *Config) (*Verifier, error) {
+	providers := make(map[string]*provider, len(cfg.Providers))
+	for name, pc := range cfg.Providers {
+		keys, err := jwks.NewRemote(ctx, pc.JWKS)
+		if err != nil {
+			return nil, fmt.Errorf("provider '%s': %w", name, err)
+		}
+		providers[name] = &provider{cfg: pc, keys: keys}
+	}
+
+	return &Verifier{
+		cfg:       cfg,
+		providers: providers,
+	}, nil
+}
+
+// Verify checks the signature, issuer, audience and validity period of an ID
+// token issued by the named provider and returns its claims.
+func (v *Verifier) Verify(ctx context.Context, providerName, rawToken string) (*Claims, error) {
+	p, ok := v.providers[providerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownProvider, providerName)
+	}
+
+	keySet, err := p.keys.KeySet(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+	}
+
+	token, err := jwt.ParseString(
+		rawToken,
+		// Some providers publish keys without "alg".
+		jwt.WithKeySet(keySet, jws.WithInferAlgorithmFromKey(true)),
+		jwt.WithValidate(true),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAcceptableSkew(v.cfg.ClockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	audienceOK := slices.ContainsFunc(token.Audience(), func(aud string) bool {
+		return slices.Contains(p.cfg.ClientIDs, aud)
+	})
+	if !audienceOK {
+		return nil, fmt.Errorf("%w: unexpected audience %v", ErrInvalidIDToken, token.Audience())
+	}
+
+	if token.Subject() == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidIDToken)
+	}
+
+	claims := &Claims{Subject: token.Subject()}
+	if email, ok := token.PrivateClaims()["email"].(string); ok {
+		claims.Email = email
+	}
+	// Apple encodes email_verified as a string.
+	switch verified := token.PrivateClaims()["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = verified
+	case string:
+		claims.EmailVerified = verified == "true"
+	}
+
+	return claims, nil
+}
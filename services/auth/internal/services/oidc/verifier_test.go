@@ -0,0 +1,147 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"go-game-backend/pkg/jwks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+// fakeIssuer serves a JWKS document and signs ID tokens with its key.
+type fakeIssuer struct {
+	server *httptest.Server
+	key    jwk.Key
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	raw, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	key, err := jwk.FromRaw(raw)
+	if err != nil {
+		t.Fatalf("jwk from key: %v", err)
+	}
+	_ = key.Set(jwk.KeyIDKey, "test-key")
+	_ = key.Set(jwk.AlgorithmKey, jwa.ES256)
+
+	public, err := jwk.PublicKeyOf(key)
+	if err != nil {
+		t.Fatalf("public key: %v", err)
+	}
+	set := jwk.NewSet()
+	_ = set.AddKey(public)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+
+	return &fakeIssuer{server: server, key: key}
+}
+
+func (f *fakeIssuer) sign(t *testing.T, build func(b *jwt.Builder) *jwt.Builder) string {
+	t.Helper()
+
+	now := time.Now()
+	b := jwt.NewBuilder().
+		Issuer(f.server.URL).
+		Audience([]string{"game-client"}).
+		Subject("provider-user-1").
+		IssuedAt(now).
+		Expiration(now.Add(time.Hour)).
+		Claim("email", "player@example.com").
+		Claim("email_verified", "true")
+	token, err := build(b).Build()
+	if err != nil {
+		t.Fatalf("build token: %v", err)
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.ES256, f.key))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return string(signed)
+}
+
+func TestVerifier(t *testing.T) {
+	issuer := newFakeIssuer(t)
+
+	v, err := NewVerifier(context.Background(), &Config{
+		Providers: map[string]*ProviderConfig{
+			"fake": {
+				Issuer:    issuer.server.URL,
+				JWKS:      &jwks.RemoteConfig{URL: issuer.server.URL, RefreshInterval: time.Minute},
+				ClientIDs: []string{"game-client"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	same := func(b *jwt.Builder) *jwt.Builder { return b }
+
+	tests := []struct {
+		name     string
+		provider string
+		token    string
+		wantErr  error
+	}{
+		{name: "valid", provider: "fake", token: issuer.sign(t, same)},
+		{name: "unknown provider", provider: "other", token: issuer.sign(t, same), wantErr: ErrUnknownProvider},
+		{
+			name:     "other audience",
+			provider: "fake",
+			token:    issuer.sign(t, func(b *jwt.Builder) *jwt.Builder { return b.Audience([]string{"other-app"}) }),
+			wantErr:  ErrInvalidIDToken,
+		},
+		{
+			name:     "other issuer",
+			provider: "fake",
+			token:    issuer.sign(t, func(b *jwt.Builder) *jwt.Builder { return b.Issuer("https://evil.example") }),
+			wantErr:  ErrInvalidIDToken,
+		},
+		{
+			name:     "expired",
+			provider: "fake",
+			token: issuer.sign(t, func(b *jwt.Builder) *jwt.Builder {
+				return b.Expiration(time.Now().Add(-time.Hour))
+			}),
+			wantErr: ErrInvalidIDToken,
+		},
+		{name: "garbage", provider: "fake", token: "not-a-jwt", wantErr: ErrInvalidIDToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(context.Background(), tt.provider, tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if claims.Subject != "provider-user-1" || claims.Email != "player@example.com" || !claims.EmailVerified {
+				t.Fatalf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
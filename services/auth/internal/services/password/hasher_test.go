@@ -0,0 +1,32 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHasher(t *testing.T) {
+	h, err := NewHasher(&Config{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err != nil {
+		t.Fatalf("new hasher: %v", err)
+	}
+
+	hash, err := h.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("unexpected hash format %q", hash)
+	}
+
+	if ok, err := h.Verify("correct horse battery staple", hash); err != nil || !ok {
+		t.Fatalf("Verify(correct) = %v, %v; want true, nil", ok, err)
+	}
+	if ok, err := h.Verify("wrong", hash); err != nil || ok {
+		t.Fatalf("Verify(wrong) = %v, %v; want false, nil", ok, err)
+	}
+	if _, err := h.Verify("any", "$bcrypt$whatever"); !errors.Is(err, ErrMalformedHash) {
+		t.Fatalf("Verify(malformed) error = %v, want ErrMalformedHash", err)
+	}
+}
@@ -3,8 +3,10 @@
 package tknfactory
 
 import (
+	"context"
 	"fmt"
 	"go-game-backend/pkg/jwtfactory"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +16,15 @@ import (
 type Config struct {
 	AccessTokenTTL  time.Duration `yaml:"access-token-ttl"`
 	RefreshTokenTTL time.Duration `yaml:"refresh-token-ttl"`
+	ServiceTokenTTL time.Duration `yaml:"service-token-ttl"`
+}
+
+// AccessTokenClaims holds the contents of a parsed access token.
+type AccessTokenClaims struct {
+	UserID       int64
+	SessionToken uuid.UUID
+	IssuedAt     time.Time
+	ExpiresAt    time.Time
 }
 
 // TokensFactory generates access, refresh, and session tokens.
@@ -47,6 +58,57 @@ func (f *TokensFactory) CreateAccessToken(
 	return tkn, expiresAt, nil
 }
 
+// CreateServiceToken generates a JWT service token for a service client
+// granted the given scopes. Scopes are carried space separated in the "scope"
+// claim, as in OAuth 2.0.
+func (f *TokensFactory) CreateServiceToken(
+	clientID string,
+	scopes []string,
+	issueTime time.Time,
+) (tkn string, expiresAt time.Time, err error) {
+	payload := map[string]any{
+		"client_id": clientID,
+		"scope":     strings.Join(scopes, " "),
+	}
+	tkn, expiresAt, err = f.tokenFactory.Generate(f.cfg.ServiceTokenTTL, issueTime, payload)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("jwt token generation failed: %w", err)
+	}
+	return tkn, expiresAt, nil
+}
+
+// ParseAccessToken validates an access token created by CreateAccessToken and
+// returns its claims. Invalid tokens are reported as jwtfactory.ErrInvalidToken.
+func (f *TokensFactory) ParseAccessToken(ctx context.Context, tkn string) (AccessTokenClaims, error) {
+	token, err := f.tokenFactory.Parse(ctx, tkn)
+	if err != nil {
+		return AccessTokenClaims{}, fmt.Errorf("jwt token parsing failed: %w", err)
+	}
+
+	claims := AccessTokenClaims{
+		IssuedAt:  token.IssuedAt(),
+		ExpiresAt: token.Expiration(),
+	}
+
+	// JSON numbers are decoded as float64.
+	userID, ok := token.PrivateClaims()["userID"].(float64)
+	if !ok {
+		return AccessTokenClaims{}, fmt.Errorf("%w: missing userID claim", jwtfactory.ErrInvalidToken)
+	}
+	claims.UserID = int64(userID)
+
+	session, ok := token.PrivateClaims()["session"].(string)
+	if !ok {
+		return AccessTokenClaims{}, fmt.Errorf("%w: missing session claim", jwtfactory.ErrInvalidToken)
+	}
+	claims.SessionToken, err = uuid.Parse(session)
+	if err != nil {
+		return AccessTokenClaims{}, fmt.Errorf("%w: %w", jwtfactory.ErrInvalidToken, err)
+	}
+
+	return claims, nil
+}
+
 // CreateRefreshToken creates a refresh token that expires after the configured
 // TTL.
 func (f *TokensFactory) CreateRefreshToken(issueTime time.Time) (tkn uuid.UUID, expiresAt time.Time) {
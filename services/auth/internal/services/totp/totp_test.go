@@ -0,0 +1,55 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test vectors from RFC 6238, Appendix B (SHA-1).
+func TestGenerateRFC6238(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tt := range tests {
+		got := generate(secret, Step(time.Unix(tt.unix, 0)), 8)
+		if got != tt.want {
+			t.Errorf("generate(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	code := generate(secret, Step(now)-1, Digits)
+
+	step, ok := Validate(secret, code, now, 1)
+	if !ok || step != Step(now)-1 {
+		t.Fatalf("Validate(previous step) = %d, %v; want %d, true", step, ok, Step(now)-1)
+	}
+	if _, ok := Validate(secret, code, now.Add(2*Period), 1); ok {
+		t.Fatal("code accepted outside of the skew window")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Game", "player@example.com", []byte("12345678901234567890"))
+	if !strings.HasPrefix(uri, "otpauth://totp/Game:player@example.com?") {
+		t.Fatalf("unexpected uri %s", uri)
+	}
+	if !strings.Contains(uri, "secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ") {
+		t.Fatalf("uri %s does not carry the base32 secret", uri)
+	}
+}
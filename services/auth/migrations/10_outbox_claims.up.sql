@@ -0,0 +1,8 @@
+-- Lets several forwarder instances share the outbox: an instance claims a
+-- batch of events by leasing them until claimed_until, and events whose
+-- lease ran out, e.g. because the instance crashed, are claimed again.
+ALTER TABLE outbox
+    ADD COLUMN claimed_by    TEXT,
+    ADD COLUMN claimed_until TIMESTAMPTZ;
+
+CREATE INDEX outbox_unprocessed_idx ON outbox (topic, id) WHERE processed_at IS NULL;
@@ -0,0 +1,11 @@
+-- Tracks failed publish attempts of outbox events. A failed event is retried
+-- with a backoff once next_attempt_at has passed and is moved to the
+-- dead-letter state, dead_at, after too many attempts, so that it no longer
+-- holds back the events of its topic.
+ALTER TABLE outbox
+    ADD COLUMN attempts        INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_error      TEXT,
+    ADD COLUMN next_attempt_at TIMESTAMPTZ,
+    ADD COLUMN dead_at         TIMESTAMPTZ;
+
+CREATE INDEX outbox_dead_idx ON outbox (id) WHERE dead_at IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Events are published with a Kafka message key and headers. Events sharing
+-- a key go to the same partition and are published in order, so ordering is
+-- now kept per topic and partition key instead of per topic.
+ALTER TABLE outbox
+    ADD COLUMN partition_key TEXT  NOT NULL DEFAULT '',
+    ADD COLUMN headers       JSONB NOT NULL DEFAULT '{}';
+
+DROP INDEX outbox_unprocessed_idx;
+CREATE INDEX outbox_unprocessed_idx ON outbox (topic, partition_key, id) WHERE processed_at IS NULL;
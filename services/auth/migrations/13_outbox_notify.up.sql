@@ -0,0 +1,14 @@
+-- Notifies the outbox forwarders listening on the outbox channel when events
+-- are added, so that they do not have to wait for their next poll. The
+-- notification is delivered when the inserting transaction commits.
+CREATE FUNCTION outbox_notify() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_notify
+    AFTER INSERT ON outbox
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION outbox_notify();
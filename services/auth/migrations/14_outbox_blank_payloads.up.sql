@@ -0,0 +1,3 @@
+-- Payloads of published events are no longer kept, since some carry
+-- single-use tokens stored only hashed elsewhere.
+UPDATE outbox SET payload = ''::bytea WHERE processed_at IS NOT NULL;
@@ -0,0 +1,16 @@
+-- Processed events are deleted after a retention period, and the events of a
+-- player are deleted when the player is purged. Events added before they had
+-- a partition key are keyed by their player so that the purge finds them.
+--
+-- Events without a user_id keep the empty partition key: the column is NOT
+-- NULL, so no event is left with a NULL key. The claim query orders the
+-- events with an empty key like any other key, all together per topic, and
+-- the purge never deletes them, as it only deletes the key of a player.
+UPDATE outbox
+SET partition_key = convert_from(payload, 'UTF8')::jsonb ->> 'user_id'
+WHERE partition_key = ''
+  AND payload <> ''::bytea
+  AND convert_from(payload, 'UTF8')::jsonb ->> 'user_id' IS NOT NULL;
+
+CREATE INDEX outbox_processed_idx ON outbox (processed_at) WHERE processed_at IS NOT NULL;
+CREATE INDEX outbox_partition_key_idx ON outbox (partition_key);
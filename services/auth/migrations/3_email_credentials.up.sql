@@ -0,0 +1,23 @@
+-- Players registered with an email have no login token.
+ALTER TABLE player_credentials ALTER COLUMN login_token DROP NOT NULL;
+
+CREATE TABLE player_email_credentials
+(
+    player_id     BIGINT PRIMARY KEY REFERENCES player_credentials (id) ON DELETE CASCADE,
+    email         TEXT        NOT NULL UNIQUE,
+    password_hash TEXT        NOT NULL,
+    verified_at   TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Single-use tokens sent by email. Only SHA-256 hashes of the tokens are stored.
+CREATE TABLE player_email_tokens
+(
+    token_hash BYTEA PRIMARY KEY,
+    player_id  BIGINT      NOT NULL REFERENCES player_email_credentials (player_id) ON DELETE CASCADE,
+    purpose    TEXT        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX player_email_tokens_player_id_idx ON player_email_tokens (player_id);
@@ -0,0 +1,26 @@
+-- Every credential a player can sign in with. The provider names the kind of
+-- credential ("guest", "email" or an external provider) and the subject
+-- identifies it within that provider.
+CREATE TABLE player_identities
+(
+    id         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    player_id  BIGINT      NOT NULL REFERENCES player_credentials (id) ON DELETE CASCADE,
+    provider   TEXT        NOT NULL,
+    subject    TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX player_identities_player_id_idx ON player_identities (player_id);
+
+INSERT INTO player_identities (player_id, provider, subject)
+SELECT id, 'guest', login_token::TEXT
+FROM player_credentials
+WHERE login_token IS NOT NULL;
+
+INSERT INTO player_identities (player_id, provider, subject)
+SELECT player_id, 'email', email
+FROM player_email_credentials;
+
+-- Guest login tokens are now stored as identities.
+ALTER TABLE player_credentials DROP COLUMN login_token;
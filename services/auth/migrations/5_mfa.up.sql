@@ -0,0 +1,19 @@
+CREATE TABLE player_totp
+(
+    player_id        BIGINT PRIMARY KEY REFERENCES player_credentials (id) ON DELETE CASCADE,
+    -- AES-GCM encrypted shared secret.
+    secret_encrypted BYTEA       NOT NULL,
+    confirmed_at     TIMESTAMPTZ,
+    -- Time step of the last accepted code, used to reject replays.
+    last_used_step   BIGINT      NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One-time recovery codes. Only SHA-256 hashes of the codes are stored.
+CREATE TABLE player_recovery_codes
+(
+    player_id BIGINT      NOT NULL REFERENCES player_credentials (id) ON DELETE CASCADE,
+    code_hash BYTEA       NOT NULL,
+    used_at   TIMESTAMPTZ,
+    PRIMARY KEY (player_id, code_hash)
+);
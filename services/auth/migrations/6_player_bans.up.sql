@@ -0,0 +1,14 @@
+-- Bans issued by moderators. A ban without expires_at is permanent; lifting a
+-- ban keeps the row for the moderation history.
+CREATE TABLE player_bans
+(
+    id         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    player_id  BIGINT      NOT NULL REFERENCES player_credentials (id) ON DELETE CASCADE,
+    reason     TEXT        NOT NULL,
+    issued_by  TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ,
+    lifted_at  TIMESTAMPTZ
+);
+
+CREATE INDEX player_bans_player_id_idx ON player_bans (player_id);
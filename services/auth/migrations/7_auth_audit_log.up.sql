@@ -0,0 +1,15 @@
+-- Append-only trail of security-relevant actions. Rows are never updated and
+-- deliberately not tied to player_credentials, so that the trail outlives the
+-- player and can record attempts for unknown players.
+CREATE TABLE auth_audit_log
+(
+    id         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    player_id  BIGINT,
+    event      TEXT        NOT NULL,
+    ip         TEXT        NOT NULL DEFAULT '',
+    user_agent TEXT        NOT NULL DEFAULT '',
+    details    JSONB       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX auth_audit_log_player_id_created_at_idx ON auth_audit_log (player_id, created_at);
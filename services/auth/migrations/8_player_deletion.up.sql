@@ -0,0 +1,9 @@
+-- Players who asked for their account to be deleted. The account is removed
+-- for good once delete_after passes; signing in before that cancels the
+-- deletion.
+ALTER TABLE player_credentials
+    ADD COLUMN deletion_requested_at TIMESTAMPTZ,
+    ADD COLUMN delete_after          TIMESTAMPTZ;
+
+CREATE INDEX player_credentials_delete_after_idx ON player_credentials (delete_after)
+    WHERE delete_after IS NOT NULL;
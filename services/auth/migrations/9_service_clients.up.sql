@@ -0,0 +1,12 @@
+-- Internal callers (game servers, admin tools, other services) that obtain
+-- service tokens with the client credentials grant. Secrets are stored as
+-- argon2id hashes; a disabled client can no longer obtain tokens.
+CREATE TABLE IF NOT EXISTS service_clients
+(
+    client_id   TEXT PRIMARY KEY,
+    secret_hash TEXT        NOT NULL,
+    scopes      TEXT[]      NOT NULL DEFAULT '{}',
+    description TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    disabled_at TIMESTAMPTZ
+);
@@ -0,0 +1,42 @@
+package authverify
+
+import "context"
+
+type ctxKey string
+
+const (
+	claimsCtxKey        ctxKey = "authClaims"
+	serviceClaimsCtxKey ctxKey = "authServiceClaims"
+)
+
+// WithClaims returns a context carrying the verified claims.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey, claims)
+}
+
+// ClaimsFromContext returns the verified claims stored in ctx, if any.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsCtxKey).(*Claims)
+	return claims, ok
+}
+
+// UserIDFromContext returns the authenticated user ID stored in ctx, if any.
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// WithServiceClaims returns a context carrying the verified service claims.
+func WithServiceClaims(ctx context.Context, claims *ServiceClaims) context.Context {
+	return context.WithValue(ctx, serviceClaimsCtxKey, claims)
+}
+
+// ServiceClaimsFromContext returns the verified service claims stored in ctx,
+// if any.
+func ServiceClaimsFromContext(ctx context.Context) (*ServiceClaims, bool) {
+	claims, ok := ctx.Value(serviceClaimsCtxKey).(*ServiceClaims)
+	return claims, ok
+}
@@ -0,0 +1,74 @@
+package authverify
+
+import (
+	"errors"
+	"go-game-backend/services/auth/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/jwtauth/v5"
+)
+
+// Error codes reported by the gin middleware in models.ErrorResponse.Code.
+const (
+	ErrorCodeInvalidAccessToken = "invalid_access_token"
+	ErrorCodeSessionRevoked     = "session_revoked"
+	ErrorCodeInsufficientScope  = "insufficient_scope"
+)
+
+// GinMiddleware authenticates requests using the bearer token from the
+// Authorization header and aborts unauthenticated requests with 401.
+func (v *Verifier) GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := v.Verify(c.Request.Context(), jwtauth.TokenFromHeader(c.Request))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithClaims(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+
+// ServiceGinMiddleware authenticates requests of internal callers using the
+// service token from the Authorization header. Requests without a valid
+// service token are aborted with 401, and those whose token was not granted
+// every one of scopes with 403.
+func (v *Verifier) ServiceGinMiddleware(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := v.VerifyService(c.Request.Context(), jwtauth.TokenFromHeader(c.Request), scopes...)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithServiceClaims(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+
+func abortWithError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrSessionRevoked):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    ErrorCodeSessionRevoked,
+			Message: ErrSessionRevoked.Error(),
+		})
+	case errors.Is(err, ErrInsufficientScope):
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    ErrorCodeInsufficientScope,
+			Message: ErrInsufficientScope.Error(),
+		})
+	case errors.Is(err, ErrUnavailable):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Code:    models.ErrorCodeServiceUnavailable,
+			Message: ErrUnavailable.Error(),
+		})
+	default:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    ErrorCodeInvalidAccessToken,
+			Message: ErrInvalidToken.Error(),
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package authverify
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const bearerPrefix = "bearer "
+
+// UnaryServerInterceptor authenticates unary calls using the bearer token from
+// the "authorization" metadata.
+func (v *Verifier) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := v.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates streaming calls using the bearer token
+// from the "authorization" metadata.
+func (v *Verifier) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := v.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (v *Verifier) authenticate(ctx context.Context) (context.Context, error) {
+	claims, err := v.Verify(ctx, TokenFromMetadata(ctx))
+	switch {
+	case err == nil:
+		return WithClaims(ctx, claims), nil
+	case errors.Is(err, ErrUnavailable):
+		return nil, status.Error(codes.Unavailable, ErrUnavailable.Error())
+	case errors.Is(err, ErrSessionRevoked):
+		return nil, status.Error(codes.Unauthenticated, ErrSessionRevoked.Error())
+	default:
+		return nil, status.Error(codes.Unauthenticated, ErrInvalidToken.Error())
+	}
+}
+
+// ScopeRules maps gRPC methods to the scopes a service token must be granted
+// to call them. Keys are either a full method name such as
+// "/auth.v1.ModerationService/BanPlayer" or a service prefix such as
+// "/auth.v1.ModerationService/" covering every method of the service; a
+// method rule takes precedence over the rule of its service.
+type ScopeRules map[string][]string
+
+// lookup returns the scopes required to call fullMethod and whether the
+// method is guarded at all.
+func (r ScopeRules) lookup(fullMethod string) ([]string, bool) {
+	if scopes, ok := r[fullMethod]; ok {
+		return scopes, true
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		scopes, ok := r[fullMethod[:i+1]]
+		return scopes, ok
+	}
+	return nil, false
+}
+
+// ServiceUnaryServerInterceptor authenticates unary calls to the methods
+// guarded by rules using the service token from the "authorization" metadata.
+// Calls to methods rules do not cover are passed through.
+func (v *Verifier) ServiceUnaryServerInterceptor(rules ScopeRules) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		scopes, ok := rules.lookup(info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+		ctx, err := v.authenticateService(ctx, scopes)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ServiceStreamServerInterceptor authenticates streaming calls to the methods
+// guarded by rules using the service token from the "authorization" metadata.
+// Calls to methods rules do not cover are passed through.
+func (v *Verifier) ServiceStreamServerInterceptor(rules ScopeRules) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		scopes, ok := rules.lookup(info.FullMethod)
+		if !ok {
+			return handler(srv, ss)
+		}
+		ctx, err := v.authenticateService(ss.Context(), scopes)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (v *Verifier) authenticateService(ctx context.Context, scopes []string) (context.Context, error) {
+	claims, err := v.VerifyService(ctx, TokenFromMetadata(ctx), scopes...)
+	switch {
+	case err == nil:
+		return WithServiceClaims(ctx, claims), nil
+	case errors.Is(err, ErrUnavailable):
+		return nil, status.Error(codes.Unavailable, ErrUnavailable.Error())
+	case errors.Is(err, ErrInsufficientScope):
+		return nil, status.Error(codes.PermissionDenied, ErrInsufficientScope.Error())
+	default:
+		return nil, status.Error(codes.Unauthenticated, ErrInvalidToken.Error())
+	}
+}
+
+// TokenFromMetadata returns the bearer token from the "authorization" metadata
+// of an incoming call, or an empty string when there is none.
+func TokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, v := range md.Get("authorization") {
+		if len(v) > len(bearerPrefix) && strings.EqualFold(v[:len(bearerPrefix)], bearerPrefix) {
+			return v[len(bearerPrefix):]
+		}
+	}
+	return ""
+}
+
+// authenticatedStream overrides the context of a server stream with one
+// carrying the verified claims.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the verified claims.
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,39 @@
+package authverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	authredis "go-game-backend/services/auth/pkg/redis"
+)
+
+// RedisSessionChecker checks sessions against the session keys the auth
+// service maintains in Redis.
+type RedisSessionChecker struct {
+	cmd redis.Cmdable
+}
+
+// NewRedisSessionChecker creates a RedisSessionChecker using the given client.
+func NewRedisSessionChecker(cmd redis.Cmdable) *RedisSessionChecker {
+	return &RedisSessionChecker{cmd: cmd}
+}
+
+// IsActiveSession reports whether sessionToken is an active session of the user.
+func (c *RedisSessionChecker) IsActiveSession(ctx context.Context, userID int64, sessionToken uuid.UUID) (bool, error) {
+	key := authredis.SessionKey(sessionToken)
+
+	res := c.cmd.HGet(ctx, key, authredis.SessionUserIDField)
+	if errors.Is(res.Err(), redis.Nil) {
+		return false, nil
+	}
+	if err := res.Err(); err != nil {
+		return false, fmt.Errorf("redis: get '%s': %w", key, err)
+	}
+
+	return res.Val() == strconv.FormatInt(userID, 10), nil
+}
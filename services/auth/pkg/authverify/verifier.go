@@ -0,0 +1,194 @@
+// Package authverify verifies access tokens and service tokens issued by the
+// auth service and provides gin middleware and gRPC interceptors for
+// downstream services.
+package authverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+// ErrInvalidToken is returned when an access token is missing, malformed,
+// expired or carries an invalid signature.
+var ErrInvalidToken = errors.New("invalid access token")
+
+// ErrSessionRevoked is returned when the session an access token was issued
+// for has ended or was revoked.
+var ErrSessionRevoked = errors.New("session revoked")
+
+// ErrInsufficientScope is returned when a service token was not granted a
+// scope the call requires.
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+// ErrUnavailable is returned when the session store or the key set cannot be
+// reached.
+var ErrUnavailable = errors.New("auth backend unavailable")
+
+// Claims holds the verified contents of an access token.
+type Claims struct {
+	UserID       int64
+	SessionToken uuid.UUID
+	IssuedAt     time.Time
+	ExpiresAt    time.Time
+}
+
+// ServiceClaims holds the verified contents of a service token issued to an
+// internal caller with the client credentials grant.
+type ServiceClaims struct {
+	ClientID  string
+	Scopes    []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// HasScopes reports whether the token was granted every one of scopes.
+func (c *ServiceClaims) HasScopes(scopes ...string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyProvider returns the keys accepted for token verification. It is
+// implemented by jwks.KeySet for local keys and jwks.Remote for keys
+// published by the auth service.
+type KeyProvider interface {
+	KeySet(ctx context.Context) (jwk.Set, error)
+}
+
+// SessionChecker reports whether a session of a user is still active.
+type SessionChecker interface {
+	IsActiveSession(ctx context.Context, userID int64, sessionToken uuid.UUID) (bool, error)
+}
+
+// Verifier validates access tokens and the sessions they belong to.
+type Verifier struct {
+	keys     KeyProvider
+	sessions SessionChecker
+}
+
+// New creates a Verifier that checks token signatures with keys and session
+// state with sessions.
+func New(keys KeyProvider, sessions SessionChecker) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		sessions: sessions,
+	}
+}
+
+// Verify validates the signature, expiration and issue time of the token and
+// checks that its session has not been revoked.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	jwtToken, err := v.parse(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := parseClaims(jwtToken.PrivateClaims())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	claims.IssuedAt = jwtToken.IssuedAt()
+	claims.ExpiresAt = jwtToken.Expiration()
+
+	active, err := v.sessions.IsActiveSession(ctx, claims.UserID, claims.SessionToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	if !active {
+		return nil, ErrSessionRevoked
+	}
+
+	return claims, nil
+}
+
+// VerifyService validates the signature, expiration and issue time of a
+// service token and checks that it was granted every one of scopes. Service
+// tokens are not bound to a session. Access tokens of users are rejected.
+func (v *Verifier) VerifyService(ctx context.Context, token string, scopes ...string) (*ServiceClaims, error) {
+	jwtToken, err := v.parse(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := parseServiceClaims(jwtToken.PrivateClaims())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	claims.IssuedAt = jwtToken.IssuedAt()
+	claims.ExpiresAt = jwtToken.Expiration()
+
+	if !claims.HasScopes(scopes...) {
+		return nil, ErrInsufficientScope
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) parse(ctx context.Context, token string) (jwt.Token, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: no token found", ErrInvalidToken)
+	}
+
+	keySet, err := v.keys.KeySet(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	// Parsing validates the signature against the key matching the token's
+	// "kid" along with the exp, iat and nbf claims.
+	jwtToken, err := jwt.ParseString(token, jwt.WithKeySet(keySet), jwt.WithValidate(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return jwtToken, nil
+}
+
+func parseClaims(private map[string]any) (*Claims, error) {
+	// JSON numbers are decoded as float64.
+	userID, ok := private["userID"].(float64)
+	if !ok {
+		return nil, errors.New("missing userID claim")
+	}
+
+	session, ok := private["session"].(string)
+	if !ok {
+		return nil, errors.New("missing session claim")
+	}
+	sessionToken, err := uuid.Parse(session)
+	if err != nil {
+		return nil, fmt.Errorf("parse session claim: %w", err)
+	}
+
+	return &Claims{
+		UserID:       int64(userID),
+		SessionToken: sessionToken,
+	}, nil
+}
+
+func parseServiceClaims(private map[string]any) (*ServiceClaims, error) {
+	clientID, ok := private["client_id"].(string)
+	if !ok || clientID == "" {
+		return nil, errors.New("missing client_id claim")
+	}
+
+	scope, ok := private["scope"].(string)
+	if !ok {
+		return nil, errors.New("missing scope claim")
+	}
+
+	return &ServiceClaims{
+		ClientID: clientID,
+		Scopes:   strings.Fields(scope),
+	}, nil
+}
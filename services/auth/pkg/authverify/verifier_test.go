@@ -0,0 +1,178 @@
+package authverify
+
+import (
+	"context"
+	"errors"
+	"go-game-backend/pkg/jwks"
+	"go-game-backend/pkg/jwtfactory"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeSessions struct {
+	active uuid.UUID
+	err    error
+}
+
+func (f *fakeSessions) IsActiveSession(_ context.Context, _ int64, sessionToken uuid.UUID) (bool, error) {
+	return f.active == sessionToken, f.err
+}
+
+func TestVerify(t *testing.T) {
+	keys, err := jwks.Load(&jwks.Config{Algorithm: "HS256", Secret: "secret"})
+	if err != nil {
+		t.Fatalf("load keys: %v", err)
+	}
+	otherKeys, err := jwks.Load(&jwks.Config{Algorithm: "HS256", Secret: "other"})
+	if err != nil {
+		t.Fatalf("load other keys: %v", err)
+	}
+	session := uuid.New()
+	now := time.Now()
+
+	generate := func(t *testing.T, f *jwtfactory.Factory, ttl time.Duration, issueTime time.Time) string {
+		t.Helper()
+		tkn, _, err := f.Generate(ttl, issueTime, map[string]any{"userID": int64(42), "session": session})
+		if err != nil {
+			t.Fatalf("generate token: %v", err)
+		}
+		return tkn
+	}
+
+	type tc struct {
+		name     string
+		token    string
+		sessions *fakeSessions
+		wantErr  error
+	}
+
+	tests := []tc{
+		{
+			name:     "valid token",
+			token:    generate(t, jwtfactory.New(keys), time.Minute, now),
+			sessions: &fakeSessions{active: session},
+		},
+		{
+			name:     "missing token",
+			token:    "",
+			sessions: &fakeSessions{active: session},
+			wantErr:  ErrInvalidToken,
+		},
+		{
+			name:     "expired token",
+			token:    generate(t, jwtfactory.New(keys), time.Minute, now.Add(-time.Hour)),
+			sessions: &fakeSessions{active: session},
+			wantErr:  ErrInvalidToken,
+		},
+		{
+			name:     "issued in the future",
+			token:    generate(t, jwtfactory.New(keys), 2*time.Hour, now.Add(time.Hour)),
+			sessions: &fakeSessions{active: session},
+			wantErr:  ErrInvalidToken,
+		},
+		{
+			name:     "foreign signature",
+			token:    generate(t, jwtfactory.New(otherKeys), time.Minute, now),
+			sessions: &fakeSessions{active: session},
+			wantErr:  ErrInvalidToken,
+		},
+		{
+			name:     "superseded session",
+			token:    generate(t, jwtfactory.New(keys), time.Minute, now),
+			sessions: &fakeSessions{active: uuid.New()},
+			wantErr:  ErrSessionRevoked,
+		},
+		{
+			name:     "session store down",
+			token:    generate(t, jwtfactory.New(keys), time.Minute, now),
+			sessions: &fakeSessions{err: errors.New("connection refused")},
+			wantErr:  ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := New(keys, tt.sessions).Verify(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error: %v", err)
+			}
+			if claims.UserID != 42 || claims.SessionToken != session {
+				t.Fatalf("unexpected claims: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerifyService(t *testing.T) {
+	keys, err := jwks.Load(&jwks.Config{Algorithm: "HS256", Secret: "secret"})
+	if err != nil {
+		t.Fatalf("load keys: %v", err)
+	}
+	factory := jwtfactory.New(keys)
+
+	generate := func(t *testing.T, claims map[string]any) string {
+		t.Helper()
+		tkn, _, err := factory.Generate(time.Minute, time.Now(), claims)
+		if err != nil {
+			t.Fatalf("generate token: %v", err)
+		}
+		return tkn
+	}
+	serviceToken := generate(t, map[string]any{"client_id": "game-server", "scope": "auth.introspect auth.moderation"})
+
+	type tc struct {
+		name    string
+		token   string
+		scopes  []string
+		wantErr error
+	}
+
+	tests := []tc{
+		{
+			name:   "granted scopes",
+			token:  serviceToken,
+			scopes: []string{"auth.moderation", "auth.introspect"},
+		},
+		{
+			name:  "no scopes required",
+			token: serviceToken,
+		},
+		{
+			name:    "missing scope",
+			token:   serviceToken,
+			scopes:  []string{"auth.moderation", "auth.audit.read"},
+			wantErr: ErrInsufficientScope,
+		},
+		{
+			name:    "user access token",
+			token:   generate(t, map[string]any{"userID": int64(42), "session": uuid.New()}),
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := New(keys, &fakeSessions{}).VerifyService(context.Background(), tt.token, tt.scopes...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("VerifyService() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyService() error: %v", err)
+			}
+			if claims.ClientID != "game-server" || len(claims.Scopes) != 2 {
+				t.Fatalf("unexpected claims: %+v", claims)
+			}
+		})
+	}
+}
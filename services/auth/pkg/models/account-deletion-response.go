@@ -0,0 +1,7 @@
+package models
+
+// AccountDeletionResponse tells when a scheduled account deletion takes
+// effect. Signing in before then cancels it.
+type AccountDeletionResponse struct {
+	DeleteAfterUnix int64 `json:"delete_after"`
+}
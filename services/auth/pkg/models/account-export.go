@@ -0,0 +1,15 @@
+package models
+
+// AccountExport gathers everything the auth service holds about a user.
+// DeleteAfterUnix is set while a deletion of the account is scheduled.
+type AccountExport struct {
+	UserID          int64          `json:"user_id"`
+	ExportedAtUnix  int64          `json:"exported_at"`
+	DeleteAfterUnix int64          `json:"delete_after,omitempty"`
+	Identities      []Identity     `json:"identities"`
+	Email           *ExportedEmail `json:"email,omitempty"`
+	TOTPEnabled     bool           `json:"totp_enabled"`
+	Bans            []ExportedBan  `json:"bans"`
+	Sessions        []Session      `json:"sessions"`
+	AuditLog        []AuditEntry   `json:"audit_log"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// AdminBanRequest asks support to block a player from signing in. A zero
+// ExpiresAtUnix bans the player permanently. An empty IssuedBy records the
+// operator, or the admin client when no operator is reported.
+type AdminBanRequest struct {
+	Reason        string `json:"reason"     binding:"required,max=1000"`
+	IssuedBy      string `json:"issued_by"  binding:"max=200"`
+	ExpiresAtUnix int64  `json:"expires_at" binding:"omitempty,gt=0"`
+}
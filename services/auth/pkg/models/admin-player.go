@@ -0,0 +1,15 @@
+package models
+
+// AdminPlayer is what support sees when looking up a player through the
+// admin API. DeleteAfterUnix is set while a deletion of the account is
+// scheduled; ActiveBan is set while a ban is in effect.
+type AdminPlayer struct {
+	UserID                  int64          `json:"user_id"`
+	DeletionRequestedAtUnix int64          `json:"deletion_requested_at,omitempty"`
+	DeleteAfterUnix         int64          `json:"delete_after,omitempty"`
+	Identities              []Identity     `json:"identities"`
+	Email                   *ExportedEmail `json:"email,omitempty"`
+	TOTPEnabled             bool           `json:"totp_enabled"`
+	ActiveBan               *ExportedBan   `json:"active_ban,omitempty"`
+	Sessions                []Session      `json:"sessions"`
+}
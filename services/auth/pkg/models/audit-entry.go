@@ -0,0 +1,41 @@
+package models
+
+import "encoding/json"
+
+// Audit events recorded in AuditEntry.Event. Identity changes and security
+// events are recorded under their IdentityEvent* and SecurityEvent* types.
+const (
+	AuditEventRegistered         = "registered"
+	AuditEventLoginSucceeded     = "login_succeeded"
+	AuditEventLoginFailed        = "login_failed"
+	AuditEventMFAChallenged      = "mfa_challenged"
+	AuditEventTokenRefreshed     = "token_refreshed"
+	AuditEventLoggedOut          = "logged_out"
+	AuditEventLoggedOutAll       = "logged_out_all"
+	AuditEventSessionRevoked     = "session_revoked"
+	AuditEventPasswordReset      = "password_reset"
+	AuditEventPlayerBanned       = "player_banned"
+	AuditEventPlayerUnbanned     = "player_unbanned"
+	AuditEventDeletionRequested  = "deletion_requested"
+	AuditEventDeletionCancelled  = "deletion_cancelled"
+	AuditEventPlayerDeleted      = "player_deleted"
+	AuditEventPlayerViewed       = "player_viewed"
+	AuditEventSessionsRevoked    = "sessions_revoked"
+	AuditEventLoginTokenReissued = "login_token_reissued"
+	AuditEventLoginTokenRotated  = "login_token_rotated"
+	AuditEventTransferCodeIssued = "transfer_code_issued"
+	AuditEventAccountTransferred = "account_transferred"
+)
+
+// AuditEntry is a security-relevant action recorded in the audit log. IP and
+// UserAgent describe the client that made the request; Details holds event
+// specific fields.
+type AuditEntry struct {
+	ID            int64           `json:"id"`
+	UserID        int64           `json:"user_id"`
+	Event         string          `json:"event"`
+	IP            string          `json:"ip,omitempty"`
+	UserAgent     string          `json:"user_agent,omitempty"`
+	Details       json.RawMessage `json:"details"`
+	CreatedAtUnix int64           `json:"created_at"`
+}
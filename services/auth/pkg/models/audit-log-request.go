@@ -0,0 +1,11 @@
+package models
+
+// AuditLogRequest asks for the audit log of a player within [FromUnix,
+// ToUnix). A zero ToUnix means now; a zero Limit returns the default number
+// of entries.
+type AuditLogRequest struct {
+	UserID   int64 `json:"user_id" binding:"required,gt=0"`
+	FromUnix int64 `json:"from"    binding:"omitempty,gte=0"`
+	ToUnix   int64 `json:"to"      binding:"omitempty,gtfield=FromUnix"`
+	Limit    int   `json:"limit"   binding:"omitempty,gt=0,lte=1000"`
+}
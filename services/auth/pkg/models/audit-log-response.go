@@ -0,0 +1,6 @@
+package models
+
+// AuditLogResponse lists audit entries, newest first.
+type AuditLogResponse struct {
+	Entries []AuditEntry `json:"entries"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// BanPlayerRequest asks to block a player from signing in. A zero
+// ExpiresAtUnix bans the player permanently.
+type BanPlayerRequest struct {
+	UserID        int64  `json:"user_id"    binding:"required,gt=0"`
+	Reason        string `json:"reason"     binding:"required,max=1000"`
+	IssuedBy      string `json:"issued_by"  binding:"required,max=200"`
+	ExpiresAtUnix int64  `json:"expires_at" binding:"omitempty,gt=0"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// BanPlayerResponse identifies the ban created by a BanPlayerRequest.
+type BanPlayerResponse struct {
+	BanID int64 `json:"ban_id"`
+}
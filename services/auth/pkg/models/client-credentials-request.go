@@ -0,0 +1,16 @@
+package models
+
+// GrantTypeClientCredentials is the only grant accepted by the token
+// endpoint of internal callers.
+const GrantTypeClientCredentials = "client_credentials"
+
+// ClientCredentialsRequest asks for a service token with the OAuth 2.0
+// client credentials grant. The client may authenticate with HTTP Basic
+// instead of ClientID and ClientSecret. An empty Scope asks for every scope
+// the client is allowed.
+type ClientCredentialsRequest struct {
+	GrantType    string `json:"grant_type" form:"grant_type"`
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	Scope        string `json:"scope" form:"scope"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "strings"
+
+// EmailCredentialsRequest carries an email and password, used to register,
+// log in and link an email to the current user.
+type EmailCredentialsRequest struct {
+	Email    string `json:"email"    binding:"required,email,max=254"`
+	Password string `json:"password" binding:"required,min=8,max=128"`
+}
+
+// NormalizeEmail returns the form emails are stored and looked up in.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
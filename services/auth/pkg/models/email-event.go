@@ -0,0 +1,16 @@
+package models
+
+// Email event types published in EmailEvent.Type.
+const (
+	EmailEventVerificationRequested  = "email_verification_requested"
+	EmailEventPasswordResetRequested = "password_reset_requested"
+)
+
+// EmailEvent asks the mailer to deliver a single-use token to a player.
+type EmailEvent struct {
+	Type          string `json:"type"`
+	UserID        int64  `json:"user_id"`
+	Email         string `json:"email"`
+	Token         string `json:"token"`
+	ExpiresAtUnix int64  `json:"expires_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+// Error codes returned in ErrorResponse.Code.
+const (
+	ErrorCodeInvalidRequest      = "invalid_request"
+	ErrorCodeInvalidCredentials  = "invalid_credentials"
+	ErrorCodeLoginTokenTaken     = "login_token_taken"
+	ErrorCodeEmailTaken          = "email_taken"
+	ErrorCodeEmailAlreadyLinked  = "email_already_linked"
+	ErrorCodeEmailNotVerified    = "email_not_verified"
+	ErrorCodeInvalidEmailToken   = "invalid_email_token"
+	ErrorCodeUnknownProvider     = "unknown_provider"
+	ErrorCodeIdentityTaken       = "identity_taken"
+	ErrorCodeIdentityNotFound    = "identity_not_found"
+	ErrorCodeLastIdentity        = "last_identity"
+	ErrorCodeMergeNotAllowed     = "merge_not_allowed"
+	ErrorCodeMFAAlreadyEnabled   = "mfa_already_enabled"
+	ErrorCodeMFANotEnrolled      = "mfa_not_enrolled"
+	ErrorCodeInvalidMFACode      = "invalid_mfa_code"
+	ErrorCodeInvalidMFAToken     = "invalid_mfa_token"
+	ErrorCodeInvalidTransferCode = "invalid_transfer_code"
+	ErrorCodeSessionNotFound     = "session_not_found"
+	ErrorCodeInvalidRefreshToken = "invalid_refresh_token"
+	ErrorCodeRefreshTokenReused  = "refresh_token_reused"
+	ErrorCodePlayerBanned        = "player_banned"
+	ErrorCodePlayerNotFound      = "player_not_found"
+	ErrorCodePlayerNotBanned     = "player_not_banned"
+	ErrorCodeBanExpired          = "ban_expired"
+	ErrorCodePlayerLocked        = "player_locked"
+	ErrorCodeInvalidClient       = "invalid_client"
+	ErrorCodeInvalidScope        = "invalid_scope"
+	ErrorCodeUnsupportedGrant    = "unsupported_grant_type"
+	ErrorCodeServiceUnavailable  = "service_unavailable"
+	ErrorCodeTimeout             = "timeout"
+	ErrorCodeInternal            = "internal_error"
+)
+
+// ErrorResponse is the body returned by the auth API for failed requests.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
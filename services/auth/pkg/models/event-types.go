@@ -0,0 +1,18 @@
+package models
+
+// Event types sent in the event type header of the events the auth service
+// publishes, naming the payload of the event.
+const (
+	EventTypeUserCreated       = "user_created"
+	EventTypeUserDeleted       = "user_deleted"
+	EventTypePlayerBanned      = "player_banned"
+	EventTypeEmail             = "email"
+	EventTypeIdentitiesChanged = "identities_changed"
+	EventTypeSecurity          = "security"
+	EventTypeSessionReplaced   = "session_replaced"
+)
+
+// EventSchemaVersion is the version of the event payloads, sent in the
+// schema version header of the events. It changes when a payload changes in
+// a way older consumers cannot read.
+const EventSchemaVersion = "1"
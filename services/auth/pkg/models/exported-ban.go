@@ -0,0 +1,12 @@
+package models
+
+// ExportedBan is a ban of a user in an AccountExport or an AdminPlayer. Zero
+// ExpiresAtUnix means the ban is permanent; zero LiftedAtUnix that it was not
+// lifted.
+type ExportedBan struct {
+	ID            int64  `json:"id"`
+	Reason        string `json:"reason"`
+	CreatedAtUnix int64  `json:"created_at"`
+	ExpiresAtUnix int64  `json:"expires_at,omitempty"`
+	LiftedAtUnix  int64  `json:"lifted_at,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// ExportedEmail is the email credential of a user in an AccountExport or an
+// AdminPlayer. The password hash is left out.
+type ExportedEmail struct {
+	Email    string `json:"email"`
+	Verified bool   `json:"verified"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// Identity event types published in IdentitiesChangedEvent.Type.
+const (
+	IdentityEventLinked   = "identity_linked"
+	IdentityEventUnlinked = "identity_unlinked"
+	IdentityEventMerged   = "players_merged"
+)
+
+// IdentitiesChangedEvent represents payload for events published when the set
+// of credentials of a user changes. For merges, MergedUserID is the guest
+// user that was merged into UserID and no longer exists.
+type IdentitiesChangedEvent struct {
+	Type         string `json:"type"`
+	UserID       int64  `json:"user_id"`
+	Provider     string `json:"provider,omitempty"`
+	MergedUserID int64  `json:"merged_user_id,omitempty"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// IdentitiesResponse lists the identities of the user.
+type IdentitiesResponse struct {
+	Identities []Identity `json:"identities"`
+}
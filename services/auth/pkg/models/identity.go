@@ -0,0 +1,20 @@
+package models
+
+// Identity providers of the credentials built into the auth service.
+const (
+	IdentityProviderGuest = "guest"
+	IdentityProviderEmail = "email"
+)
+
+// OIDCIdentityProvider returns the identity provider under which accounts of
+// the named OpenID Connect provider are stored.
+func OIDCIdentityProvider(name string) string {
+	return "oidc:" + name
+}
+
+// Identity describes a credential the user can sign in with.
+type Identity struct {
+	Provider      string `json:"provider"`
+	Subject       string `json:"subject"`
+	CreatedAtUnix int64  `json:"created_at"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// IntrospectRequest represents a request to inspect an access token.
+type IntrospectRequest struct {
+	AccessToken string `json:"access_token"`
+}
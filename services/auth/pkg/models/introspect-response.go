@@ -0,0 +1,14 @@
+package models
+
+import "github.com/google/uuid"
+
+// IntrospectResponse describes an access token. Active is false when the
+// token is invalid, expired or its session has ended or was revoked; the
+// remaining fields are only set for tokens with a valid signature.
+type IntrospectResponse struct {
+	Active        bool      `json:"active"`
+	UserID        int64     `json:"user_id,omitempty"`
+	SessionToken  uuid.UUID `json:"session_token,omitzero"`
+	IssuedAtUnix  int64     `json:"issued_at,omitempty"`
+	ExpiresAtUnix int64     `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "github.com/google/uuid"
+
+// LinkGuestRequest carries the login token of a guest account, used to link a
+// device to the current user or to merge the guest account into it.
+type LinkGuestRequest struct {
+	LoginToken uuid.UUID `json:"login_token" binding:"required"`
+}
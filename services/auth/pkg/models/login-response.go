@@ -3,8 +3,14 @@ package models
 import "github.com/google/uuid"
 
 // LoginRespose contains tokens returned after a successful authentication.
+//
+// When the user enabled two-factor authentication, a login returns only
+// MFAChallenge instead. The tokens are issued once its token is presented
+// together with a second factor.
 type LoginRespose struct {
-	AccessToken   string    `json:"access_token"`
-	RefreshToken  uuid.UUID `json:"refresh_token"`
-	ExpiresAtUnix int64     `json:"expires_at"`
+	AccessToken  string    `json:"access_token,omitempty"`
+	RefreshToken uuid.UUID `json:"refresh_token,omitzero"`
+	// ExpiresAtUnix is the expiration of the access token.
+	ExpiresAtUnix int64                 `json:"expires_at,omitempty"`
+	MFAChallenge  *MFAChallengeResponse `json:"mfa_challenge,omitempty"`
 }
@@ -0,0 +1,9 @@
+package models
+
+import "github.com/google/uuid"
+
+// LoginTokenResponse contains a guest login token issued to a player. It is
+// shown once and must be handed to the player to sign in with.
+type LoginTokenResponse struct {
+	LoginToken uuid.UUID `json:"login_token"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "github.com/google/uuid"
+
+// LogoutRequest represents a request to end the session the refresh token
+// belongs to.
+type LogoutRequest struct {
+	RefreshToken uuid.UUID `json:"refresh_token"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// MFAChallengeResponse asks for a second factor to complete a login.
+type MFAChallengeResponse struct {
+	// MFAToken is presented together with the second factor.
+	MFAToken string `json:"mfa_token"`
+	// ExpiresAtUnix is the expiration of the challenge.
+	ExpiresAtUnix int64 `json:"expires_at"`
+}
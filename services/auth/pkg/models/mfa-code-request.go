@@ -0,0 +1,8 @@
+package models
+
+// MFACodeRequest carries a second factor: either a TOTP code or a recovery
+// code.
+type MFACodeRequest struct {
+	Code         string `json:"code"          binding:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code,omitempty,max=64"`
+}
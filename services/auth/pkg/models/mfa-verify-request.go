@@ -0,0 +1,7 @@
+package models
+
+// MFAVerifyRequest completes a login that returned an MFA token.
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	MFACodeRequest
+}
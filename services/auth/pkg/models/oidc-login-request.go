@@ -0,0 +1,8 @@
+package models
+
+// OIDCLoginRequest carries an ID token issued by an external OpenID Connect
+// provider, used to sign in and to link the provider account.
+type OIDCLoginRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	IDToken  string `json:"id_token" binding:"required"`
+}
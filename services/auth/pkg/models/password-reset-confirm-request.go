@@ -0,0 +1,7 @@
+package models
+
+// PasswordResetConfirmRequest sets a new password using a reset token.
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token"        binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=128"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// PasswordResetRequest asks for a password reset token to be sent to an email.
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
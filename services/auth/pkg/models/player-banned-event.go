@@ -0,0 +1,11 @@
+package models
+
+// PlayerBannedEvent represents payload for the event published when a
+// player is banned. ExpiresAtUnix is zero for permanent bans.
+type PlayerBannedEvent struct {
+	UserID        int64  `json:"user_id"`
+	Reason        string `json:"reason"`
+	IssuedBy      string `json:"issued_by"`
+	BannedAtUnix  int64  `json:"banned_at"`
+	ExpiresAtUnix int64  `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// RecoveryCodesResponse lists one-time recovery codes that can be used
+// instead of a TOTP code when the authenticator app is lost. The codes are
+// shown only once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// RedeemTransferCodeRequest moves the account a transfer code was generated
+// for to the device making the request. Codes are matched regardless of
+// case, dashes and spaces.
+type RedeemTransferCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
@@ -0,0 +1,8 @@
+package models
+
+import "github.com/google/uuid"
+
+// RevokeSessionRequest asks to end a session of the current user.
+type RevokeSessionRequest struct {
+	SessionToken uuid.UUID `json:"session_token" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "github.com/google/uuid"
+
+// RotateLoginTokenRequest carries the guest login token of the current user
+// to be replaced with a new one.
+type RotateLoginTokenRequest struct {
+	LoginToken uuid.UUID `json:"login_token" binding:"required"`
+}
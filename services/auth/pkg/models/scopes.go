@@ -0,0 +1,14 @@
+package models
+
+// Scopes granted to service clients and required by the internal endpoints
+// of the auth service.
+const (
+	// ScopeIntrospect allows introspecting access tokens of users.
+	ScopeIntrospect = "auth.introspect"
+	// ScopeModeration allows banning and unbanning players.
+	ScopeModeration = "auth.moderation"
+	// ScopeAuditRead allows reading the audit log of players.
+	ScopeAuditRead = "auth.audit.read"
+	// ScopeAdmin allows using the admin API of support tooling.
+	ScopeAdmin = "auth.admin"
+)
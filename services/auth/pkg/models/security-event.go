@@ -0,0 +1,19 @@
+package models
+
+import "github.com/google/uuid"
+
+// Security event types published in SecurityEvent.Type.
+const (
+	SecurityEventRefreshTokenReused = "refresh_token_reused"
+	SecurityEventMFAEnabled         = "mfa_enabled"
+	SecurityEventMFADisabled        = "mfa_disabled"
+)
+
+// SecurityEvent represents payload for security-relevant events detected by
+// the auth service.
+type SecurityEvent struct {
+	Type           string    `json:"type"`
+	UserID         int64     `json:"user_id"`
+	SessionToken   uuid.UUID `json:"session_token"`
+	OccurredAtUnix int64     `json:"occurred_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// ServiceTokenResponse contains a service token issued to an internal
+// caller, in the shape of an OAuth 2.0 token response.
+type ServiceTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// ExpiresIn is the lifetime of the token in seconds.
+	ExpiresIn int64  `json:"expires_in"`
+	Scope     string `json:"scope"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "github.com/google/uuid"
+
+// SessionReplacedEvent represents payload for the event published when a
+// session is ended because the user signed in on another device. Servers
+// holding a connection for SessionToken should disconnect it.
+type SessionReplacedEvent struct {
+	UserID         int64     `json:"user_id"`
+	SessionToken   uuid.UUID `json:"session_token"`
+	ReplacedBy     uuid.UUID `json:"replaced_by"`
+	OccurredAtUnix int64     `json:"occurred_at"`
+}
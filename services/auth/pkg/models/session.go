@@ -0,0 +1,17 @@
+package models
+
+import "github.com/google/uuid"
+
+// Session describes an active session of a user and the device it was
+// started on. Current marks the session of the access token used to list
+// the sessions.
+type Session struct {
+	SessionToken   uuid.UUID `json:"session_token"`
+	Platform       string    `json:"platform,omitempty"`
+	AppVersion     string    `json:"app_version,omitempty"`
+	IP             string    `json:"ip,omitempty"`
+	CreatedAtUnix  int64     `json:"created_at"`
+	LastUsedAtUnix int64     `json:"last_used_at"`
+	ExpiresAtUnix  int64     `json:"expires_at"`
+	Current        bool      `json:"current"`
+}
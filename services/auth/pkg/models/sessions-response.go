@@ -0,0 +1,6 @@
+package models
+
+// SessionsResponse lists the active sessions of the current user.
+type SessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
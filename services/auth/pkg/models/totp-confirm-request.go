@@ -0,0 +1,7 @@
+package models
+
+// TOTPConfirmRequest activates an enrolled TOTP secret with a code produced
+// by the authenticator app.
+type TOTPConfirmRequest struct {
+	Code string `json:"code" binding:"required,numeric,len=6"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// TOTPEnrollResponse carries a new TOTP secret to be added to an
+// authenticator app, either typed in or scanned from the provisioning URI
+// rendered as a QR code.
+type TOTPEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
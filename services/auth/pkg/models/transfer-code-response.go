@@ -0,0 +1,8 @@
+package models
+
+// TransferCodeResponse carries a short-lived code the player types in on
+// another device to move the account there.
+type TransferCodeResponse struct {
+	Code          string `json:"code"`
+	ExpiresAtUnix int64  `json:"expires_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "github.com/google/uuid"
+
+// TransferResponse contains the new guest login token of an account moved
+// to another device, together with the outcome of signing in with it. The
+// sign-in fields are empty when signing in failed after the account was
+// moved; the device then logs in with the login token.
+type TransferResponse struct {
+	LoginToken uuid.UUID `json:"login_token"`
+	LoginRespose
+}
@@ -0,0 +1,8 @@
+package models
+
+// UnlinkIdentityRequest identifies a credential to remove from the current
+// user.
+type UnlinkIdentityRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	Subject  string `json:"subject"  binding:"required"`
+}
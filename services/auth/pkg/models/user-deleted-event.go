@@ -0,0 +1,9 @@
+package models
+
+// UserDeletedEvent represents payload for user-deleted events, published
+// once the account is gone for good. Services keeping data about the user
+// must purge it.
+type UserDeletedEvent struct {
+	UserID        int64 `json:"user_id"`
+	DeletedAtUnix int64 `json:"deleted_at"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// VerifyEmailRequest confirms ownership of an email with the token sent to it.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
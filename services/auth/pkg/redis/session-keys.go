@@ -0,0 +1,26 @@
+// Package redis contains Redis key helpers shared between the auth service and
+// services verifying its tokens.
+package redis
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SessionKey creates the key of the hash describing a session. The hash
+// holds the owner in the SessionUserIDField field and exists for as long as
+// the session is active.
+func SessionKey(sessionToken uuid.UUID) string {
+	return fmt.Sprintf("session:%s", sessionToken)
+}
+
+// SessionUserIDField is the field of the session hash holding the ID of the
+// user the session belongs to.
+const SessionUserIDField = "user_id"
+
+// UserSessionsKey creates the key of the sorted set indexing the sessions of
+// a user by creation time.
+func UserSessionsKey(userID int64) string {
+	return fmt.Sprintf("user_sessions:%v", userID)
+}
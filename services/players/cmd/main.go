@@ -4,9 +4,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"go-game-backend/pkg/jwks"
 	"go-game-backend/pkg/kafka"
 	"go-game-backend/pkg/logging"
 	"go-game-backend/pkg/service"
+	"go-game-backend/services/auth/pkg/authverify"
 	playerkafka "go-game-backend/services/players/internal/ingester/kafka"
 	"log"
 	"net/http"
@@ -15,6 +17,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	redisstore "go-game-backend/pkg/redis"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -24,6 +28,8 @@ type Config struct {
 	Service         *service.Config           `yaml:"service"`
 	HTTP            *service.HTTPServerConfig `yaml:"http"`
 	Kafka           *kafka.ReaderConfig       `yaml:"kafka"`
+	Redis           *redisstore.Config        `yaml:"redis"`
+	JWKS            *jwks.RemoteConfig        `yaml:"jwks"`
 	ShutdownTimeout time.Duration             `yaml:"shutdown-timeout"`
 }
 
@@ -62,6 +68,14 @@ func run(ctx context.Context, cfg *Config, logger *logging.ZapLogger) error {
 	defer service.Close(ctx, reader, "kafka reader", logger)
 	ing := playerkafka.NewUserCreated(reader, logger)
 
+	rxStorage := redisstore.New(cfg.Redis, logger, authverify.NewRedisSessionChecker)
+	defer service.Stop(ctx, rxStorage, "redis storage", logger)
+	keys, err := jwks.NewRemote(ctx, cfg.JWKS)
+	if err != nil {
+		return fmt.Errorf("failed to create jwks client: %w", err)
+	}
+	verifier := authverify.New(keys, rxStorage.Raw())
+
 	serv := service.NewBuilder().
 		WithGo(func(ctx context.Context) error {
 			if err := ing.Run(ctx); err != nil {
@@ -72,7 +86,7 @@ func run(ctx context.Context, cfg *Config, logger *logging.ZapLogger) error {
 		WithHTTPServer(cfg.HTTP, func() http.Handler {
 			router := gin.Default()
 
-			api := router.Group("/api/v1")
+			api := router.Group("/api/v1", verifier.GinMiddleware())
 			{
 				_ = api
 			}